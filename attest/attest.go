@@ -0,0 +1,172 @@
+// Package attest verifies that job results were produced by trusted workers running in a trusted execution environment.
+//
+// A worker signs the digest of every job result with a key generated inside its enclave and publishes Evidence:
+// the signature, the public key and an attestation quote binding the key to the enclave measurement. A Verifier
+// accepts the result if the key is trusted directly, or if an Attester verifies the quote and its measurement is trusted.
+package attest
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrVerification is wrapped by every verification failure
+	ErrVerification = errors.New("attestation verification failed")
+	// ErrNoEvidence is returned for results without evidence
+	ErrNoEvidence = fmt.Errorf("%w: no evidence", ErrVerification)
+	// ErrResultMismatch is returned if the evidence was issued for another job or result
+	ErrResultMismatch = fmt.Errorf("%w: evidence does not match the result", ErrVerification)
+	// ErrInvalidSignature is returned if the result signature does not verify with the worker key
+	ErrInvalidSignature = fmt.Errorf("%w: invalid signature", ErrVerification)
+	// ErrInvalidQuote is returned if the attestation quote does not verify or does not attest the worker key
+	ErrInvalidQuote = fmt.Errorf("%w: invalid quote", ErrVerification)
+	// ErrUntrusted is returned if neither the worker key nor its attested measurement is trusted
+	ErrUntrusted = fmt.Errorf("%w: untrusted worker", ErrVerification)
+)
+
+// Evidence is the attestation evidence of a job result
+type Evidence struct {
+	JobUUID      string `json:"job_uuid"`
+	ResultDigest []byte `json:"result_digest"` // SHA-256 of the JSON result
+	PublicKey    []byte `json:"public_key"`    // Ed25519 key of the worker
+	Signature    []byte `json:"signature"`     // Signature of SignedMessage by the worker key
+	Quote        []byte `json:"quote,omitempty"`
+}
+
+// Report is the content of a verified attestation quote
+type Report struct {
+	Measurement string // Hex encoded enclave measurement, e.g. MRENCLAVE
+	ReportData  []byte // Data bound into the quote by the enclave, starting with KeyBinding of the worker key
+}
+
+// Attester verifies attestation quotes of a TEE platform, e.g. Intel SGX DCAP quotes
+type Attester interface {
+	VerifyQuote(ctx context.Context, quote []byte) (Report, error)
+}
+
+// Digest returns the digest of a JSON result, ignoring surrounding whitespace
+func Digest(result []byte) []byte {
+	sum := sha256.Sum256(bytes.TrimSpace(result))
+	return sum[:]
+}
+
+// SignedMessage returns the message a worker signs for a job result
+func SignedMessage(jobUUID string, digest []byte) []byte {
+	return []byte("gopher-attestation-v1\n" + jobUUID + "\n" + hex.EncodeToString(digest))
+}
+
+// KeyBinding returns the report data binding a worker key to its quote
+func KeyBinding(publicKey ed25519.PublicKey) []byte {
+	sum := sha256.Sum256(publicKey)
+	return sum[:]
+}
+
+// Verifier verifies the evidence of job results
+type Verifier struct {
+	keys         []ed25519.PublicKey
+	measurements map[string]bool
+	attester     Attester
+}
+
+// Option configures a Verifier
+type Option func(*Verifier) error
+
+// TrustedKeys trusts results signed by the worker keys, without requiring a quote
+func TrustedKeys(keys ...ed25519.PublicKey) Option {
+	return func(v *Verifier) error {
+		for _, key := range keys {
+			if len(key) != ed25519.PublicKeySize {
+				return fmt.Errorf("invalid trusted key of %d bytes", len(key))
+			}
+		}
+		v.keys = append(v.keys, keys...)
+		return nil
+	}
+}
+
+// TrustedMeasurements trusts results of workers whose quote attests one of the hex encoded measurements.
+// Requires an Attester.
+func TrustedMeasurements(measurements ...string) Option {
+	return func(v *Verifier) error {
+		for _, measurement := range measurements {
+			if _, err := hex.DecodeString(measurement); err != nil {
+				return fmt.Errorf("invalid trusted measurement %q: %w", measurement, err)
+			}
+			v.measurements[strings.ToLower(measurement)] = true
+		}
+		return nil
+	}
+}
+
+// WithAttester sets the Attester verifying the quotes of workers
+func WithAttester(attester Attester) Option {
+	return func(v *Verifier) error {
+		v.attester = attester
+		return nil
+	}
+}
+
+// NewVerifier creates a Verifier trusting the configured keys and measurements
+func NewVerifier(opts ...Option) (*Verifier, error) {
+	v := &Verifier{measurements: make(map[string]bool)}
+	for _, opt := range opts {
+		if err := opt(v); err != nil {
+			return nil, err
+		}
+	}
+	if len(v.measurements) > 0 && v.attester == nil {
+		return nil, errors.New("trusted measurements require an attester")
+	}
+	if len(v.keys) == 0 && len(v.measurements) == 0 {
+		return nil, errors.New("no trusted keys or measurements")
+	}
+	return v, nil
+}
+
+// Verify verifies that result is the result of the job jobUUID, signed by a trusted worker
+func (v *Verifier) Verify(ctx context.Context, jobUUID string, result []byte, evidence *Evidence) error {
+	if evidence == nil {
+		return ErrNoEvidence
+	}
+	digest := Digest(result)
+	if evidence.JobUUID != jobUUID || !bytes.Equal(evidence.ResultDigest, digest) {
+		return ErrResultMismatch
+	}
+
+	key := ed25519.PublicKey(evidence.PublicKey)
+	if len(key) != ed25519.PublicKeySize || !ed25519.Verify(key, SignedMessage(jobUUID, digest), evidence.Signature) {
+		return ErrInvalidSignature
+	}
+	return v.trust(ctx, key, evidence.Quote)
+}
+
+// trust checks that key is trusted directly or attested by a quote with a trusted measurement
+func (v *Verifier) trust(ctx context.Context, key ed25519.PublicKey, quote []byte) error {
+	for _, trusted := range v.keys {
+		if trusted.Equal(key) {
+			return nil
+		}
+	}
+	if v.attester == nil || len(quote) == 0 {
+		return ErrUntrusted
+	}
+
+	report, err := v.attester.VerifyQuote(ctx, quote)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidQuote, err)
+	}
+	if !bytes.HasPrefix(report.ReportData, KeyBinding(key)) {
+		return fmt.Errorf("%w: quote does not attest the worker key", ErrInvalidQuote)
+	}
+	if !v.measurements[strings.ToLower(report.Measurement)] {
+		return fmt.Errorf("%w: measurement %s", ErrUntrusted, report.Measurement)
+	}
+	return nil
+}
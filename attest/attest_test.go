@@ -0,0 +1,111 @@
+package attest_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+
+	"github.com/gopher-lab/gopher-client/attest"
+	"github.com/gopher-lab/gopher-client/attest/attesttest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const measurement = "a1b2c3d4e5f6"
+
+var _ = Describe("Verifier", func() {
+	var (
+		ctx      context.Context
+		platform *attesttest.Platform
+		enclave  *attesttest.Enclave
+		verifier *attest.Verifier
+		result   []byte
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		platform = attesttest.NewPlatform()
+		enclave = platform.NewEnclave(measurement)
+		result = []byte(`[{"id": "1", "content": "one"}]`)
+
+		var err error
+		verifier, err = attest.NewVerifier(attest.WithAttester(platform.Attester()), attest.TrustedMeasurements("A1B2C3D4E5F6"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should verify results of enclaves with a trusted measurement", func() {
+		Expect(verifier.Verify(ctx, "job-1", result, enclave.Evidence("job-1", result))).To(Succeed())
+	})
+
+	It("should ignore whitespace around the result", func() {
+		Expect(verifier.Verify(ctx, "job-1", append(result, '\n'), enclave.Evidence("job-1", result))).To(Succeed())
+	})
+
+	It("should reject results without evidence", func() {
+		err := verifier.Verify(ctx, "job-1", result, nil)
+		Expect(err).To(MatchError(attest.ErrNoEvidence))
+		Expect(err).To(MatchError(attest.ErrVerification))
+	})
+
+	It("should reject evidence of other jobs or results", func() {
+		Expect(verifier.Verify(ctx, "job-2", result, enclave.Evidence("job-1", result))).To(MatchError(attest.ErrResultMismatch))
+		Expect(verifier.Verify(ctx, "job-1", []byte(`[]`), enclave.Evidence("job-1", result))).To(MatchError(attest.ErrResultMismatch))
+	})
+
+	It("should reject invalid signatures", func() {
+		evidence := enclave.Evidence("job-1", result)
+		evidence.Signature[0] ^= 0xff
+
+		Expect(verifier.Verify(ctx, "job-1", result, evidence)).To(MatchError(attest.ErrInvalidSignature))
+	})
+
+	It("should reject untrusted measurements", func() {
+		untrusted := platform.NewEnclave("ffff")
+
+		err := verifier.Verify(ctx, "job-1", result, untrusted.Evidence("job-1", result))
+		Expect(err).To(MatchError(attest.ErrUntrusted))
+		Expect(err).To(MatchError(ContainSubstring("measurement ffff")))
+	})
+
+	It("should reject quotes of other platforms", func() {
+		forged := attesttest.NewPlatform().NewEnclave(measurement)
+
+		Expect(verifier.Verify(ctx, "job-1", result, forged.Evidence("job-1", result))).To(MatchError(attest.ErrInvalidQuote))
+	})
+
+	It("should reject quotes attesting another key", func() {
+		evidence := enclave.Evidence("job-1", result)
+		other := platform.NewEnclave(measurement).Evidence("job-1", result)
+		evidence.Quote = other.Quote
+
+		Expect(verifier.Verify(ctx, "job-1", result, evidence)).To(MatchError(ContainSubstring("quote does not attest the worker key")))
+	})
+
+	It("should trust keys without a quote", func() {
+		verifier, err := attest.NewVerifier(attest.TrustedKeys(enclave.PublicKey()))
+		Expect(err).NotTo(HaveOccurred())
+
+		evidence := enclave.Evidence("job-1", result)
+		evidence.Quote = nil
+		Expect(verifier.Verify(ctx, "job-1", result, evidence)).To(Succeed())
+
+		other := platform.NewEnclave(measurement)
+		Expect(verifier.Verify(ctx, "job-1", result, other.Evidence("job-1", result))).To(MatchError(attest.ErrUntrusted))
+	})
+
+	It("should require something to trust", func() {
+		_, err := attest.NewVerifier()
+		Expect(err).To(MatchError("no trusted keys or measurements"))
+
+		_, err = attest.NewVerifier(attest.TrustedMeasurements(measurement))
+		Expect(err).To(MatchError("trusted measurements require an attester"))
+
+		_, err = attest.NewVerifier(attest.TrustedMeasurements("not hex"), attest.WithAttester(platform.Attester()))
+		Expect(err).To(HaveOccurred())
+
+		public, _, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = attest.NewVerifier(attest.TrustedKeys(public[:16]))
+		Expect(err).To(MatchError(ContainSubstring("invalid trusted key")))
+	})
+})
@@ -0,0 +1,96 @@
+// Package attesttest provides a fake TEE platform for testing attestation verification without hardware.
+package attesttest
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+
+	"github.com/gopher-lab/gopher-client/attest"
+)
+
+// Platform is a fake TEE platform signing the quotes of its enclaves with a local key
+type Platform struct {
+	key ed25519.PrivateKey
+}
+
+// NewPlatform creates a Platform with a new key
+func NewPlatform() *Platform {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return &Platform{key: key}
+}
+
+// quote is the format of the fake quotes
+type quote struct {
+	Measurement string `json:"measurement"`
+	ReportData  []byte `json:"report_data"`
+	Signature   []byte `json:"signature"`
+}
+
+// Attester returns an attest.Attester accepting the quotes of the platform's enclaves
+func (p *Platform) Attester() attest.Attester {
+	return attester{key: p.key.Public().(ed25519.PublicKey)}
+}
+
+// NewEnclave creates an enclave with the hex encoded measurement and a new worker key attested by the platform
+func (p *Platform) NewEnclave(measurement string) *Enclave {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	q := quote{Measurement: measurement, ReportData: attest.KeyBinding(key.Public().(ed25519.PublicKey))}
+	q.Signature = ed25519.Sign(p.key, q.signed())
+	data, err := json.Marshal(q)
+	if err != nil {
+		panic(err)
+	}
+	return &Enclave{key: key, quote: data}
+}
+
+// Enclave is a fake worker enclave issuing evidence for job results
+type Enclave struct {
+	key   ed25519.PrivateKey
+	quote []byte
+}
+
+// PublicKey returns the worker key of the enclave
+func (e *Enclave) PublicKey() ed25519.PublicKey {
+	return e.key.Public().(ed25519.PublicKey)
+}
+
+// Evidence returns the evidence of a job result, signed by the enclave and including its quote
+func (e *Enclave) Evidence(jobUUID string, result []byte) *attest.Evidence {
+	digest := attest.Digest(result)
+	return &attest.Evidence{
+		JobUUID:      jobUUID,
+		ResultDigest: digest,
+		PublicKey:    e.PublicKey(),
+		Signature:    ed25519.Sign(e.key, attest.SignedMessage(jobUUID, digest)),
+		Quote:        e.quote,
+	}
+}
+
+type attester struct {
+	key ed25519.PublicKey
+}
+
+func (a attester) VerifyQuote(_ context.Context, data []byte) (attest.Report, error) {
+	var q quote
+	if err := json.Unmarshal(data, &q); err != nil {
+		return attest.Report{}, err
+	}
+	if !ed25519.Verify(a.key, q.signed(), q.Signature) {
+		return attest.Report{}, errors.New("quote is not signed by the platform")
+	}
+	return attest.Report{Measurement: q.Measurement, ReportData: q.ReportData}, nil
+}
+
+// signed returns the signed content of the quote
+func (q quote) signed() []byte {
+	return append([]byte(q.Measurement+"\n"), q.ReportData...)
+}
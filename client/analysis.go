@@ -1,27 +1,52 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 
 	"github.com/gopher-lab/gopher-client/types"
 )
 
+// defaultAnalysisModel is the model used for analysis requests when none is specified
+const defaultAnalysisModel = "openai/gpt-4o-mini"
+
 // AnalyzeDataWithArgs analyzes tweets and other data using various AI models via OpenRouter with custom arguments
 //
 // Args:
 //   - tweets: Array of tweets to analyze
 //   - prompt: Analysis prompt
-//   - model: AI model to use for analysis (optional, defaults to "openai/gpt-4o-mini")
+//   - model: AI model to use for analysis (optional, defaults to "openai/gpt-4o-mini", must be in the model catalog)
 //   - app: Whether this is an app request (optional, defaults to false)
 //   - chatHistory: Previous chat history for context (optional)
 //   - currentQuery: Current query being analyzed (optional)
 //
 // Returns:
-//   - A pointer to AnalysisResponse containing the analysis results and metadata, or an error if the operation fails
+//   - A pointer to AnalysisResponse containing the analysis results and metadata, or an error if the operation fails.
+//     Models that are not in the model catalog are rejected with an UnknownModelError before making the request.
+//     If the catalog cannot be fetched, the model is not validated and the request is sent anyway (fail open).
 func (c *Client) AnalyzeDataWithArgs(data []string, prompt string, model string, app bool, chatHistory []types.ChatHistoryItem, currentQuery string) (*types.AnalysisResponse, error) {
-	// Set default model if not provided
+	return c.AnalyzeDataWithArgsContext(context.Background(), data, prompt, model, app, chatHistory, currentQuery)
+}
+
+// AnalyzeDataWithArgsContext is like AnalyzeDataWithArgs but with a context for cancellation and the parent trace span
+func (c *Client) AnalyzeDataWithArgsContext(ctx context.Context, data []string, prompt string, model string, app bool, chatHistory []types.ChatHistoryItem, currentQuery string) (*types.AnalysisResponse, error) {
+	return traced(ctx, c, "AnalyzeDataWithArgs", func(ctx context.Context) (*types.AnalysisResponse, error) {
+		return c.analyzeData(ctx, data, prompt, model, app, chatHistory, currentQuery)
+	})
+}
+
+func (c *Client) analyzeData(ctx context.Context, data []string, prompt string, model string, app bool, chatHistory []types.ChatHistoryItem, currentQuery string) (*types.AnalysisResponse, error) {
+	// Set default model if not provided, otherwise reject models that are not in the catalog
 	if model == "" {
-		model = "openai/gpt-4o-mini"
+		model = defaultAnalysisModel
+	} else if err := c.Models().validate(ctx, model); err != nil {
+		var unknownModel *UnknownModelError
+		if errors.As(err, &unknownModel) {
+			return nil, err
+		}
+		// The catalog could not be fetched, fail open and leave the validation to the API
+		c.log().WarnContext(ctx, "Model not validated, the model catalog is unavailable", "model", model, "error", err.Error())
 	}
 
 	request := types.AnalysisRequest{
@@ -39,7 +64,7 @@ func (c *Client) AnalyzeDataWithArgs(data []string, prompt string, model string,
 	}
 
 	var response types.AnalysisResponse
-	err = c.doImmediateRequest(c.BaseURL+"/v1/analysis", requestBody, &response)
+	err = c.post(ctx, c.BaseURL+"/v1/analysis", requestBody, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -56,18 +81,36 @@ func (c *Client) AnalyzeDataWithArgs(data []string, prompt string, model string,
 // Returns:
 //   - A pointer to AnalysisResponse containing the analysis results and metadata, or an error if the operation fails
 func (c *Client) AnalyzeData(tweets []string, prompt string) (*types.AnalysisResponse, error) {
-	return c.AnalyzeDataWithArgs(tweets, prompt, "", false, nil, "")
+	return c.AnalyzeDataContext(context.Background(), tweets, prompt)
 }
 
-// GetAvailableModels retrieves the list of available AI models for analysis
+// AnalyzeDataContext is like AnalyzeData but with a context for cancellation and the parent trace span
+func (c *Client) AnalyzeDataContext(ctx context.Context, tweets []string, prompt string) (*types.AnalysisResponse, error) {
+	return traced(ctx, c, "AnalyzeData", func(ctx context.Context) (*types.AnalysisResponse, error) {
+		return c.analyzeData(ctx, tweets, prompt, "", false, nil, "")
+	})
+}
+
+// GetAvailableModels retrieves the list of available AI models for analysis.
+// Use GetModels or Models for the model metadata.
 //
 // Returns:
 //   - A slice of strings containing available model names, or an error if the operation fails
 func (c *Client) GetAvailableModels() ([]string, error) {
-	var models []string
-	err := c.doResultRequest(c.BaseURL+"/v1/analysis", &models)
-	if err != nil {
-		return nil, err
-	}
-	return models, nil
+	return c.GetAvailableModelsContext(context.Background())
+}
+
+// GetAvailableModelsContext is like GetAvailableModels but with a context for cancellation and the parent trace span
+func (c *Client) GetAvailableModelsContext(ctx context.Context) ([]string, error) {
+	return traced(ctx, c, "GetAvailableModels", func(ctx context.Context) ([]string, error) {
+		models, err := c.getModels(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, len(models))
+		for i, model := range models {
+			names[i] = model.ID
+		}
+		return names, nil
+	})
 }
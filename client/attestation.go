@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/gopher-lab/gopher-client/attest"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+)
+
+// VerifiedResult is a job result with the outcome of its attestation verification
+type VerifiedResult struct {
+	Docs     []types.Document
+	Evidence *attest.Evidence // nil if the server provided none
+	Verified bool
+	Err      error // why the result did not verify, wrapping attest.ErrVerification
+}
+
+// GetVerifiedResult gets the result of a finished job together with its attestation evidence and verifies it
+// with the verifier of the Attestation option. Results that do not verify are returned with Verified false.
+func (c *Client) GetVerifiedResult(jobID string) (*VerifiedResult, error) {
+	return c.GetVerifiedResultContext(context.Background(), jobID)
+}
+
+// GetVerifiedResultContext is like GetVerifiedResult but with a context for cancellation and the parent trace span
+func (c *Client) GetVerifiedResultContext(ctx context.Context, jobID string) (*VerifiedResult, error) {
+	return traced(ctx, c, "GetVerifiedResult", func(ctx context.Context) (*VerifiedResult, error) {
+		c.annotate(ctx, AttrJobUUID.String(jobID))
+		return c.getVerifiedResult(withJobID(ctx, jobID), jobID, nil)
+	})
+}
+
+func (c *Client) getVerifiedResult(ctx context.Context, jobID string, decode resultDecoder) (*VerifiedResult, error) {
+	if c.verifier == nil {
+		return nil, errors.New("attestation verification is not configured, see the Attestation option")
+	}
+
+	var raw json.RawMessage
+	if err := c.getResult(ctx, jobID, &raw); err != nil {
+		return nil, err
+	}
+	if decode == nil {
+		decode = decodeDocuments
+	}
+	docs, err := decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal result of job %s: %w", jobID, err)
+	}
+	result := &VerifiedResult{Docs: docs}
+
+	evidence, err := c.getEvidence(ctx, jobID)
+	if err != nil {
+		result.Err = fmt.Errorf("%w: %v", attest.ErrNoEvidence, err)
+	} else {
+		result.Evidence = evidence
+		result.Err = c.verifier.Verify(ctx, jobID, raw, evidence)
+	}
+	result.Verified = result.Err == nil
+
+	c.annotate(ctx, AttrJobVerified.Bool(result.Verified))
+	if result.Err != nil {
+		c.log().WarnContext(ctx, "Job result not verified", slog.String("job_id", jobID), slog.String("error", result.Err.Error()))
+	}
+	return result, nil
+}
+
+// getEvidence gets the attestation evidence of a job result
+func (c *Client) getEvidence(ctx context.Context, jobID string) (*attest.Evidence, error) {
+	url := c.BaseURL + jobEndpoint + "/attestation/" + jobID
+	var evidence attest.Evidence
+	if err := c.get(ctx, url, &evidence); err != nil {
+		return nil, err
+	}
+	return &evidence, nil
+}
+
+// jobResult gets the result of a finished job, decoded with decode if not nil, failing results that do not verify
+// if attestation is configured
+func (c *Client) jobResult(ctx context.Context, jobID string, decode resultDecoder) ([]types.Document, error) {
+	if c.verifier == nil && decode == nil {
+		var results []types.Document
+		err := c.getResult(ctx, jobID, &results)
+		return results, err
+	}
+	if c.verifier == nil {
+		var raw json.RawMessage
+		if err := c.getResult(ctx, jobID, &raw); err != nil {
+			return nil, err
+		}
+		docs, err := decode(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal result of job %s: %w", jobID, err)
+		}
+		return docs, nil
+	}
+
+	result, err := c.getVerifiedResult(ctx, jobID, decode)
+	if err != nil {
+		return nil, err
+	}
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	return result.Docs, nil
+}
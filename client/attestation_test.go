@@ -0,0 +1,106 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gopher-lab/gopher-client/attest"
+	"github.com/gopher-lab/gopher-client/attest/attesttest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Attestation", func() {
+	const result = `[{"id": "1", "content": "one"}]`
+
+	var (
+		server   *httptest.Server
+		platform *attesttest.Platform
+		evidence *attest.Evidence
+		verifier *attest.Verifier
+	)
+
+	BeforeEach(func() {
+		platform = attesttest.NewPlatform()
+		evidence = platform.NewEnclave("abcd").Evidence("job-1", []byte(result))
+
+		var err error
+		verifier, err = attest.NewVerifier(attest.WithAttester(platform.Attester()), attest.TrustedMeasurements("abcd"))
+		Expect(err).NotTo(HaveOccurred())
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /v1/search/live", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"uuid": "job-1"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/status/job-1", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"status": "done"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/result/job-1", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(result))
+		})
+		mux.HandleFunc("GET /v1/search/live/attestation/job-1", func(w http.ResponseWriter, r *http.Request) {
+			if evidence == nil {
+				http.NotFound(w, r)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(evidence)
+		})
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should verify results with their evidence", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token", Attestation(verifier))
+		Expect(err).NotTo(HaveOccurred())
+
+		verified, err := client.GetVerifiedResult("job-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verified.Verified).To(BeTrue())
+		Expect(verified.Err).NotTo(HaveOccurred())
+		Expect(verified.Docs).To(HaveLen(1))
+		Expect(verified.Evidence.JobUUID).To(Equal("job-1"))
+	})
+
+	It("should report results without evidence as not verified", func() {
+		evidence = nil
+		client, err := NewClientWithOptions(server.URL, "test-token", Attestation(verifier))
+		Expect(err).NotTo(HaveOccurred())
+
+		verified, err := client.GetVerifiedResult("job-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verified.Verified).To(BeFalse())
+		Expect(verified.Err).To(MatchError(attest.ErrNoEvidence))
+		Expect(verified.Docs).To(HaveLen(1))
+	})
+
+	It("should fail jobs whose results do not verify", func() {
+		evidence = attesttest.NewPlatform().NewEnclave("abcd").Evidence("job-1", []byte(result))
+		client, err := NewClientWithOptions(server.URL, "test-token", Attestation(verifier))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.SearchTwitter("golang")
+		Expect(err).To(MatchError(attest.ErrInvalidQuote))
+		Expect(err).To(MatchError(ContainSubstring("failed to get job results")))
+	})
+
+	It("should return verified job results", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token", Attestation(verifier))
+		Expect(err).NotTo(HaveOccurred())
+
+		docs, err := client.SearchTwitter("golang")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(docs).To(HaveLen(1))
+	})
+
+	It("should require a verifier", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.GetVerifiedResult("job-1")
+		Expect(err).To(MatchError(ContainSubstring("attestation verification is not configured")))
+	})
+})
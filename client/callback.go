@@ -0,0 +1,312 @@
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/masa-finance/tee-worker/v2/api/types"
+)
+
+const (
+	// CallbackSignatureHeader is the header of the HMAC-SHA256 signature of a callback, "sha256=<hex>"
+	CallbackSignatureHeader = "X-Gopher-Signature"
+	// CallbackTimestampHeader is the header of the Unix time in seconds at which a callback was signed
+	CallbackTimestampHeader = "X-Gopher-Timestamp"
+	// CallbackTolerance is how far the timestamp of a callback may be from the current time
+	CallbackTolerance = 5 * time.Minute
+	// DefaultCallbackGracePeriod is how long Wait waits for a callback before polling the job status
+	DefaultCallbackGracePeriod = 30 * time.Second
+
+	// maxCallbackBody bounds the size of callback bodies
+	maxCallbackBody = 1 << 20
+	// earlyCallbackTTL is how long callbacks of jobs nobody waits for yet are kept
+	earlyCallbackTTL = 10 * time.Minute
+)
+
+// Callback is a job completion callback
+type Callback struct {
+	UUID   string          `json:"uuid"`
+	Status types.JobStatus `json:"status"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// CallbackHandler is called with every verified callback
+type CallbackHandler func(ctx context.Context, callback Callback)
+
+// CallbackReceiver is an http.Handler receiving job completion callbacks signed with a shared secret. Wait
+// returns the results of a job once its callback arrives, and polls the job status if it does not arrive
+// within the grace period.
+type CallbackReceiver struct {
+	client   *Client
+	secret   []byte
+	grace    time.Duration
+	handlers []CallbackHandler
+
+	mu      sync.Mutex
+	waiters map[string][]chan Callback
+	early   map[string]earlyCallback
+}
+
+// earlyCallback is a callback received before Wait was called for its job
+type earlyCallback struct {
+	callback   Callback
+	receivedAt time.Time
+}
+
+var _ http.Handler = (*CallbackReceiver)(nil)
+
+// NewCallbackReceiver creates a CallbackReceiver verifying callbacks with secret. Wait polls jobs without a
+// callback after grace, DefaultCallbackGracePeriod if 0.
+func NewCallbackReceiver(c *Client, secret []byte, grace time.Duration) (*CallbackReceiver, error) {
+	if c == nil {
+		return nil, errors.New("client must not be nil")
+	}
+	if len(secret) == 0 {
+		return nil, errors.New("callback secret must not be empty")
+	}
+	if grace < 0 {
+		return nil, fmt.Errorf("callback grace period must not be negative, got %v", grace)
+	}
+	if grace == 0 {
+		grace = DefaultCallbackGracePeriod
+	}
+	return &CallbackReceiver{
+		client:  c,
+		secret:  secret,
+		grace:   grace,
+		waiters: make(map[string][]chan Callback),
+		early:   make(map[string]earlyCallback),
+	}, nil
+}
+
+// SignCallback returns the CallbackSignatureHeader value of a callback body signed with secret at timestamp, the
+// CallbackTimestampHeader value. The signature covers "<timestamp>.<body>", so that a captured callback cannot be
+// replayed once its timestamp is older than CallbackTolerance.
+func SignCallback(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Handle adds a handler called with every verified callback, before waiting Wait calls are resolved.
+// Handlers are called on the request path and should return quickly.
+func (r *CallbackReceiver) Handle(handler CallbackHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, handler)
+}
+
+// ServeHTTP verifies and dispatches a callback POSTed as JSON
+func (r *CallbackReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxCallbackBody))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	timestamp := req.Header.Get(CallbackTimestampHeader)
+	if !r.verify(body, timestamp, req.Header.Get(CallbackSignatureHeader)) {
+		r.client.log().WarnContext(req.Context(), "Rejected callback with an invalid signature", "remote_addr", req.RemoteAddr)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	if !fresh(timestamp, time.Now()) {
+		r.client.log().WarnContext(req.Context(), "Rejected stale callback", "remote_addr", req.RemoteAddr, "timestamp", timestamp)
+		http.Error(w, "stale callback", http.StatusUnauthorized)
+		return
+	}
+
+	var callback Callback
+	if err := json.Unmarshal(body, &callback); err != nil || callback.UUID == "" {
+		http.Error(w, "invalid callback", http.StatusBadRequest)
+		return
+	}
+	r.client.log().DebugContext(req.Context(), "Job callback received", "job_id", callback.UUID, "status", callback.Status.String())
+	r.dispatch(req.Context(), callback)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verify checks the signature of a callback body and timestamp in constant time
+func (r *CallbackReceiver) verify(body []byte, timestamp string, signature string) bool {
+	return hmac.Equal([]byte(SignCallback(r.secret, timestamp, body)), []byte(strings.TrimSpace(signature)))
+}
+
+// fresh reports whether a callback timestamp is within CallbackTolerance of now
+func fresh(timestamp string, now time.Time) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	return age <= CallbackTolerance && age >= -CallbackTolerance
+}
+
+// dispatch calls the handlers and resolves the waiters of the job, or keeps the callback for a later Wait
+func (r *CallbackReceiver) dispatch(ctx context.Context, callback Callback) {
+	r.mu.Lock()
+	handlers := r.handlers
+	waiters := r.waiters[callback.UUID]
+	delete(r.waiters, callback.UUID)
+	if len(waiters) == 0 && finished(callback.Status) {
+		r.forgetEarly()
+		r.early[callback.UUID] = earlyCallback{callback: callback, receivedAt: time.Now()}
+	}
+	r.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(ctx, callback)
+	}
+	for _, waiter := range waiters {
+		waiter <- callback
+	}
+}
+
+// forgetEarly forgets the early callbacks older than earlyCallbackTTL, r.mu must be held
+func (r *CallbackReceiver) forgetEarly() {
+	cutoff := time.Now().Add(-earlyCallbackTTL)
+	for id, early := range r.early {
+		if early.receivedAt.Before(cutoff) {
+			delete(r.early, id)
+		}
+	}
+}
+
+// Wait waits for the callback of a job and returns its results, like WaitForJobCompletion. If no callback
+// arrives within the grace period, it also polls the job status, until either finds the job finished or the
+// client timeout, counted from the call of Wait, expires.
+func (r *CallbackReceiver) Wait(ctx context.Context, jobID string) ([]types.Document, error) {
+	return traced(ctx, r.client, "Wait", func(ctx context.Context) ([]types.Document, error) {
+		ctx = withJobID(ctx, jobID)
+		r.client.annotate(ctx, AttrJobUUID.String(jobID))
+
+		ch, ok := r.register(jobID)
+		if !ok {
+			return r.resolve(ctx, <-ch, time.Now())
+		}
+		defer func() { r.unregister(jobID, ch) }()
+
+		start := time.Now()
+		grace := time.NewTimer(r.grace)
+		defer grace.Stop()
+		timeoutTimer := time.NewTimer(r.client.Timeout)
+		defer timeoutTimer.Stop()
+		var poll <-chan time.Time // Polls the job status once the grace period is over
+		var lastStatus types.JobStatus
+		for {
+			select {
+			case callback := <-ch:
+				if !finished(callback.Status) {
+					// Progress callbacks do not end the wait
+					r.client.storeStatus(ctx, jobID, callback.Status, callback.Error)
+					ch, _ = r.register(jobID)
+					continue
+				}
+				return r.resolve(ctx, callback, start)
+			case <-grace.C:
+				r.client.log().DebugContext(ctx, "No job callback within the grace period, polling", "job_id", jobID)
+				ticker := time.NewTicker(time.Second)
+				defer ticker.Stop()
+				poll = ticker.C
+			case <-poll:
+				status, err := r.client.getJobStatus(ctx, jobID)
+				if err != nil {
+					if status != nil && status.Status != "" {
+						r.client.storeStatus(ctx, jobID, status.Status, status.Error)
+					}
+					r.client.jobFinished(ctx, jobID, JobOutcomeError, start)
+					return nil, fmt.Errorf("failed to get job status: %w", err)
+				}
+				r.client.jobPolled(ctx, jobID, status.Status.String())
+				if status.Status != lastStatus {
+					r.client.statusChanged(ctx, lastStatus, status.Status)
+					lastStatus = status.Status
+				}
+				if finished(status.Status) {
+					return r.resolve(ctx, Callback{UUID: jobID, Status: status.Status, Error: status.Error}, start)
+				}
+				r.client.storeStatus(ctx, jobID, status.Status, status.Error)
+			case <-timeoutTimer.C:
+				r.client.jobFinished(ctx, jobID, JobOutcomeTimeout, start)
+				return nil, fmt.Errorf("job %s timed out after %v", jobID, r.client.Timeout)
+			case <-ctx.Done():
+				r.client.jobFinished(ctx, jobID, JobOutcomeTimeout, start)
+				return nil, fmt.Errorf("waiting for job %s: %w", jobID, ctx.Err())
+			}
+		}
+	})
+}
+
+// register registers a waiter of the job. If its callback was already received, the channel holds it and
+// register returns false.
+func (r *CallbackReceiver) register(jobID string) (chan Callback, bool) {
+	ch := make(chan Callback, 1)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if early, ok := r.early[jobID]; ok {
+		delete(r.early, jobID)
+		ch <- early.callback
+		return ch, false
+	}
+	r.waiters[jobID] = append(r.waiters[jobID], ch)
+	return ch, true
+}
+
+func (r *CallbackReceiver) unregister(jobID string, ch chan Callback) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	waiters := r.waiters[jobID]
+	for i, waiter := range waiters {
+		if waiter == ch {
+			waiters = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(waiters) == 0 {
+		delete(r.waiters, jobID)
+	} else {
+		r.waiters[jobID] = waiters
+	}
+}
+
+// resolve returns the results of a finished job from its callback
+func (r *CallbackReceiver) resolve(ctx context.Context, callback Callback, start time.Time) ([]types.Document, error) {
+	c := r.client
+	jobID := callback.UUID
+	if !callback.Status.IsDone() {
+		c.storeStatus(ctx, jobID, callback.Status, callback.Error)
+		c.jobFinished(ctx, jobID, JobOutcomeError, start)
+		return nil, fmt.Errorf("job failed with status %s: %s", callback.Status, callback.Error)
+	}
+
+	results, err := c.jobResult(ctx, jobID, nil)
+	if err != nil {
+		c.jobFinished(ctx, jobID, JobOutcomeError, start)
+		return nil, fmt.Errorf("failed to get job results: %w", err)
+	}
+	c.storeStatus(ctx, jobID, callback.Status, "")
+	c.jobFinished(ctx, jobID, JobOutcomeDone, start)
+	c.annotate(ctx, AttrDocumentCount.Int(len(results)))
+	return results, nil
+}
+
+// finished reports whether a job with the status is done or failed
+func finished(status types.JobStatus) bool {
+	return StoredJob{Status: status}.Finished()
+}
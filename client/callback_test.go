@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Callback receiver", func() {
+	var (
+		server   *httptest.Server
+		polls    atomic.Int32
+		client   *Client
+		receiver *CallbackReceiver
+		secret   = []byte("callback-secret")
+	)
+
+	BeforeEach(func() {
+		polls.Store(0)
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /v1/search/live/status/job-1", func(w http.ResponseWriter, r *http.Request) {
+			polls.Add(1)
+			_, _ = w.Write([]byte(`{"status": "done"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/status/job-4", func(w http.ResponseWriter, r *http.Request) {
+			polls.Add(1)
+			_, _ = w.Write([]byte(`{"status": "in progress"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/result/job-1", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`[{"id": "1", "content": "one"}]`))
+		})
+		server = httptest.NewServer(mux)
+
+		var err error
+		client, err = NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+		receiver, err = NewCallbackReceiver(client, secret, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	// deliver POSTs a callback signed with key at signedAt, unsigned if key is nil
+	deliver := func(body string, key []byte, signedAt time.Time) int {
+		req := httptest.NewRequest(http.MethodPost, "/callbacks", strings.NewReader(body))
+		if key != nil {
+			timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+			req.Header.Set(CallbackTimestampHeader, timestamp)
+			req.Header.Set(CallbackSignatureHeader, SignCallback(key, timestamp, []byte(body)))
+		}
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	It("should reject callbacks with an invalid signature", func() {
+		body := `{"uuid": "job-1", "status": "done"}`
+		Expect(deliver(body, nil, time.Now())).To(Equal(http.StatusUnauthorized))
+		Expect(deliver(body, []byte("other-secret"), time.Now())).To(Equal(http.StatusUnauthorized))
+		Expect(deliver(`{"status": "done"}`, secret, time.Now())).To(Equal(http.StatusBadRequest))
+
+		req := httptest.NewRequest(http.MethodPost, "/callbacks", strings.NewReader(body))
+		req.Header.Set(CallbackTimestampHeader, strconv.FormatInt(time.Now().Unix(), 10))
+		req.Header.Set(CallbackSignatureHeader, SignCallback(secret, "0", []byte(body)))
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+
+		rec = httptest.NewRecorder()
+		receiver.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/callbacks", nil))
+		Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	It("should reject stale callbacks", func() {
+		body := `{"uuid": "job-1", "status": "done"}`
+		Expect(deliver(body, secret, time.Now().Add(-CallbackTolerance-time.Minute))).To(Equal(http.StatusUnauthorized))
+		Expect(deliver(body, secret, time.Now().Add(CallbackTolerance+time.Minute))).To(Equal(http.StatusUnauthorized))
+		Expect(deliver(body, secret, time.Now().Add(-time.Minute))).To(Equal(http.StatusNoContent))
+	})
+
+	It("should resolve Wait when the callback arrives, without polling", func() {
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(50 * time.Millisecond)
+			body := `{"uuid": "job-1", "status": "done"}`
+			Expect(deliver(body, secret, time.Now())).To(Equal(http.StatusNoContent))
+		}()
+
+		docs, err := receiver.Wait(context.Background(), "job-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(docs).To(HaveLen(1))
+		Expect(docs[0].Content).To(Equal("one"))
+		Expect(polls.Load()).To(BeZero())
+	})
+
+	It("should resolve Wait with a callback received before it", func() {
+		body := `{"uuid": "job-1", "status": "done"}`
+		Expect(deliver(body, secret, time.Now())).To(Equal(http.StatusNoContent))
+
+		docs, err := receiver.Wait(context.Background(), "job-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(docs).To(HaveLen(1))
+	})
+
+	It("should return the error of failed jobs", func() {
+		body := `{"uuid": "job-2", "status": "error", "error": "rate limited"}`
+		Expect(deliver(body, secret, time.Now())).To(Equal(http.StatusNoContent))
+
+		_, err := receiver.Wait(context.Background(), "job-2")
+		Expect(err).To(MatchError(ContainSubstring("rate limited")))
+	})
+
+	It("should dispatch callbacks to handlers", func() {
+		received := make(chan Callback, 1)
+		receiver.Handle(func(_ context.Context, callback Callback) {
+			received <- callback
+		})
+
+		body := `{"uuid": "job-3", "status": "done(saved)"}`
+		Expect(deliver(body, secret, time.Now())).To(Equal(http.StatusNoContent))
+		Eventually(received).Should(Receive(Equal(Callback{UUID: "job-3", Status: types.JobStatusSaved})))
+	})
+
+	It("should poll the job status when no callback arrives within the grace period", func() {
+		var err error
+		receiver, err = NewCallbackReceiver(client, secret, 10*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+
+		docs, err := receiver.Wait(context.Background(), "job-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(docs).To(HaveLen(1))
+		Expect(polls.Load()).To(BeNumerically(">=", 1))
+	})
+
+	It("should keep waiting for the callback while polling", func() {
+		var err error
+		receiver, err = NewCallbackReceiver(client, secret, 10*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+		go func() {
+			defer GinkgoRecover()
+			Eventually(polls.Load, 2*time.Second).Should(BeNumerically(">=", 1))
+			body := `{"uuid": "job-4", "status": "error", "error": "rate limited"}`
+			Expect(deliver(body, secret, time.Now())).To(Equal(http.StatusNoContent))
+		}()
+
+		_, err = receiver.Wait(context.Background(), "job-4")
+		Expect(err).To(MatchError(ContainSubstring("rate limited")))
+	})
+
+	It("should time out after the client timeout, including the grace period", func() {
+		var err error
+		client, err = NewClientWithOptions(server.URL, "test-token", Timeout(200*time.Millisecond))
+		Expect(err).NotTo(HaveOccurred())
+		receiver, err = NewCallbackReceiver(client, secret, 150*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+
+		start := time.Now()
+		_, err = receiver.Wait(context.Background(), "job-4")
+		Expect(err).To(MatchError(ContainSubstring("timed out")))
+		Expect(time.Since(start)).To(BeNumerically("<", 300*time.Millisecond))
+	})
+})
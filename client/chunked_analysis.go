@@ -0,0 +1,244 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gopher-lab/gopher-client/types"
+)
+
+// defaultContextWindow is the context window (in tokens) assumed for models that are not in modelContextWindows
+const defaultContextWindow = 16000
+
+// defaultChunkConcurrency is the number of chunks analyzed in parallel when no concurrency is specified
+const defaultChunkConcurrency = 4
+
+// modelContextWindows holds the approximate context window (in tokens) of commonly used analysis models,
+// used when the model catalog doesn't report one
+var modelContextWindows = map[string]int{
+	"openai/gpt-4o-mini":                128000,
+	"openai/gpt-4o":                     128000,
+	"openai/gpt-4.1-mini":               1047576,
+	"openai/gpt-4.1":                    1047576,
+	"anthropic/claude-3.5-sonnet":       200000,
+	"anthropic/claude-3-haiku":          200000,
+	"google/gemini-flash-1.5":           1000000,
+	"meta-llama/llama-3.1-70b-instruct": 131072,
+}
+
+// ChunkedAnalysisOptions configures AnalyzeDataChunked
+type ChunkedAnalysisOptions struct {
+	Model          string                  // AI model to use for analysis (optional, defaults to "openai/gpt-4o-mini")
+	MaxChunkTokens int                     // Estimated token budget per chunk (optional, derived from the model's context window)
+	Concurrency    int                     // Number of chunks analyzed in parallel (optional, defaults to 4)
+	ReducePrompt   string                  // Prompt used to combine the partial analyses (optional, derived from the analysis prompt)
+	App            bool                    // Whether this is an app request (optional, defaults to false)
+	ChatHistory    []types.ChatHistoryItem // Previous chat history for context (optional)
+	CurrentQuery   string                  // Current query being analyzed (optional)
+}
+
+// AnalyzeDataChunked analyzes datasets that are too large for a single analysis request.
+// The data is split into chunks by an estimated token budget, the chunks are analyzed concurrently
+// and the partial analyses are then combined by a reduce pass using the same model.
+// Data that fits into a single chunk is analyzed with a single request.
+//
+// Args:
+//   - data: Array of tweets or other data to analyze
+//   - prompt: Analysis prompt
+//   - opts: Chunking and analysis options
+//
+// Returns:
+//   - A pointer to AnalysisResponse containing the combined analysis, the tokens used by all requests and the
+//     job UUIDs of the per-chunk analyses, or an error if any of the requests fails
+func (c *Client) AnalyzeDataChunked(data []string, prompt string, opts ChunkedAnalysisOptions) (*types.AnalysisResponse, error) {
+	return c.AnalyzeDataChunkedContext(context.Background(), data, prompt, opts)
+}
+
+// AnalyzeDataChunkedContext is like AnalyzeDataChunked but with a context for cancellation and the parent trace span
+func (c *Client) AnalyzeDataChunkedContext(ctx context.Context, data []string, prompt string, opts ChunkedAnalysisOptions) (*types.AnalysisResponse, error) {
+	return traced(ctx, c, "AnalyzeDataChunked", func(ctx context.Context) (*types.AnalysisResponse, error) {
+		return c.analyzeDataChunked(ctx, data, prompt, opts)
+	})
+}
+
+func (c *Client) analyzeDataChunked(ctx context.Context, data []string, prompt string, opts ChunkedAnalysisOptions) (*types.AnalysisResponse, error) {
+	if opts.Model == "" {
+		opts.Model = defaultAnalysisModel
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultChunkConcurrency
+	}
+	budget := c.chunkTokenBudget(ctx, opts, prompt)
+
+	chunks := chunkByTokens(data, budget)
+	if len(chunks) <= 1 {
+		response, err := c.analyzeData(ctx, data, prompt, opts.Model, opts.App, opts.ChatHistory, opts.CurrentQuery)
+		if err != nil {
+			return nil, err
+		}
+		response.ChunkJobUUIDs = []string{response.JobUUID}
+		return response, nil
+	}
+
+	partials, err := c.analyzeChunks(ctx, chunks, prompt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tokensUsed := 0
+	chunkJobUUIDs := make([]string, len(partials))
+	for i, partial := range partials {
+		tokensUsed += partial.TokensUsed
+		chunkJobUUIDs[i] = partial.JobUUID
+	}
+
+	reducePrompt := opts.ReducePrompt
+	if reducePrompt == "" {
+		reducePrompt = fmt.Sprintf("The data consists of partial analyses, each produced for one chunk of a larger dataset "+
+			"using the prompt %q. Combine them into a single analysis that answers that prompt for the whole dataset.", prompt)
+	}
+
+	// Reduce the partial analyses level by level until they fit into a single request
+	reduceBudget := c.chunkTokenBudget(ctx, opts, reducePrompt)
+	for {
+		reduceChunks := chunkByTokens(formatPartialAnalyses(partials), reduceBudget)
+		if len(reduceChunks) <= 1 {
+			break
+		}
+		if len(reduceChunks) == len(partials) {
+			// Every partial analysis needs a request of its own, combining them would exceed the budget
+			return nil, fmt.Errorf("failed to combine %d partial analyses: they don't fit into the chunk token budget of %d",
+				len(partials), reduceBudget)
+		}
+		partials, err = c.analyzeChunks(ctx, reduceChunks, reducePrompt, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, partial := range partials {
+			tokensUsed += partial.TokensUsed
+		}
+	}
+
+	response, err := c.analyzeData(ctx, formatPartialAnalyses(partials), reducePrompt, opts.Model, opts.App, opts.ChatHistory, opts.CurrentQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine %d partial analyses: %w", len(partials), err)
+	}
+	response.TokensUsed += tokensUsed
+	response.ChunkJobUUIDs = chunkJobUUIDs
+	return response, nil
+}
+
+// analyzeChunks analyzes every chunk with at most opts.Concurrency requests in flight, preserving the chunk order.
+// The first failure cancels the analyses of the other chunks.
+func (c *Client) analyzeChunks(ctx context.Context, chunks [][]string, prompt string, opts ChunkedAnalysisOptions) ([]*types.AnalysisResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*types.AnalysisResponse, len(chunks))
+	sem := make(chan struct{}, opts.Concurrency)
+	var (
+		wg       sync.WaitGroup
+		failOnce sync.Once
+		failed   error
+	)
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				return
+			}
+			result, err := c.analyzeData(ctx, chunk, prompt, opts.Model, opts.App, opts.ChatHistory, opts.CurrentQuery)
+			if err != nil {
+				failOnce.Do(func() {
+					failed = fmt.Errorf("failed to analyze chunk %d of %d: %w", i+1, len(chunks), err)
+					cancel()
+				})
+				return
+			}
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+
+	if failed != nil {
+		return nil, failed
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// chunkTokenBudget returns the estimated number of data tokens that fit into a single analysis request
+func (c *Client) chunkTokenBudget(ctx context.Context, opts ChunkedAnalysisOptions, prompt string) int {
+	if opts.MaxChunkTokens > 0 {
+		return opts.MaxChunkTokens
+	}
+
+	window := c.contextWindow(ctx, opts.Model)
+
+	// Only use half of the context window for data, leaving room for the model output
+	budget := window/2 - estimateTokens(prompt) - estimateTokens(opts.CurrentQuery)
+	for _, item := range opts.ChatHistory {
+		budget -= estimateTokens(item.Query)
+	}
+	return max(budget, 1)
+}
+
+// contextWindow returns the context window of model from the model catalog, falling back to
+// well-known context windows when the catalog is unavailable or doesn't report one
+func (c *Client) contextWindow(ctx context.Context, model string) int {
+	if m, ok, err := c.Models().lookup(ctx, model); err == nil && ok && m.ContextWindow > 0 {
+		return m.ContextWindow
+	}
+	if window, ok := modelContextWindows[model]; ok {
+		return window
+	}
+	return defaultContextWindow
+}
+
+// chunkByTokens splits data into consecutive chunks whose estimated token count does not exceed budget.
+// Items that exceed the budget on their own are placed into a chunk of their own.
+func chunkByTokens(data []string, budget int) [][]string {
+	var chunks [][]string
+	var current []string
+	currentTokens := 0
+	for _, item := range data {
+		tokens := estimateTokens(item)
+		if len(current) > 0 && currentTokens+tokens > budget {
+			chunks = append(chunks, current)
+			current = nil
+			currentTokens = 0
+		}
+		current = append(current, item)
+		currentTokens += tokens
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// estimateTokens approximates the number of tokens in s, assuming ~4 characters per token plus some
+// per-item overhead for the JSON encoding
+func estimateTokens(s string) int {
+	return len(s)/4 + 4
+}
+
+// formatPartialAnalyses turns partial analyses into the data of a reduce request
+func formatPartialAnalyses(partials []*types.AnalysisResponse) []string {
+	data := make([]string, len(partials))
+	for i, partial := range partials {
+		data[i] = fmt.Sprintf("Partial analysis %d of %d:\n%s", i+1, len(partials), strings.TrimSpace(partial.Analysis))
+	}
+	return data
+}
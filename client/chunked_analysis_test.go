@@ -0,0 +1,158 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/gopher-lab/gopher-client/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Chunked Analysis", func() {
+	var (
+		server   *httptest.Server
+		client   *Client
+		mu       sync.Mutex
+		requests []types.AnalysisRequest
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				_ = json.NewEncoder(w).Encode([]string{defaultAnalysisModel})
+				return
+			}
+
+			var request types.AnalysisRequest
+			Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+
+			mu.Lock()
+			requests = append(requests, request)
+			n := len(requests)
+			mu.Unlock()
+
+			_ = json.NewEncoder(w).Encode(types.AnalysisResponse{
+				Analysis:   fmt.Sprintf("analysis of %d items", len(request.Tweets)),
+				ModelUsed:  request.Model,
+				TokensUsed: 10,
+				JobUUID:    fmt.Sprintf("job-%d", n),
+			})
+		}))
+		client = NewClient(server.URL, "test-token")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("AnalyzeDataChunked", func() {
+		It("should use a single request when the data fits into one chunk", func() {
+			response, err := client.AnalyzeDataChunked([]string{"a", "b"}, "What is the sentiment?", ChunkedAnalysisOptions{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Model).To(Equal(defaultAnalysisModel))
+			Expect(response.TokensUsed).To(Equal(10))
+			Expect(response.ChunkJobUUIDs).To(Equal([]string{response.JobUUID}))
+		})
+
+		It("should analyze chunks and reduce the partial analyses", func() {
+			data := make([]string, 10)
+			for i := range data {
+				data[i] = strings.Repeat("x", 180)
+			}
+
+			response, err := client.AnalyzeDataChunked(data, "What is the sentiment?", ChunkedAnalysisOptions{
+				MaxChunkTokens: 100,
+				Concurrency:    2,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			// 5 map requests with 2 items each, followed by a single reduce request
+			Expect(requests).To(HaveLen(6))
+			Expect(response.ChunkJobUUIDs).To(HaveLen(5))
+			Expect(response.ChunkJobUUIDs).NotTo(ContainElement(response.JobUUID))
+			Expect(response.TokensUsed).To(Equal(60))
+			Expect(response.Analysis).To(Equal("analysis of 5 items"))
+
+			reduce := requests[len(requests)-1]
+			Expect(reduce.Prompt).To(ContainSubstring("What is the sentiment?"))
+			Expect(reduce.Tweets[0]).To(HavePrefix("Partial analysis 1 of 5"))
+		})
+
+		It("should reduce partial analyses in several passes when they do not fit into one request", func() {
+			data := make([]string, 10)
+			for i := range data {
+				data[i] = strings.Repeat("x", 40)
+			}
+
+			response, err := client.AnalyzeDataChunked(data, "What is the sentiment?", ChunkedAnalysisOptions{
+				MaxChunkTokens: 30,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			// 5 map requests, reduced to 3 and then 2 partial analyses, followed by the final reduce request
+			Expect(requests).To(HaveLen(11))
+			Expect(response.ChunkJobUUIDs).To(HaveLen(5))
+			Expect(response.TokensUsed).To(Equal(110))
+		})
+
+		It("should return an error when a chunk fails", func() {
+			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+			})
+
+			_, err := client.AnalyzeDataChunked([]string{"aaaa", "bbbb"}, "prompt", ChunkedAnalysisOptions{MaxChunkTokens: 5})
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to analyze chunk"))
+		})
+
+		It("should stop analyzing chunks after the first failure", func() {
+			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					_ = json.NewEncoder(w).Encode([]string{defaultAnalysisModel})
+					return
+				}
+				mu.Lock()
+				requests = append(requests, types.AnalysisRequest{})
+				mu.Unlock()
+				w.WriteHeader(http.StatusBadRequest)
+			})
+
+			_, err := client.AnalyzeDataChunked([]string{"aaaa", "bbbb", "cccc", "dddd"}, "prompt", ChunkedAnalysisOptions{
+				MaxChunkTokens: 5,
+				Concurrency:    1,
+			})
+
+			Expect(err).To(MatchError(ContainSubstring("of 4")))
+			Expect(requests).To(HaveLen(1))
+		})
+
+		It("should return an error when the partial analyses can't be combined within the budget", func() {
+			_, err := client.AnalyzeDataChunked([]string{"aaaa", "bbbb"}, "prompt", ChunkedAnalysisOptions{MaxChunkTokens: 5})
+
+			Expect(err).To(MatchError(ContainSubstring("failed to combine 2 partial analyses")))
+			// Only the chunks are analyzed, without an oversized reduce request
+			Expect(requests).To(HaveLen(2))
+		})
+	})
+
+	Describe("chunkByTokens", func() {
+		It("should keep items that exceed the budget in their own chunk", func() {
+			chunks := chunkByTokens([]string{"a", strings.Repeat("x", 100), "b"}, 10)
+
+			Expect(chunks).To(Equal([][]string{{"a"}, {strings.Repeat("x", 100)}, {"b"}}))
+		})
+
+		It("should return no chunks for empty data", func() {
+			Expect(chunkByTokens(nil, 10)).To(BeEmpty())
+		})
+	})
+})
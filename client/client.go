@@ -1,14 +1,21 @@
 package client
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
+	"log/slog"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/gopher-lab/gopher-client/attest"
 	"github.com/gopher-lab/gopher-client/config"
+	"github.com/gopher-lab/gopher-client/redact"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
     "github.com/masa-finance/tee-worker/v2/api/types"
 )
 
@@ -18,9 +25,28 @@ const jobEndpoint = "/v1/search/live"
 // Client represents the API client
 type Client struct {
 	BaseURL    string
-	Token      string
+	Token      string // Static API token, ignored if a TokenSource is set with Credentials
 	Timeout    time.Duration
 	HTTPClient *http.Client
+
+	models     *ModelCatalog
+	modelsOnce sync.Once
+	observer   Observer
+	jobs       jobTracker
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	logger     *slog.Logger
+	redactor   *redact.Redactor
+	tokens     TokenSource
+	verifier   *attest.Verifier
+	store      JobStore
+
+	jobRetention time.Duration
+	lastPrune    atomic.Int64 // Unix time in nanoseconds of the last job store prune
+
+	maxResponseSize int64
+	compressMinSize int
+	submissions     *singleflight.Group // Deduplicates submissions in flight, nil if disabled
 }
 
 // NewClient creates a new API client
@@ -31,6 +57,8 @@ func NewClient(baseURL string, token string) *Client {
 		Token:      token,
 		Timeout:    opts.Timeout,
 		HTTPClient: opts.HttpClient,
+
+		maxResponseSize: opts.MaxResponseSize,
 	}
 }
 
@@ -40,13 +68,26 @@ func NewClientFromConfig() (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	opts, _ := NewOptions(Timeout(cfg.Timeout))
-	return &Client{
-		BaseURL:    cfg.BaseUrl,
-		Token:      cfg.Token,
-		Timeout:    cfg.Timeout,
-		HTTPClient: opts.HttpClient,
-	}, nil
+	return NewClientWithConfig(cfg)
+}
+
+// NewClientFromProfile creates a new API client from a profile of the config file, see config.LoadProfile
+func NewClientFromProfile(name string) (*Client, error) {
+	cfg, err := config.LoadProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewClientWithConfig(cfg)
+}
+
+// NewClientWithConfig creates a new API client from a loaded configuration, e.g. from config.Flags.
+// The configuration is validated, see ConfigOptions. Options are applied after the configuration.
+func NewClientWithConfig(cfg *config.Config, opts ...Option) (*Client, error) {
+	configOpts, err := ConfigOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewClientWithOptions(cfg.BaseUrl, cfg.Token, append(configOpts, opts...)...)
 }
 
 // MustNewClientFromConfig creates a new API client from configuration and panics on error
@@ -58,249 +99,221 @@ func MustNewClientFromConfig() *Client {
 	return client
 }
 
+// redaction returns the redactor applied to bodies in error messages, the default one if none is configured
+func (c *Client) redaction() *redact.Redactor {
+	if c.redactor == nil {
+		return redact.Default()
+	}
+	return c.redactor
+}
+
 func getErrorFromResponse(body []byte) error {
+	if message := errorMessage(body); message != "" {
+		return fmt.Errorf("job errored: %s", message)
+	}
+	return nil
+}
+
+// errorMessage returns the "error" field of a JSON response body, if any
+func errorMessage(body []byte) string {
 	result := struct {
 		Error string `json:"error"`
 	}{}
 
 	_ = json.Unmarshal(body, &result)
-
-	if result.Error != "" {
-		return fmt.Errorf("job errored: %s", result.Error)
-	}
-
-	return nil
+	return result.Error
 }
 
-func (c *Client) doRequest(url string, requestBody []byte) (*types.ResultResponse, error) {
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(requestBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create POST request to %s: %w", url, err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	if c.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
-	}
-
-	resp, err := c.HTTPClient.Do(req)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to do POST request to %s: %w", url, err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+// NewClientWithOptions creates a new API client with functional options.
+// Non-breaking addition; callers can keep using NewClient/NewClientFromConfig.
+func NewClientWithOptions(baseURL string, token string, opts ...Option) (*Client, error) {
+	options, err := NewOptions(opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read body from POST request to %s: %w", url, err)
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("job errored: Status code %d during call to %s with request body %s. Response body: %s",
-			resp.StatusCode, url, requestBody, body)
-	}
-
-	var searchResponse types.ResultResponse
-	if err := json.Unmarshal(body, &searchResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal GET %s response %s: %w", url, body, err)
+		return nil, err
 	}
-
-	return &searchResponse, getErrorFromResponse(body)
+	// default Timeout field mirrors the http client timeout unless overridden via Timeout option
+	timeout := options.HttpClient.Timeout
+	return &Client{
+		BaseURL:    baseURL,
+		Token:      token,
+		Timeout:    timeout,
+		HTTPClient: options.HttpClient,
+		observer:   options.Observer,
+		tracer:     options.tracer(),
+		propagator: options.TracePropagator,
+		logger:     options.Logger,
+		redactor:   options.Redactor,
+		tokens:     options.TokenSource,
+		verifier:   options.Verifier,
+		store:      options.JobStore,
+
+		jobRetention: options.JobRetention,
+
+		maxResponseSize: options.MaxResponseSize,
+		compressMinSize: options.CompressMinSize,
+		submissions:     submissionGroup(options.dedupeSubmissions),
+	}, nil
 }
 
-func (c *Client) doStatusRequest(url string) (*types.IndexerJobResult, error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create GET request to %s: %w", url, err)
-	}
-
-	if c.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
-	}
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to do GET request to %s: %w", url, err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read body from POST request to %s: %w", url, err)
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("job errored: Status code %d during call to %s. Response body: %s", resp.StatusCode, url, body)
-	}
-
-	var jobStatusResponse types.IndexerJobResult
-	if err := json.Unmarshal(body, &jobStatusResponse); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal GET %s response %s: %w", url, body, err)
-	}
-
-	return &jobStatusResponse, getErrorFromResponse(body)
+// GetJobStatus sends a GET request to the job status endpoint
+func (c *Client) GetJobStatus(jobID string) (*types.IndexerJobResult, error) {
+	return c.GetJobStatusContext(context.Background(), jobID)
 }
 
-func (c *Client) doResultRequest(url string, receiver any) error {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create GET request to %s: %w", url, err)
-	}
-
-	if c.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
-	}
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to do GET request to %s: %w", url, err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read body from POST request to %s: %w", url, err)
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("job errored: Status code %d during call to %s. Response body: %s", resp.StatusCode, url, body)
-	}
-
-	if err := json.Unmarshal(body, receiver); err != nil {
-		return fmt.Errorf("failed to unmarshal GET %s response %s: %w", url, body, err)
-	}
-
-	return getErrorFromResponse(body)
+// GetJobStatusContext is like GetJobStatus but with a context for cancellation and the parent trace span
+func (c *Client) GetJobStatusContext(ctx context.Context, jobID string) (*types.IndexerJobResult, error) {
+	return traced(ctx, c, "GetJobStatus", func(ctx context.Context) (*types.IndexerJobResult, error) {
+		c.annotate(ctx, AttrJobUUID.String(jobID))
+		return c.getJobStatus(ctx, jobID)
+	})
 }
 
-// doMetricsRequest sends a GET request to the metrics endpoint
-func (c *Client) doMetricsRequest(url string, receiver any) error {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create GET request to %s: %w", url, err)
-	}
-
-	if c.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
-	}
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to do GET request to %s: %w", url, err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read body from GET request to %s: %w", url, err)
+func (c *Client) getJobStatus(ctx context.Context, jobID string) (*types.IndexerJobResult, error) {
+	var status types.IndexerJobResult
+	if err := c.get(ctx, c.BaseURL+jobEndpoint+"/status/"+jobID, &status); err != nil {
+		if isResponseError(err) {
+			// The status of failed jobs is returned with their error
+			return &status, err
+		}
+		return nil, err
 	}
+	return &status, nil
+}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("job errored: Status code %d during call to %s. Response body: %s", resp.StatusCode, url, body)
-	}
+// GetResult sends a GET request to the job result endpoint
+func (c *Client) GetResult(jobID string, receiver any) error {
+	return c.GetResultContext(context.Background(), jobID, receiver)
+}
 
-	if err := json.Unmarshal(body, receiver); err != nil {
-		return fmt.Errorf("Error during unmarshal: %#w. URL: %s. Response: '%s'", err, url, body)
-	}
+// GetResultContext is like GetResult but with a context for cancellation and the parent trace span
+func (c *Client) GetResultContext(ctx context.Context, jobID string, receiver any) error {
+	_, err := traced(ctx, c, "GetResult", func(ctx context.Context) (struct{}, error) {
+		c.annotate(ctx, AttrJobUUID.String(jobID))
+		return struct{}{}, c.getResult(ctx, jobID, receiver)
+	})
+	return err
+}
 
-	return getErrorFromResponse(body)
+func (c *Client) getResult(ctx context.Context, jobID string, receiver any) error {
+	return c.get(ctx, c.BaseURL+jobEndpoint+"/result/"+jobID, receiver)
 }
 
-func (c *Client) doImmediateRequest(url string, requestBody []byte, receiver any) error {
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(requestBody))
+// submitJob marshals the job parameters and submits the job
+func (c *Client) submitJob(ctx context.Context, jobParams any) (*types.ResultResponse, error) {
+	body, err := json.Marshal(jobParams)
 	if err != nil {
-		return fmt.Errorf("failed to create POST request to %s: %w", url, err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	if c.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.Token)
-	}
+	return c.submitDeduped(ctx, body)
+}
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to do POST request to %s: %w", url, err)
-	}
-	defer resp.Body.Close()
+// runJob submits the job and waits for its completion
+func (c *Client) runJob(ctx context.Context, jobParams any) ([]types.Document, error) {
+	return c.runDecodedJob(ctx, jobParams, nil)
+}
 
-	body, err := io.ReadAll(resp.Body)
+// runDecodedJob submits the job and waits for its completion, decoding its result with decode if not nil
+func (c *Client) runDecodedJob(ctx context.Context, jobParams any, decode resultDecoder) ([]types.Document, error) {
+	resp, err := c.submitJob(ctx, jobParams)
 	if err != nil {
-		return fmt.Errorf("failed to read body from POST request to %s: %w", url, err)
+		return nil, err
 	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("job errored: Status code %d during call to %s. Body: %s", resp.StatusCode, url, body)
+	if resp.Error != "" {
+		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
 	}
+	return c.waitForJobCompletion(ctx, resp.UUID, decode)
+}
 
-	if err := json.Unmarshal(body, receiver); err != nil {
-		return fmt.Errorf("Error during unmarshal: %#w. URL: %s. Request: '%s'. Response: '%s'", err, url, requestBody, body)
-	}
+// WaitForJobCompletion polls the job status until completion and returns the results
+func (c *Client) WaitForJobCompletion(jobID string) ([]types.Document, error) {
+	return c.WaitForJobCompletionContext(context.Background(), jobID)
+}
 
-	return getErrorFromResponse(body)
+// WaitForJobCompletionContext is like WaitForJobCompletion but with a context for cancellation and the parent trace span
+func (c *Client) WaitForJobCompletionContext(ctx context.Context, jobID string) ([]types.Document, error) {
+	return traced(ctx, c, "WaitForJobCompletion", func(ctx context.Context) ([]types.Document, error) {
+		return c.waitForJobCompletion(ctx, jobID, nil)
+	})
 }
 
-// NewClientWithOptions creates a new API client with functional options.
-// Non-breaking addition; callers can keep using NewClient/NewClientFromConfig.
-func NewClientWithOptions(baseURL string, token string, opts ...Option) (*Client, error) {
-	options, err := NewOptions(opts...)
+func (c *Client) waitForJobCompletion(ctx context.Context, jobID string, decode resultDecoder) ([]types.Document, error) {
+	ctx = withJobID(ctx, jobID)
+	status, start, err := c.waitForJobDone(ctx, jobID)
 	if err != nil {
 		return nil, err
 	}
-	// default Timeout field mirrors the http client timeout unless overridden via Timeout option
-	timeout := options.HttpClient.Timeout
-	return &Client{
-		BaseURL:    baseURL,
-		Token:      token,
-		Timeout:    timeout,
-		HTTPClient: options.HttpClient,
-	}, nil
-}
 
-// GetJobStatus sends a GET request to the job status endpoint
-func (c *Client) GetJobStatus(jobID string) (*types.IndexerJobResult, error) {
-	url := c.BaseURL + jobEndpoint + "/status/" + jobID
-	return c.doStatusRequest(url)
-}
-
-// GetResult sends a GET request to the job result endpoint
-func (c *Client) GetResult(jobID string, receiver any) error {
-	url := c.BaseURL + jobEndpoint + "/result/" + jobID
-	return c.doResultRequest(url, receiver)
+	results, err := c.jobResult(ctx, jobID, decode)
+	if err != nil {
+		c.jobFinished(ctx, jobID, JobOutcomeError, start)
+		return nil, fmt.Errorf("failed to get job results: %w", err)
+	}
+	// Stored as done once the results are fetched, so that a failed fetch is resumed
+	c.storeStatus(ctx, jobID, status, "")
+	c.jobFinished(ctx, jobID, JobOutcomeDone, start)
+	c.annotate(ctx, AttrDocumentCount.Int(len(results)))
+	return results, nil
 }
 
-// WaitForJobCompletion polls the job status until completion and returns the results
-func (c *Client) WaitForJobCompletion(jobID string) ([]types.Document, error) {
+// waitForJobDone polls the job status until it is done and returns the status and when the wait started.
+// Failed jobs and timeouts are reported as finished, done jobs are reported by the caller once their results
+// are fetched.
+func (c *Client) waitForJobDone(ctx context.Context, jobID string) (types.JobStatus, time.Time, error) {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
 
 	timeoutTimer := time.NewTimer(c.Timeout)
 	defer timeoutTimer.Stop()
 
+	if job, ok := c.trackedJob(jobID); ok {
+		c.annotate(ctx, AttrJobType.String(job.jobType))
+	}
+	c.annotate(ctx, AttrJobUUID.String(jobID))
+
+	start := time.Now()
+	polls := 0
+	var lastStatus types.JobStatus
+	defer func() { c.annotate(ctx, AttrJobPolls.Int(polls)) }()
 	for {
 		select {
 		case <-ticker.C:
-			status, err := c.GetJobStatus(jobID)
+			status, err := c.getJobStatus(ctx, jobID)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get job status: %w", err)
+				if status != nil && status.Status != "" {
+					c.storeStatus(ctx, jobID, status.Status, status.Error)
+				}
+				c.jobFinished(ctx, jobID, JobOutcomeError, start)
+				return "", start, fmt.Errorf("failed to get job status: %w", err)
+			}
+			polls++
+			c.jobPolled(ctx, jobID, status.Status.String())
+			if status.Status != lastStatus {
+				c.statusChanged(ctx, lastStatus, status.Status)
+				lastStatus = status.Status
+				if !status.Status.IsDone() {
+					c.storeStatus(ctx, jobID, status.Status, status.Error)
+				}
 			}
 
 			// Check if job is done (either "done" or "done(not saved)")
 			if status.Status.IsDone() {
-				var results []types.Document
-				err = c.GetResult(jobID, &results)
-				if err != nil {
-					return nil, fmt.Errorf("failed to get job results: %w", err)
-				}
-				return results, nil
+				return status.Status, start, nil
 			}
 
 			// Check for errors
 			if status.Status == types.JobStatusError || status.Status == types.JobStatusRetryError {
-				return nil, fmt.Errorf("job failed with status %s: %s", status.Status, status.Error)
+				c.jobFinished(ctx, jobID, JobOutcomeError, start)
+				return "", start, fmt.Errorf("job failed with status %s: %s", status.Status, status.Error)
 			}
 
 		case <-timeoutTimer.C:
-			return nil, fmt.Errorf("job %s timed out after %v", jobID, c.Timeout)
+			c.jobFinished(ctx, jobID, JobOutcomeTimeout, start)
+			return "", start, fmt.Errorf("job %s timed out after %v", jobID, c.Timeout)
+
+		case <-ctx.Done():
+			c.jobFinished(ctx, jobID, JobOutcomeTimeout, start)
+			return "", start, fmt.Errorf("waiting for job %s: %w", jobID, ctx.Err())
 		}
 	}
 }
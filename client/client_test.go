@@ -1,9 +1,16 @@
 package client
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/gopher-lab/gopher-client/config"
+	"github.com/masa-finance/tee-worker/v2/api/types"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -54,6 +61,89 @@ var _ = Describe("Client", func() {
 				Expect(client).NotTo(BeNil())
 				Expect(client.BaseURL).To(Equal("https://test.example.com"))
 				Expect(client.Token).To(Equal("test-token-456"))
+				Expect(client.maxResponseSize).To(BeEquivalentTo(DefaultMaxResponseSize))
+			})
+
+			It("should disable the response size limit with 0 like the MaxResponseSize option", func() {
+				GinkgoT().Setenv("GOPHER_CLIENT_MAX_RESPONSE_SIZE", "0")
+				client, err := NewClientFromConfig()
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(client.maxResponseSize).To(BeZero())
+			})
+		})
+
+		Context("NewClientFromProfile", func() {
+			BeforeEach(func() {
+				path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+				content := "profiles:\n  staging:\n    url: https://staging.example.com\n    token: staging-token\n    timeout: 30s\n"
+				Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+				GinkgoT().Setenv("GOPHER_CLIENT_CONFIG", path)
+				os.Unsetenv("GOPHER_CLIENT_URL")
+				os.Unsetenv("GOPHER_CLIENT_TOKEN")
+			})
+
+			It("should create a client from the profile", func() {
+				client, err := NewClientFromProfile("staging")
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(client.BaseURL).To(Equal("https://staging.example.com"))
+				Expect(client.Token).To(Equal("staging-token"))
+				Expect(client.Timeout).To(Equal(30 * time.Second))
+			})
+
+			It("should fail for unknown profiles", func() {
+				_, err := NewClientFromProfile("prod")
+
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("NewClientWithConfig", func() {
+			var cfg *config.Config
+
+			BeforeEach(func() {
+				cfg = &config.Config{
+					BaseUrl:             "https://config.example.com",
+					Token:               "config-token",
+					Timeout:             45 * time.Second,
+					MaxConnsPerHost:     20,
+					MaxIdleConnsPerHost: 5,
+					MaxIdleConns:        50,
+					IdleConnTimeout:     time.Minute,
+					IgnoreTLSCert:       true,
+					DisableRedaction:    true,
+				}
+			})
+
+			It("should apply the config to the options", func() {
+				client, err := NewClientWithConfig(cfg)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(client.Timeout).To(Equal(45 * time.Second))
+				transport := client.HTTPClient.Transport.(*http.Transport)
+				Expect(transport.MaxConnsPerHost).To(Equal(20))
+				Expect(transport.MaxIdleConnsPerHost).To(Equal(5))
+				Expect(transport.MaxIdleConns).To(Equal(50))
+				Expect(transport.IdleConnTimeout).To(Equal(time.Minute))
+				Expect(transport.TLSClientConfig.InsecureSkipVerify).To(BeTrue())
+				Expect(client.redaction().Body([]byte(`{"token": "secret"}`))).To(ContainSubstring("secret"))
+			})
+
+			It("should let options override the config", func() {
+				client, err := NewClientWithConfig(cfg, Timeout(time.Second))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(client.Timeout).To(Equal(time.Second))
+			})
+
+			It("should reject invalid configs", func() {
+				cfg.MaxConnsPerHost = 0
+				cfg.BaseUrl = "config.example.com"
+
+				_, err := NewClientWithConfig(cfg)
+				Expect(err).To(MatchError(ContainSubstring("max_conns_per_host (GOPHER_CLIENT_MAX_CONNS_PER_HOST)")))
+				Expect(err).To(MatchError(ContainSubstring("url (GOPHER_CLIENT_URL)")))
 			})
 		})
 
@@ -114,54 +204,73 @@ var _ = Describe("Client", func() {
 			client = NewClient("https://api.example.com", "test-token")
 		})
 
-		Context("doRequest", func() {
+		Context("post", func() {
 			It("should handle invalid URL", func() {
-				// This is a basic test - in a real scenario you'd use httptest.Server
-				// For simplicity, we're just testing error handling with invalid URL
 				requestBody := []byte(`{"query": "test"}`)
+				var receiver interface{}
 
-				// Since we can't easily mock http.Client without more complex setup,
-				// we'll test that the method doesn't panic with invalid URL
-				_, err := client.doRequest("invalid-url", requestBody)
+				err := client.post(context.Background(), "invalid-url", requestBody, &receiver)
 
 				Expect(err).NotTo(BeNil())
 				Expect(err.Error()).To(ContainSubstring("failed to do POST request"))
 			})
 		})
 
-		Context("doStatusRequest", func() {
+		Context("get", func() {
 			It("should handle invalid URL", func() {
-				url := "invalid-url"
+				var receiver interface{}
 
-				_, err := client.doStatusRequest(url)
+				err := client.get(context.Background(), "invalid-url", &receiver)
 
 				Expect(err).NotTo(BeNil())
 				Expect(err.Error()).To(ContainSubstring("failed to do GET request"))
 			})
 		})
 
-		Context("doResultRequest", func() {
-			It("should handle invalid URL", func() {
-				url := "invalid-url"
-				var receiver interface{}
+		Context("send", func() {
+			var server *httptest.Server
 
-				err := client.doResultRequest(url, receiver)
+			BeforeEach(func() {
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.URL.Path {
+					case "/failed":
+						http.Error(w, `{"error": "rate limited"}`, http.StatusTooManyRequests)
+					case "/errored":
+						_, _ = w.Write([]byte(`{"status": "error", "error": "scraper crashed"}`))
+					default:
+						_, _ = w.Write([]byte(`not json`))
+					}
+				}))
+			})
 
-				Expect(err).NotTo(BeNil())
-				Expect(err.Error()).To(ContainSubstring("failed to do GET request"))
+			AfterEach(func() {
+				server.Close()
 			})
-		})
 
-		Context("doImmediateRequest", func() {
-			It("should handle invalid URL", func() {
-				url := "invalid-url"
-				requestBody := []byte(`{"query": "test"}`)
-				var receiver interface{}
+			It("should return an APIError for error statuses", func() {
+				err := client.post(context.Background(), server.URL+"/failed", []byte(`{"query": "test"}`), nil)
 
-				err := client.doImmediateRequest(url, requestBody, receiver)
+				var apiErr *APIError
+				Expect(errors.As(err, &apiErr)).To(BeTrue())
+				Expect(apiErr.StatusCode).To(Equal(http.StatusTooManyRequests))
+				Expect(apiErr.Message).To(Equal("rate limited"))
+				Expect(err.Error()).To(ContainSubstring("Status code 429 during call to " + server.URL + "/failed with request body"))
+			})
 
-				Expect(err).NotTo(BeNil())
-				Expect(err.Error()).To(ContainSubstring("failed to do POST request"))
+			It("should decode responses with an error field and return the error", func() {
+				var status types.IndexerJobResult
+				err := client.get(context.Background(), server.URL+"/errored", &status)
+
+				Expect(err).To(MatchError(HavePrefix("job errored: scraper crashed (request ID ")))
+				Expect(status.Status).To(Equal(types.JobStatusError))
+				Expect(isResponseError(err)).To(BeTrue())
+			})
+
+			It("should fail on invalid JSON", func() {
+				var receiver map[string]any
+				err := client.get(context.Background(), server.URL+"/invalid", &receiver)
+
+				Expect(err).To(MatchError(ContainSubstring("failed to unmarshal GET " + server.URL + "/invalid")))
 			})
 		})
 	})
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/gopher-lab/gopher-client/types"
@@ -16,6 +17,17 @@ import (
 // Returns:
 //   - A pointer to ContextualizeResponse containing the contextualized query and metadata, or an error if the operation fails
 func (c *Client) ContextualizeQuery(currentQuery string, chatHistory []types.ChatHistoryItem, maxHistoryItems int) (*types.ContextualizeResponse, error) {
+	return c.ContextualizeQueryContext(context.Background(), currentQuery, chatHistory, maxHistoryItems)
+}
+
+// ContextualizeQueryContext is like ContextualizeQuery but with a context for cancellation and the parent trace span
+func (c *Client) ContextualizeQueryContext(ctx context.Context, currentQuery string, chatHistory []types.ChatHistoryItem, maxHistoryItems int) (*types.ContextualizeResponse, error) {
+	return traced(ctx, c, "ContextualizeQuery", func(ctx context.Context) (*types.ContextualizeResponse, error) {
+		return c.contextualizeQuery(ctx, currentQuery, chatHistory, maxHistoryItems)
+	})
+}
+
+func (c *Client) contextualizeQuery(ctx context.Context, currentQuery string, chatHistory []types.ChatHistoryItem, maxHistoryItems int) (*types.ContextualizeResponse, error) {
 	// Set default maxHistoryItems if not provided or invalid
 	if maxHistoryItems <= 0 || maxHistoryItems > 10 {
 		maxHistoryItems = 5
@@ -33,7 +45,7 @@ func (c *Client) ContextualizeQuery(currentQuery string, chatHistory []types.Cha
 	}
 
 	var response types.ContextualizeResponse
-	err = c.doImmediateRequest(c.BaseURL+"/v1/contextualize", requestBody, &response)
+	err = c.post(ctx, c.BaseURL+"/v1/contextualize", requestBody, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// DefaultMaxResponseSize is the default maximum size of a response body read into memory, 64 MiB
+	DefaultMaxResponseSize = 64 << 20
+	// DefaultCompressMinSize is the size from which request bodies are compressed by CompressRequests(0)
+	DefaultCompressMinSize = 16 << 10
+
+	// acceptEncoding is the Accept-Encoding header of all requests, decoded by decodeResponse
+	acceptEncoding = "gzip, zstd"
+)
+
+// ResponseTooLargeError is returned when a response body exceeds the maximum response size
+type ResponseTooLargeError struct {
+	Limit int64 // The maximum response size in bytes
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeds the maximum size of %d bytes", e.Limit)
+}
+
+// MaxResponseSize sets the maximum size of a decompressed response body read into memory, failing larger
+// responses with a *ResponseTooLargeError. The default is DefaultMaxResponseSize, 0 disables the limit.
+// Streamed results are not limited.
+func MaxResponseSize(size int64) Option {
+	return func(o *Options) error {
+		if size < 0 {
+			return fmt.Errorf("maximum response size must not be negative, got %d", size)
+		}
+		o.MaxResponseSize = size
+		return nil
+	}
+}
+
+// CompressRequests compresses request bodies of at least minSize bytes with gzip, DefaultCompressMinSize if 0.
+// The server must accept gzip encoded requests.
+func CompressRequests(minSize int) Option {
+	return func(o *Options) error {
+		if minSize < 0 {
+			return fmt.Errorf("compression minimum size must not be negative, got %d", minSize)
+		}
+		if minSize == 0 {
+			minSize = DefaultCompressMinSize
+		}
+		o.CompressMinSize = minSize
+		return nil
+	}
+}
+
+// readBody reads a response body up to the maximum response size
+func (c *Client) readBody(resp *http.Response) ([]byte, error) {
+	if c.maxResponseSize <= 0 {
+		return io.ReadAll(resp.Body)
+	}
+	if resp.ContentLength > c.maxResponseSize {
+		return nil, &ResponseTooLargeError{Limit: c.maxResponseSize}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > c.maxResponseSize {
+		return nil, &ResponseTooLargeError{Limit: c.maxResponseSize}
+	}
+	return body, nil
+}
+
+// compressRequest compresses the body of req with gzip if it is large enough
+func (c *Client) compressRequest(req *http.Request) error {
+	if c.compressMinSize <= 0 || req.Body == nil || req.GetBody == nil || req.ContentLength < int64(c.compressMinSize) ||
+		req.Header.Get("Content-Encoding") != "" {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := io.Copy(zw, body); err != nil {
+		return fmt.Errorf("failed to compress request body: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress request body: %w", err)
+	}
+
+	compressed := buf.Bytes()
+	req.Body = io.NopCloser(bytes.NewReader(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	req.ContentLength = int64(len(compressed))
+	req.Header.Set("Content-Encoding", "gzip")
+	return nil
+}
+
+// decodeResponse replaces a gzip or zstd encoded response body with the decoded body
+func decodeResponse(resp *http.Response) error {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	var decoded io.ReadCloser
+	switch encoding {
+	case "gzip", "x-gzip":
+		decoded = &gzipBody{body: resp.Body}
+	case "zstd":
+		decoder, err := zstd.NewReader(resp.Body, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return fmt.Errorf("failed to decode zstd response: %w", err)
+		}
+		decoded = &zstdBody{decoder: decoder, body: resp.Body}
+	default:
+		return nil
+	}
+	resp.Body = decoded
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// gzipBody decodes a gzip encoded body, reading the gzip header on the first read so that empty bodies
+// can be closed without error
+type gzipBody struct {
+	body   io.ReadCloser
+	reader *gzip.Reader
+	err    error
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.reader == nil {
+		b.reader, b.err = gzip.NewReader(b.body)
+		if b.err != nil {
+			b.err = fmt.Errorf("failed to decode gzip response: %w", b.err)
+			return 0, b.err
+		}
+	}
+	return b.reader.Read(p)
+}
+
+func (b *gzipBody) Close() error {
+	return b.body.Close()
+}
+
+// zstdBody decodes a zstd encoded body
+type zstdBody struct {
+	decoder *zstd.Decoder
+	body    io.ReadCloser
+}
+
+func (b *zstdBody) Read(p []byte) (int, error) {
+	n, err := b.decoder.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		err = fmt.Errorf("failed to decode zstd response: %w", err)
+	}
+	return n, err
+}
+
+func (b *zstdBody) Close() error {
+	b.decoder.Close()
+	return b.body.Close()
+}
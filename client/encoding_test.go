@@ -0,0 +1,127 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Response size limits and compression", func() {
+	var (
+		server      *httptest.Server
+		encoding    string
+		statusBody  string
+		requestBody chan string
+	)
+
+	BeforeEach(func() {
+		encoding = ""
+		statusBody = `{"status": "done"}`
+		requestBody = make(chan string, 1)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /v1/search/live/status/job-1", func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Accept-Encoding")).To(Equal("gzip, zstd"))
+			var buf bytes.Buffer
+			switch encoding {
+			case "gzip":
+				zw := gzip.NewWriter(&buf)
+				_, _ = zw.Write([]byte(statusBody))
+				Expect(zw.Close()).To(Succeed())
+			case "zstd":
+				zw, err := zstd.NewWriter(&buf)
+				Expect(err).NotTo(HaveOccurred())
+				_, _ = zw.Write([]byte(statusBody))
+				Expect(zw.Close()).To(Succeed())
+			default:
+				buf.WriteString(statusBody)
+			}
+			if encoding != "" {
+				w.Header().Set("Content-Encoding", encoding)
+			}
+			_, _ = w.Write(buf.Bytes())
+		})
+		mux.HandleFunc("POST /v1/search/live", func(w http.ResponseWriter, r *http.Request) {
+			var body io.Reader = r.Body
+			if r.Header.Get("Content-Encoding") == "gzip" {
+				zr, err := gzip.NewReader(r.Body)
+				Expect(err).NotTo(HaveOccurred())
+				body = zr
+			}
+			data, err := io.ReadAll(body)
+			Expect(err).NotTo(HaveOccurred())
+			requestBody <- r.Header.Get("Content-Encoding") + " " + string(data)
+			_, _ = w.Write([]byte(`{"uuid": "job-1"}`))
+		})
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	DescribeTable("should decode compressed responses",
+		func(contentEncoding string) {
+			encoding = contentEncoding
+			client, err := NewClientWithOptions(server.URL, "test-token")
+			Expect(err).NotTo(HaveOccurred())
+
+			status, err := client.GetJobStatus("job-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.Status.String()).To(Equal("done"))
+		},
+		Entry("gzip", "gzip"),
+		Entry("zstd", "zstd"),
+		Entry("identity", ""),
+	)
+
+	DescribeTable("should fail responses larger than the maximum size",
+		func(contentEncoding string) {
+			encoding = contentEncoding
+			statusBody = `{"status": "done", "padding": "` + strings.Repeat("x", 1000) + `"}`
+			client, err := NewClientWithOptions(server.URL, "test-token", MaxResponseSize(100))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetJobStatus("job-1")
+			var tooLarge *ResponseTooLargeError
+			Expect(errors.As(err, &tooLarge)).To(BeTrue())
+			Expect(tooLarge.Limit).To(BeEquivalentTo(100))
+
+			client, err = NewClientWithOptions(server.URL, "test-token", MaxResponseSize(0))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.GetJobStatus("job-1")
+			Expect(err).NotTo(HaveOccurred())
+		},
+		Entry("uncompressed", ""),
+		Entry("after decompression", "gzip"),
+	)
+
+	It("should compress large request bodies when enabled", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.SearchTwitterAsync("golang")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(<-requestBody).To(HavePrefix(` {`))
+
+		client, err = NewClientWithOptions(server.URL, "test-token", CompressRequests(1))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.SearchTwitterAsync("golang")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(<-requestBody).To(SatisfyAll(HavePrefix("gzip {"), ContainSubstring("golang")))
+	})
+
+	It("should reject negative sizes", func() {
+		_, err := NewClientWithOptions(server.URL, "test-token", MaxResponseSize(-1))
+		Expect(err).To(HaveOccurred())
+		_, err = NewClientWithOptions(server.URL, "test-token", CompressRequests(-1))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/gopher-lab/gopher-client/types"
@@ -15,6 +16,17 @@ import (
 // Returns:
 //   - A pointer to ExtractionResponse containing the extracted search terms and metadata, or an error if the operation fails
 func (c *Client) ExtractSearchTerms(userInput string, maxTerms int) (*types.ExtractionResponse, error) {
+	return c.ExtractSearchTermsContext(context.Background(), userInput, maxTerms)
+}
+
+// ExtractSearchTermsContext is like ExtractSearchTerms but with a context for cancellation and the parent trace span
+func (c *Client) ExtractSearchTermsContext(ctx context.Context, userInput string, maxTerms int) (*types.ExtractionResponse, error) {
+	return traced(ctx, c, "ExtractSearchTerms", func(ctx context.Context) (*types.ExtractionResponse, error) {
+		return c.extractSearchTerms(ctx, userInput, maxTerms)
+	})
+}
+
+func (c *Client) extractSearchTerms(ctx context.Context, userInput string, maxTerms int) (*types.ExtractionResponse, error) {
 	// Set default maxTerms if not provided or invalid
 	if maxTerms <= 0 || maxTerms > 6 {
 		maxTerms = 4
@@ -31,7 +43,7 @@ func (c *Client) ExtractSearchTerms(userInput string, maxTerms int) (*types.Extr
 	}
 
 	var response types.ExtractionResponse
-	err = c.doImmediateRequest(c.BaseURL+"/v1/extraction", requestBody, &response)
+	err = c.post(ctx, c.BaseURL+"/v1/extraction", requestBody, &response)
 	if err != nil {
 		return nil, err
 	}
@@ -1,9 +1,9 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 
-	"github.com/gopher-lab/gopher-client/log"
     "github.com/masa-finance/tee-worker/v2/api/params"
     "github.com/masa-finance/tee-worker/v2/api/types"
 )
@@ -18,6 +18,37 @@ func (c *Client) SearchHybrid(
 	keywords []string,
 	operator string,
 	maxResults int,
+) ([]types.Document, error) {
+	return c.SearchHybridContext(context.Background(), query, sources, text, queryWeight, textWeight, keywords, operator, maxResults)
+}
+
+// SearchHybridContext is like SearchHybrid but with a context for cancellation and the parent trace span
+func (c *Client) SearchHybridContext(
+	ctx context.Context,
+	query string,
+	sources []types.Source,
+	text string,
+	queryWeight float64,
+	textWeight float64,
+	keywords []string,
+	operator string,
+	maxResults int,
+) ([]types.Document, error) {
+	return traced(ctx, c, "SearchHybrid", func(ctx context.Context) ([]types.Document, error) {
+		return c.searchHybrid(ctx, query, sources, text, queryWeight, textWeight, keywords, operator, maxResults)
+	})
+}
+
+func (c *Client) searchHybrid(
+	ctx context.Context,
+	query string,
+	sources []types.Source,
+	text string,
+	queryWeight float64,
+	textWeight float64,
+	keywords []string,
+	operator string,
+	maxResults int,
 ) ([]types.Document, error) {
 	requestBody, err := json.Marshal(params.HybridSearch{
 		TextQuery:       params.HybridQuery{Query: query, Weight: queryWeight},
@@ -28,14 +59,14 @@ func (c *Client) SearchHybrid(
 		MaxResults:      maxResults,
 	})
 	if err != nil {
-		log.Error("Error while performing hybrid web search", "query", query, "text", text, "error", err.Error())
+		c.log().ErrorContext(ctx, "Error while performing hybrid web search", "query", query, "text", text, "error", err.Error())
 		return nil, err
 	}
 
 	var results []types.Document
-	err = c.doImmediateRequest(c.BaseURL+"/v1/search/hybrid", requestBody, &results)
+	err = c.post(ctx, c.BaseURL+"/v1/search/hybrid", requestBody, &results)
 	if err != nil {
-		log.Error("Error while performing hybrid web search", "query", query, "text", text, "error", err.Error())
+		c.log().ErrorContext(ctx, "Error while performing hybrid web search", "query", query, "text", text, "error", err.Error())
 		return nil, err
 	}
 	return results, nil
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	"golang.org/x/sync/singleflight"
+)
+
+// idempotencyKeyHeader is the header of job submissions carrying the idempotency key
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// WithRequestID returns a context whose requests are sent with the request ID instead of a generated one. Pass
+// it to the ...Context methods, e.g. SearchTwitterAsyncContext, or to Submit and Run. Without it, every method
+// call generates a request ID shared by all of its requests.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// WithIdempotencyKey returns a context whose job submissions are sent with an Idempotency-Key header derived from
+// the key and the job, "<key>-<sha256 of the job request>", so that the different jobs of one call, e.g. the
+// searches of Research, don't share a key. If the server supports idempotency keys, resubmitting a job with the
+// key of a submission that failed or timed out returns the job of the first submission instead of creating
+// another. Pass it to the ...Context methods, e.g. SearchTwitterAsyncContext, or to Submit and Run.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey, key)
+}
+
+// DedupeSubmissions makes identical job submissions in flight at the same time share a single submission and
+// job. Submissions are identical if they have the same job type, arguments and idempotency key. The shared
+// submission is not canceled with the context of any one caller.
+func DedupeSubmissions() Option {
+	return func(o *Options) error {
+		o.dedupeSubmissions = true
+		return nil
+	}
+}
+
+// requestIDOf returns the request ID of ctx, or a new random ID for requests made outside a method call
+func requestIDOf(ctx context.Context) string {
+	if id, _ := ctx.Value(requestIDKey).(string); id != "" {
+		return id
+	}
+	return uuid.NewString()
+}
+
+// idempotencyKey returns the idempotency key of WithIdempotencyKey, or an empty string
+func idempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyKey).(string)
+	return key
+}
+
+// submissionKey returns the key of submitting body with the idempotency key, unique to the key and the job
+func submissionKey(key string, body []byte) string {
+	sum := sha256.Sum256(body)
+	return key + "-" + hex.EncodeToString(sum[:])
+}
+
+// submitDeduped submits a job, sharing the submission with identical submissions in flight
+func (c *Client) submitDeduped(ctx context.Context, body []byte) (*types.ResultResponse, error) {
+	if c.submissions == nil {
+		return c.submit(ctx, body)
+	}
+
+	ch := c.submissions.DoChan(submissionKey(idempotencyKey(ctx), body), func() (any, error) {
+		return c.submit(context.WithoutCancel(ctx), body)
+	})
+	select {
+	case result := <-ch:
+		resp, _ := result.Val.(*types.ResultResponse)
+		if resp != nil {
+			// Every caller gets its own copy of the shared response
+			shared := *resp
+			resp = &shared
+		}
+		if result.Shared {
+			c.log().DebugContext(ctx, "Job submission shared with an identical submission", "job_id", uuidOf(resp))
+		}
+		return resp, result.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// submit submits a job with the submission key of the idempotency key of ctx, if any
+func (c *Client) submit(ctx context.Context, body []byte) (*types.ResultResponse, error) {
+	jobType := jobTypeOf(body)
+	r := apiRequest{method: http.MethodPost, url: c.BaseURL + jobEndpoint, body: body}
+	if key := idempotencyKey(ctx); key != "" {
+		r.header = http.Header{idempotencyKeyHeader: {submissionKey(key, body)}}
+	}
+
+	var resp types.ResultResponse
+	if err := c.send(ctx, r, &resp); err != nil {
+		c.jobSubmitted(ctx, jobType, resp.UUID, err)
+		if isResponseError(err) {
+			return &resp, err
+		}
+		return nil, err
+	}
+	c.jobSubmitted(ctx, jobType, resp.UUID, nil)
+	c.storeSubmitted(ctx, resp.UUID, body)
+	return &resp, nil
+}
+
+func uuidOf(resp *types.ResultResponse) string {
+	if resp == nil {
+		return ""
+	}
+	return resp.UUID
+}
+
+// submissionGroup returns the group deduplicating submissions, nil if disabled
+func submissionGroup(enabled bool) *singleflight.Group {
+	if !enabled {
+		return nil
+	}
+	return &singleflight.Group{}
+}
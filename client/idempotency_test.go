@@ -0,0 +1,233 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/masa-finance/tee-worker/v2/api/args/twitter"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Request IDs and idempotency", func() {
+	var (
+		server      *httptest.Server
+		submissions atomic.Int32
+		headers     chan http.Header
+	)
+
+	BeforeEach(func() {
+		submissions.Store(0)
+		headers = make(chan http.Header, 100)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /v1/search/live", func(w http.ResponseWriter, r *http.Request) {
+			n := submissions.Add(1)
+			headers <- r.Header.Clone()
+			// Slow enough for concurrent submissions to overlap
+			time.Sleep(100 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"uuid": "job-` + string(rune('0'+n)) + `"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/status/{id}", func(w http.ResponseWriter, r *http.Request) {
+			headers <- r.Header.Clone()
+			_, _ = w.Write([]byte(`{"status": "done"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/result/{id}", func(w http.ResponseWriter, r *http.Request) {
+			headers <- r.Header.Clone()
+			_, _ = w.Write([]byte(`[{"id": "1", "content": "one"}]`))
+		})
+		mux.HandleFunc("GET /v1/search/live/status/failed", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+		})
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	searchArgs := func(query string) twitter.SearchArguments {
+		args := twitter.NewSearchArguments()
+		args.Query = query
+		return args
+	}
+
+	It("should send a new request ID with every request", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.SearchTwitterAsync("golang")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.SearchTwitterAsync("golang")
+		Expect(err).NotTo(HaveOccurred())
+
+		first, second := (<-headers).Get("X-Request-Id"), (<-headers).Get("X-Request-Id")
+		Expect(first).To(HaveLen(36))
+		Expect(second).NotTo(Equal(first))
+	})
+
+	It("should send the same request ID with every request of a call", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		docs, err := client.SearchTwitter("golang")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(docs).To(HaveLen(1))
+
+		Expect(headers).To(HaveLen(3))
+		submission := (<-headers).Get("X-Request-Id")
+		Expect(submission).To(HaveLen(36))
+		Expect((<-headers).Get("X-Request-Id")).To(Equal(submission))
+		Expect((<-headers).Get("X-Request-Id")).To(Equal(submission))
+	})
+
+	It("should send the request ID of the context", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = Submit(WithRequestID(context.Background(), "req-42"), client, types.TwitterJob, searchArgs("golang"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect((<-headers).Get("X-Request-Id")).To(Equal("req-42"))
+	})
+
+	It("should include the request ID in errors", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.getJobStatus(WithRequestID(context.Background(), "req-7"), "failed")
+		var apiErr *APIError
+		Expect(errors.As(err, &apiErr)).To(BeTrue())
+		Expect(apiErr.RequestID).To(Equal("req-7"))
+		Expect(err.Error()).To(HaveSuffix("(request ID req-7)"))
+
+		client, err = NewClientWithOptions("http://127.0.0.1:1", "test-token")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.getJobStatus(WithRequestID(context.Background(), "req-8"), "job-1")
+		Expect(err).To(MatchError(ContainSubstring("failed to do GET request to http://127.0.0.1:1/v1/search/live/status/job-1 (request ID req-8)")))
+	})
+
+	It("should send the idempotency key of the context with job submissions", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx := WithIdempotencyKey(context.Background(), "key-1")
+		_, err = Submit(ctx, client, types.TwitterJob, searchArgs("golang"))
+		Expect(err).NotTo(HaveOccurred())
+		first := (<-headers).Get("Idempotency-Key")
+		Expect(first).To(MatchRegexp("^key-1-[0-9a-f]{64}$"))
+
+		_, err = Submit(ctx, client, types.TwitterJob, searchArgs("golang"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect((<-headers).Get("Idempotency-Key")).To(Equal(first))
+
+		_, err = client.SearchTwitterAsync("golang")
+		Expect(err).NotTo(HaveOccurred())
+		Expect((<-headers).Get("Idempotency-Key")).To(BeEmpty())
+	})
+
+	It("should send the request ID and idempotency key of the context with named submissions", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx := WithRequestID(WithIdempotencyKey(context.Background(), "key-2"), "req-2")
+		_, err = client.SearchTwitterAsyncContext(ctx, "golang")
+		Expect(err).NotTo(HaveOccurred())
+
+		header := <-headers
+		Expect(header.Get("Idempotency-Key")).To(HavePrefix("key-2-"))
+		Expect(header.Get("X-Request-Id")).To(Equal("req-2"))
+	})
+
+	It("should send different idempotency keys for different jobs of one context", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx := WithIdempotencyKey(context.Background(), "key-3")
+		_, err = client.SearchTwitterAsyncContext(ctx, "golang")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.SearchTwitterAsyncContext(ctx, "rust")
+		Expect(err).NotTo(HaveOccurred())
+
+		first, second := (<-headers).Get("Idempotency-Key"), (<-headers).Get("Idempotency-Key")
+		Expect(first).To(HavePrefix("key-3-"))
+		Expect(second).To(HavePrefix("key-3-"))
+		Expect(second).NotTo(Equal(first))
+	})
+
+	It("should deduplicate named submissions by idempotency key", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token", DedupeSubmissions())
+		Expect(err).NotTo(HaveOccurred())
+
+		submit := func(key string) string {
+			resp, err := client.SearchTwitterAsyncContext(WithIdempotencyKey(context.Background(), key), "golang")
+			Expect(err).NotTo(HaveOccurred())
+			return resp.UUID
+		}
+		var wg sync.WaitGroup
+		uuids := make([]string, 4)
+		for i := range uuids {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				uuids[i] = submit("key-" + string(rune('a'+i%2)))
+			}()
+		}
+		wg.Wait()
+
+		Expect(submissions.Load()).To(BeEquivalentTo(2))
+		Expect(uuids[0]).To(Equal(uuids[2]))
+		Expect(uuids[1]).To(Equal(uuids[3]))
+		Expect(uuids[0]).NotTo(Equal(uuids[1]))
+	})
+
+	It("should share identical concurrent submissions when enabled", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token", DedupeSubmissions())
+		Expect(err).NotTo(HaveOccurred())
+
+		var wg sync.WaitGroup
+		uuids := make([]string, 5)
+		for i := range uuids {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				resp, err := Submit(context.Background(), client, types.TwitterJob, searchArgs("golang"))
+				Expect(err).NotTo(HaveOccurred())
+				uuids[i] = resp.UUID
+			}()
+		}
+		wg.Wait()
+
+		Expect(submissions.Load()).To(BeEquivalentTo(1))
+		Expect(uuids).To(HaveEach("job-1"))
+
+		_, err = Submit(context.Background(), client, types.TwitterJob, searchArgs("rust"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(submissions.Load()).To(BeEquivalentTo(2))
+	})
+
+	It("should submit identical submissions separately by default", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		var wg sync.WaitGroup
+		for range 3 {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, err := Submit(context.Background(), client, types.TwitterJob, searchArgs("golang"))
+				Expect(err).NotTo(HaveOccurred())
+			}()
+		}
+		wg.Wait()
+		Expect(submissions.Load()).To(BeEquivalentTo(3))
+	})
+})
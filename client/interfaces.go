@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+
+	gophertypes "github.com/gopher-lab/gopher-client/types"
+	"github.com/masa-finance/tee-worker/v2/api/args/linkedin"
+	"github.com/masa-finance/tee-worker/v2/api/args/reddit"
+	"github.com/masa-finance/tee-worker/v2/api/args/tiktok"
+	"github.com/masa-finance/tee-worker/v2/api/args/twitter"
+	"github.com/masa-finance/tee-worker/v2/api/args/web"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+)
+
+// The interfaces below group the methods of Client by capability, so that code can depend on the capabilities
+// it uses and substitute the mocks of the clientmock package in tests. Every method has its ...Context variant.
+// The mocks are generated from this file, run go generate ./clientmock after changing it.
+
+// WebScraper scrapes web pages
+type WebScraper interface {
+	ScrapeWeb(url string) ([]types.Document, error)
+	ScrapeWebContext(ctx context.Context, url string) ([]types.Document, error)
+	ScrapeWebAsync(url string) (*types.ResultResponse, error)
+	ScrapeWebAsyncContext(ctx context.Context, url string) (*types.ResultResponse, error)
+	ScrapeWebWithArgs(args web.ScraperArguments) ([]types.Document, error)
+	ScrapeWebWithArgsContext(ctx context.Context, args web.ScraperArguments) ([]types.Document, error)
+	ScrapeWebWithArgsAsync(args web.ScraperArguments) (*types.ResultResponse, error)
+	ScrapeWebWithArgsAsyncContext(ctx context.Context, args web.ScraperArguments) (*types.ResultResponse, error)
+}
+
+// TwitterSearcher searches Twitter
+type TwitterSearcher interface {
+	SearchTwitter(query string) ([]types.Document, error)
+	SearchTwitterContext(ctx context.Context, query string) ([]types.Document, error)
+	SearchTwitterAsync(query string) (*types.ResultResponse, error)
+	SearchTwitterAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error)
+	SearchTwitterWithArgs(args twitter.SearchArguments) ([]types.Document, error)
+	SearchTwitterWithArgsContext(ctx context.Context, args twitter.SearchArguments) ([]types.Document, error)
+	SearchTwitterWithArgsAsync(args twitter.SearchArguments) (*types.ResultResponse, error)
+	SearchTwitterWithArgsAsyncContext(ctx context.Context, args twitter.SearchArguments) (*types.ResultResponse, error)
+}
+
+// RedditSearcher searches and scrapes Reddit
+type RedditSearcher interface {
+	ScrapeRedditURL(url string) ([]types.Document, error)
+	ScrapeRedditURLContext(ctx context.Context, url string) ([]types.Document, error)
+	ScrapeRedditURLAsync(url string) (*types.ResultResponse, error)
+	ScrapeRedditURLAsyncContext(ctx context.Context, url string) (*types.ResultResponse, error)
+	SearchRedditPosts(query string) ([]types.Document, error)
+	SearchRedditPostsContext(ctx context.Context, query string) ([]types.Document, error)
+	SearchRedditPostsAsync(query string) (*types.ResultResponse, error)
+	SearchRedditPostsAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error)
+	SearchRedditUsers(query string) ([]types.Document, error)
+	SearchRedditUsersContext(ctx context.Context, query string) ([]types.Document, error)
+	SearchRedditUsersAsync(query string) (*types.ResultResponse, error)
+	SearchRedditUsersAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error)
+	SearchRedditCommunities(query string) ([]types.Document, error)
+	SearchRedditCommunitiesContext(ctx context.Context, query string) ([]types.Document, error)
+	SearchRedditCommunitiesAsync(query string) (*types.ResultResponse, error)
+	SearchRedditCommunitiesAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error)
+	SearchRedditWithArgs(args reddit.SearchArguments) ([]types.Document, error)
+	SearchRedditWithArgsContext(ctx context.Context, args reddit.SearchArguments) ([]types.Document, error)
+	SearchRedditWithArgsAsync(args reddit.SearchArguments) (*types.ResultResponse, error)
+	SearchRedditWithArgsAsyncContext(ctx context.Context, args reddit.SearchArguments) (*types.ResultResponse, error)
+}
+
+// TikTokClient transcribes and searches TikTok videos
+type TikTokClient interface {
+	TranscribeTikTok(url string) ([]types.Document, error)
+	TranscribeTikTokContext(ctx context.Context, url string) ([]types.Document, error)
+	TranscribeTikTokAsync(url string) (*types.ResultResponse, error)
+	TranscribeTikTokAsyncContext(ctx context.Context, url string) (*types.ResultResponse, error)
+	TranscribeTikTokWithArgs(args tiktok.TranscriptionArguments) ([]types.Document, error)
+	TranscribeTikTokWithArgsContext(ctx context.Context, args tiktok.TranscriptionArguments) ([]types.Document, error)
+	TranscribeTikTokWithArgsAsync(args tiktok.TranscriptionArguments) (*types.ResultResponse, error)
+	TranscribeTikTokWithArgsAsyncContext(ctx context.Context, args tiktok.TranscriptionArguments) (*types.ResultResponse, error)
+	SearchTikTok(query string) ([]types.Document, error)
+	SearchTikTokContext(ctx context.Context, query string) ([]types.Document, error)
+	SearchTikTokAsync(query string) (*types.ResultResponse, error)
+	SearchTikTokAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error)
+	SearchTikTokWithArgs(args tiktok.QueryArguments) ([]types.Document, error)
+	SearchTikTokWithArgsContext(ctx context.Context, args tiktok.QueryArguments) ([]types.Document, error)
+	SearchTikTokWithArgsAsync(args tiktok.QueryArguments) (*types.ResultResponse, error)
+	SearchTikTokWithArgsAsyncContext(ctx context.Context, args tiktok.QueryArguments) (*types.ResultResponse, error)
+	SearchTikTokTrending(sortBy string) ([]types.Document, error)
+	SearchTikTokTrendingContext(ctx context.Context, sortBy string) ([]types.Document, error)
+	SearchTikTokTrendingAsync(sortBy string) (*types.ResultResponse, error)
+	SearchTikTokTrendingAsyncContext(ctx context.Context, sortBy string) (*types.ResultResponse, error)
+	SearchTikTokTrendingWithArgs(args tiktok.TrendingArguments) ([]types.Document, error)
+	SearchTikTokTrendingWithArgsContext(ctx context.Context, args tiktok.TrendingArguments) ([]types.Document, error)
+	SearchTikTokTrendingWithArgsAsync(args tiktok.TrendingArguments) (*types.ResultResponse, error)
+	SearchTikTokTrendingWithArgsAsyncContext(ctx context.Context, args tiktok.TrendingArguments) (*types.ResultResponse, error)
+}
+
+// LinkedInSearcher searches LinkedIn profiles
+type LinkedInSearcher interface {
+	SearchLinkedIn(query string) ([]types.Document, error)
+	SearchLinkedInContext(ctx context.Context, query string) ([]types.Document, error)
+	SearchLinkedInAsync(query string) (*types.ResultResponse, error)
+	SearchLinkedInAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error)
+	SearchLinkedInWithArgs(args linkedin.ProfileArguments) ([]types.Document, error)
+	SearchLinkedInWithArgsContext(ctx context.Context, args linkedin.ProfileArguments) ([]types.Document, error)
+	SearchLinkedInWithArgsAsync(args linkedin.ProfileArguments) (*types.ResultResponse, error)
+	SearchLinkedInWithArgsAsyncContext(ctx context.Context, args linkedin.ProfileArguments) (*types.ResultResponse, error)
+}
+
+// IndexSearcher searches the indexed documents
+type IndexSearcher interface {
+	SearchSimilarity(query string, sources []types.Source, keywords []string, operator string, maxResults int) ([]types.Document, error)
+	SearchSimilarityContext(ctx context.Context, query string, sources []types.Source, keywords []string, operator string, maxResults int) ([]types.Document, error)
+	SearchHybrid(query string, sources []types.Source, text string, queryWeight float64, textWeight float64, keywords []string, operator string, maxResults int) ([]types.Document, error)
+	SearchHybridContext(ctx context.Context, query string, sources []types.Source, text string, queryWeight float64, textWeight float64, keywords []string, operator string, maxResults int) ([]types.Document, error)
+}
+
+// Analyzer analyzes data and queries with the LLM endpoints
+type Analyzer interface {
+	AnalyzeData(data []string, prompt string) (*gophertypes.AnalysisResponse, error)
+	AnalyzeDataContext(ctx context.Context, data []string, prompt string) (*gophertypes.AnalysisResponse, error)
+	AnalyzeDataWithArgs(data []string, prompt string, model string, app bool, chatHistory []gophertypes.ChatHistoryItem, currentQuery string) (*gophertypes.AnalysisResponse, error)
+	AnalyzeDataWithArgsContext(ctx context.Context, data []string, prompt string, model string, app bool, chatHistory []gophertypes.ChatHistoryItem, currentQuery string) (*gophertypes.AnalysisResponse, error)
+	AnalyzeDataChunked(data []string, prompt string, opts ChunkedAnalysisOptions) (*gophertypes.AnalysisResponse, error)
+	AnalyzeDataChunkedContext(ctx context.Context, data []string, prompt string, opts ChunkedAnalysisOptions) (*gophertypes.AnalysisResponse, error)
+	ExtractSearchTerms(userInput string, maxTerms int) (*gophertypes.ExtractionResponse, error)
+	ExtractSearchTermsContext(ctx context.Context, userInput string, maxTerms int) (*gophertypes.ExtractionResponse, error)
+	ContextualizeQuery(currentQuery string, chatHistory []gophertypes.ChatHistoryItem, maxHistoryItems int) (*gophertypes.ContextualizeResponse, error)
+	ContextualizeQueryContext(ctx context.Context, currentQuery string, chatHistory []gophertypes.ChatHistoryItem, maxHistoryItems int) (*gophertypes.ContextualizeResponse, error)
+	GetModels() ([]gophertypes.Model, error)
+	GetModelsContext(ctx context.Context) ([]gophertypes.Model, error)
+	GetAvailableModels() ([]string, error)
+	GetAvailableModelsContext(ctx context.Context) ([]string, error)
+}
+
+// JobTracker follows submitted jobs and gets their results
+type JobTracker interface {
+	GetJobStatus(jobID string) (*types.IndexerJobResult, error)
+	GetJobStatusContext(ctx context.Context, jobID string) (*types.IndexerJobResult, error)
+	GetResult(jobID string, receiver any) error
+	GetResultContext(ctx context.Context, jobID string, receiver any) error
+	GetVerifiedResult(jobID string) (*VerifiedResult, error)
+	GetVerifiedResultContext(ctx context.Context, jobID string) (*VerifiedResult, error)
+	WaitForJobCompletion(jobID string) ([]types.Document, error)
+	WaitForJobCompletionContext(ctx context.Context, jobID string) ([]types.Document, error)
+}
+
+// MetricsReader reads the collection metrics
+type MetricsReader interface {
+	GetMetrics(source string, refresh bool) (*types.CollectionStats, error)
+	GetMetricsContext(ctx context.Context, source string, refresh bool) (*types.CollectionStats, error)
+	GetAllMetrics(refresh bool) ([]types.CollectionStats, error)
+	GetAllMetricsContext(ctx context.Context, refresh bool) ([]types.CollectionStats, error)
+}
+
+var (
+	_ WebScraper       = (*Client)(nil)
+	_ TwitterSearcher  = (*Client)(nil)
+	_ RedditSearcher   = (*Client)(nil)
+	_ TikTokClient     = (*Client)(nil)
+	_ LinkedInSearcher = (*Client)(nil)
+	_ IndexSearcher    = (*Client)(nil)
+	_ Analyzer         = (*Client)(nil)
+	_ JobTracker       = (*Client)(nil)
+	_ MetricsReader    = (*Client)(nil)
+)
@@ -0,0 +1,325 @@
+package client
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/masa-finance/tee-worker/v2/api/types"
+)
+
+// ErrJobNotFound is returned by a JobStore for unknown jobs
+var ErrJobNotFound = errors.New("job not found")
+
+const (
+	// maxResumedJobs bounds the number of jobs ResumePending waits for concurrently
+	maxResumedJobs = 32
+	// DefaultJobRetention is how long finished jobs are kept in the job store by default
+	DefaultJobRetention = 24 * time.Hour
+	// jobPruneInterval is how often the job store is pruned at most
+	jobPruneInterval = time.Minute
+)
+
+// StoredJob is a submitted job recorded in a JobStore
+type StoredJob struct {
+	UUID        string          `json:"uuid"`
+	Type        types.JobType   `json:"type"`
+	Arguments   json.RawMessage `json:"arguments,omitempty"`
+	Status      types.JobStatus `json:"status"`
+	Error       string          `json:"error,omitempty"`
+	SubmittedAt time.Time       `json:"submitted_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// Finished reports whether the job is done or failed
+func (j StoredJob) Finished() bool {
+	return j.Status.IsDone() || j.Status == types.JobStatusError || j.Status == types.JobStatusRetryError
+}
+
+// JobStore records the jobs submitted by a client, so that ResumePending can reattach to them after a restart.
+// Implementations must be safe for concurrent use.
+type JobStore interface {
+	// SaveJob inserts the job or replaces the job with the same UUID
+	SaveJob(ctx context.Context, job StoredJob) error
+	// Job returns the job with the UUID, or an error wrapping ErrJobNotFound
+	Job(ctx context.Context, uuid string) (StoredJob, error)
+	// PendingJobs returns the jobs that are not finished, in submission order
+	PendingJobs(ctx context.Context) ([]StoredJob, error)
+	// DeleteJob removes the job with the UUID, removing an unknown job is not an error
+	DeleteJob(ctx context.Context, uuid string) error
+	// Prune removes the finished jobs last updated before the time and returns the number of removed jobs
+	Prune(ctx context.Context, before time.Time) (int, error)
+}
+
+// FileJobStore is a JobStore keeping the jobs in a JSON file, rewritten on every change. It suits clients with up
+// to a few thousand jobs in the store, use a database such as the sqlitestore package for more.
+type FileJobStore struct {
+	path string
+	mu   sync.Mutex
+	jobs map[string]StoredJob
+}
+
+var _ JobStore = (*FileJobStore)(nil)
+
+// NewFileJobStore opens the JSON job store at path, which is created on the first change if it does not exist
+func NewFileJobStore(path string) (*FileJobStore, error) {
+	s := &FileJobStore{path: path, jobs: make(map[string]StoredJob)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job store %s: %w", path, err)
+	}
+
+	var jobs []StoredJob
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to parse job store %s: %w", path, err)
+	}
+	for _, job := range jobs {
+		s.jobs[job.UUID] = job
+	}
+	return s, nil
+}
+
+// SaveJob saves the job and rewrites the file
+func (s *FileJobStore) SaveJob(_ context.Context, job StoredJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, existed := s.jobs[job.UUID]
+	s.jobs[job.UUID] = job
+	if err := s.write(); err != nil {
+		if existed {
+			s.jobs[job.UUID] = previous
+		} else {
+			delete(s.jobs, job.UUID)
+		}
+		return err
+	}
+	return nil
+}
+
+// Job returns the job with the UUID
+func (s *FileJobStore) Job(_ context.Context, uuid string) (StoredJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[uuid]
+	if !ok {
+		return StoredJob{}, fmt.Errorf("%w: %s", ErrJobNotFound, uuid)
+	}
+	return job, nil
+}
+
+// PendingJobs returns the unfinished jobs
+func (s *FileJobStore) PendingJobs(_ context.Context) ([]StoredJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pending []StoredJob
+	for _, job := range s.sorted() {
+		if !job.Finished() {
+			pending = append(pending, job)
+		}
+	}
+	return pending, nil
+}
+
+// DeleteJob removes the job and rewrites the file
+func (s *FileJobStore) DeleteJob(_ context.Context, uuid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[uuid]
+	if !ok {
+		return nil
+	}
+	delete(s.jobs, uuid)
+	if err := s.write(); err != nil {
+		s.jobs[uuid] = job
+		return err
+	}
+	return nil
+}
+
+// Prune removes the finished jobs last updated before the time and rewrites the file once
+func (s *FileJobStore) Prune(_ context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pruned := make(map[string]StoredJob)
+	for uuid, job := range s.jobs {
+		if job.Finished() && job.UpdatedAt.Before(before) {
+			pruned[uuid] = job
+			delete(s.jobs, uuid)
+		}
+	}
+	if len(pruned) == 0 {
+		return 0, nil
+	}
+	if err := s.write(); err != nil {
+		maps.Copy(s.jobs, pruned)
+		return 0, err
+	}
+	return len(pruned), nil
+}
+
+// sorted returns the jobs in submission order
+func (s *FileJobStore) sorted() []StoredJob {
+	jobs := make([]StoredJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	slices.SortFunc(jobs, func(a, b StoredJob) int {
+		if c := a.SubmittedAt.Compare(b.SubmittedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.UUID, b.UUID)
+	})
+	return jobs
+}
+
+// write replaces the file atomically with the jobs
+func (s *FileJobStore) write() error {
+	data, err := json.MarshalIndent(s.sorted(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write job store %s: %w", s.path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write job store %s: %w", s.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write job store %s: %w", s.path, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write job store %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// storeSubmitted records a submitted job in the job store
+func (c *Client) storeSubmitted(ctx context.Context, jobID string, requestBody []byte) {
+	if c.store == nil || jobID == "" {
+		return
+	}
+	var params struct {
+		JobType   types.JobType   `json:"type"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	_ = json.Unmarshal(requestBody, &params)
+
+	now := time.Now()
+	job := StoredJob{
+		UUID:        jobID,
+		Type:        params.JobType,
+		Arguments:   params.Arguments,
+		Status:      types.JobStatusReceived,
+		SubmittedAt: now,
+		UpdatedAt:   now,
+	}
+	if err := c.store.SaveJob(ctx, job); err != nil {
+		c.log().WarnContext(ctx, "Failed to store submitted job", slog.String("job_id", jobID), slog.String("error", err.Error()))
+	}
+}
+
+// storeStatus records the status of a job in the job store, ignoring jobs that are not stored and unchanged
+// statuses. Finished jobs are pruned after the job retention.
+func (c *Client) storeStatus(ctx context.Context, jobID string, status types.JobStatus, jobErr string) {
+	if c.store == nil {
+		return
+	}
+	job, err := c.store.Job(ctx, jobID)
+	if errors.Is(err, ErrJobNotFound) || err == nil && job.Status == status && job.Error == jobErr {
+		return
+	}
+	if err == nil {
+		job.Status = status
+		job.Error = jobErr
+		job.UpdatedAt = time.Now()
+		err = c.store.SaveJob(ctx, job)
+	}
+	if err != nil {
+		c.log().WarnContext(ctx, "Failed to store job status", slog.String("job_id", jobID), slog.String("error", err.Error()))
+		return
+	}
+	if job.Finished() {
+		c.pruneJobs(ctx)
+	}
+}
+
+// pruneJobs removes the jobs that finished before the job retention from the job store, at most once per
+// jobPruneInterval
+func (c *Client) pruneJobs(ctx context.Context) {
+	if c.jobRetention < 0 {
+		return
+	}
+	now := time.Now()
+	last := c.lastPrune.Load()
+	if now.UnixNano()-last < int64(jobPruneInterval) || !c.lastPrune.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	pruned, err := c.store.Prune(ctx, now.Add(-c.jobRetention))
+	if err != nil {
+		c.log().WarnContext(ctx, "Failed to prune job store", slog.String("error", err.Error()))
+		return
+	}
+	if pruned > 0 {
+		c.log().DebugContext(ctx, "Pruned finished jobs from the job store", slog.Int("jobs", pruned))
+	}
+}
+
+// ResumedJob is a job resumed by ResumePending with its results, or the error waiting for them
+type ResumedJob struct {
+	Job  StoredJob
+	Docs []types.Document
+	Err  error
+}
+
+// ResumePending reattaches to the unfinished jobs of the job store, e.g. after a restart, and waits for them
+// like WaitForJobCompletion, returning the results of every job. Jobs still unfinished when waiting for them
+// times out stay pending, to be resumed again.
+func (c *Client) ResumePending(ctx context.Context) ([]ResumedJob, error) {
+	return traced(ctx, c, "ResumePending", func(ctx context.Context) ([]ResumedJob, error) {
+		if c.store == nil {
+			return nil, errors.New("no job store is configured, see the PersistJobs option")
+		}
+		pending, err := c.store.PendingJobs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pending jobs: %w", err)
+		}
+		c.log().InfoContext(ctx, "Resuming pending jobs", slog.Int("jobs", len(pending)))
+
+		resumed := make([]ResumedJob, len(pending))
+		sem := make(chan struct{}, maxResumedJobs)
+		var wg sync.WaitGroup
+		for i, job := range pending {
+			if c.instrumented() {
+				c.trackJob(job.UUID, string(job.Type), job.SubmittedAt)
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				docs, err := c.waitForJobCompletion(ctx, job.UUID, nil)
+				if stored, storeErr := c.store.Job(ctx, job.UUID); storeErr == nil {
+					job = stored
+				}
+				resumed[i] = ResumedJob{Job: job, Docs: docs, Err: err}
+			}()
+		}
+		wg.Wait()
+		return resumed, nil
+	})
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Job store", func() {
+	var (
+		server *httptest.Server
+		path   string
+		store  *FileJobStore
+	)
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /v1/search/live", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"uuid": "job-1"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/status/job-1", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"status": "done"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/result/job-1", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`[{"id": "1", "content": "one"}]`))
+		})
+		mux.HandleFunc("GET /v1/search/live/status/job-2", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"status": "error", "error": "rate limited"}`))
+		})
+		server = httptest.NewServer(mux)
+
+		path = filepath.Join(GinkgoT().TempDir(), "jobs.json")
+		var err error
+		store, err = NewFileJobStore(path)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should record submitted jobs with their arguments", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token", PersistJobs(store))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.SearchTwitterAsync("golang")
+		Expect(err).NotTo(HaveOccurred())
+
+		reopened, err := NewFileJobStore(path)
+		Expect(err).NotTo(HaveOccurred())
+		job, err := reopened.Job(context.Background(), "job-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.Type).To(Equal(types.TwitterJob))
+		Expect(job.Status).To(Equal(types.JobStatusReceived))
+		var args map[string]any
+		Expect(json.Unmarshal(job.Arguments, &args)).To(Succeed())
+		Expect(args).To(HaveKeyWithValue("query", "golang"))
+
+		pending, err := reopened.PendingJobs(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(HaveLen(1))
+	})
+
+	It("should resume pending jobs after a restart", func() {
+		ctx := context.Background()
+		submitted := time.Now().Add(-time.Minute)
+		Expect(store.SaveJob(ctx, StoredJob{UUID: "job-1", Type: types.WebJob, Status: types.JobStatusActive, SubmittedAt: submitted})).To(Succeed())
+		Expect(store.SaveJob(ctx, StoredJob{UUID: "job-2", Type: types.WebJob, Status: types.JobStatusReceived, SubmittedAt: submitted.Add(time.Second)})).To(Succeed())
+		Expect(store.SaveJob(ctx, StoredJob{UUID: "job-3", Type: types.WebJob, Status: types.JobStatusDone, SubmittedAt: submitted})).To(Succeed())
+
+		reopened, err := NewFileJobStore(path)
+		Expect(err).NotTo(HaveOccurred())
+		client, err := NewClientWithOptions(server.URL, "test-token", PersistJobs(reopened))
+		Expect(err).NotTo(HaveOccurred())
+
+		resumed, err := client.ResumePending(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resumed).To(HaveLen(2))
+
+		Expect(resumed[0].Job.UUID).To(Equal("job-1"))
+		Expect(resumed[0].Err).NotTo(HaveOccurred())
+		Expect(resumed[0].Docs).To(HaveLen(1))
+		Expect(resumed[0].Job.Status).To(Equal(types.JobStatusDone))
+
+		Expect(resumed[1].Job.UUID).To(Equal("job-2"))
+		Expect(resumed[1].Err).To(MatchError(ContainSubstring("rate limited")))
+		Expect(resumed[1].Job.Status).To(Equal(types.JobStatusError))
+		Expect(resumed[1].Job.Error).To(Equal("rate limited"))
+
+		pending, err := reopened.PendingJobs(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(BeEmpty())
+	})
+
+	It("should delete jobs and prune finished jobs", func() {
+		ctx := context.Background()
+		now := time.Now()
+		old := now.Add(-2 * time.Hour)
+		Expect(store.SaveJob(ctx, StoredJob{UUID: "done", Type: types.WebJob, Status: types.JobStatusDone, SubmittedAt: old, UpdatedAt: old})).To(Succeed())
+		Expect(store.SaveJob(ctx, StoredJob{UUID: "failed", Type: types.WebJob, Status: types.JobStatusError, SubmittedAt: old, UpdatedAt: old})).To(Succeed())
+		Expect(store.SaveJob(ctx, StoredJob{UUID: "recent", Type: types.WebJob, Status: types.JobStatusDone, SubmittedAt: old, UpdatedAt: now})).To(Succeed())
+		Expect(store.SaveJob(ctx, StoredJob{UUID: "pending", Type: types.WebJob, Status: types.JobStatusActive, SubmittedAt: old, UpdatedAt: old})).To(Succeed())
+
+		Expect(store.DeleteJob(ctx, "pending")).To(Succeed())
+		Expect(store.DeleteJob(ctx, "unknown")).To(Succeed())
+		pruned, err := store.Prune(ctx, now.Add(-time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(Equal(2))
+
+		reopened, err := NewFileJobStore(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reopened.jobs).To(HaveLen(1))
+		Expect(reopened.jobs).To(HaveKey("recent"))
+	})
+
+	It("should prune jobs finished before the retention", func() {
+		ctx := context.Background()
+		old := time.Now().Add(-2 * time.Hour)
+		Expect(store.SaveJob(ctx, StoredJob{UUID: "old", Type: types.WebJob, Status: types.JobStatusDone, SubmittedAt: old, UpdatedAt: old})).To(Succeed())
+		client, err := NewClientWithOptions(server.URL, "test-token", PersistJobs(store), JobRetention(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.SearchTwitter("golang")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = store.Job(ctx, "old")
+		Expect(err).To(MatchError(ErrJobNotFound))
+		job, err := store.Job(ctx, "job-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.Status).To(Equal(types.JobStatusDone))
+	})
+
+	It("should report unknown jobs", func() {
+		_, err := store.Job(context.Background(), "unknown")
+		Expect(err).To(MatchError(ErrJobNotFound))
+	})
+
+	It("should reject corrupt files", func() {
+		Expect(os.WriteFile(path, []byte("not json"), 0600)).To(Succeed())
+		_, err := NewFileJobStore(path)
+		Expect(err).To(MatchError(ContainSubstring("failed to parse job store")))
+	})
+
+	It("should require a job store to resume jobs", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.ResumePending(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("PersistJobs")))
+	})
+})
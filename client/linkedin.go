@@ -1,58 +1,60 @@
 package client
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 
     "github.com/masa-finance/tee-worker/v2/api/args/linkedin"
-    "github.com/masa-finance/tee-worker/v2/api/params"
     "github.com/masa-finance/tee-worker/v2/api/types"
 )
 
 // SearchLinkedInWithArgsAsync searches LinkedIn with custom arguments and returns a job ID
 func (c *Client) SearchLinkedInWithArgsAsync(args linkedin.ProfileArguments) (*types.ResultResponse, error) {
-	body, err := json.Marshal(params.LinkedIn{
-		JobType: types.LinkedInJob,
-		Args:    &args,
+	return c.SearchLinkedInWithArgsAsyncContext(context.Background(), args)
+}
+
+// SearchLinkedInWithArgsAsyncContext is like SearchLinkedInWithArgsAsync but with a context for cancellation and the parent trace span
+func (c *Client) SearchLinkedInWithArgsAsyncContext(ctx context.Context, args linkedin.ProfileArguments) (*types.ResultResponse, error) {
+	return traced(ctx, c, "SearchLinkedInWithArgsAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.LinkedInJob, args))
 	})
-	if err != nil {
-		return nil, err
-	}
-	return c.doRequest(c.BaseURL+jobEndpoint, body)
 }
 
 // SearchLinkedInAsync performs a LinkedIn search job and returns a job ID
 func (c *Client) SearchLinkedInAsync(query string) (*types.ResultResponse, error) {
+	return c.SearchLinkedInAsyncContext(context.Background(), query)
+}
+
+// SearchLinkedInAsyncContext is like SearchLinkedInAsync but with a context for cancellation and the parent trace span
+func (c *Client) SearchLinkedInAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error) {
 	args := linkedin.NewProfileArguments()
 	args.Query = query
-
-	res, err := c.SearchLinkedInWithArgsAsync(args)
-	if err != nil {
-		return nil, err
-	}
-	return res, nil
+	return traced(ctx, c, "SearchLinkedInAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.LinkedInJob, args))
+	})
 }
 
 // SearchLinkedIn performs a LinkedIn search and waits for completion, returning results directly
 func (c *Client) SearchLinkedIn(query string) ([]types.Document, error) {
-	resp, err := c.SearchLinkedInAsync(query)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
+	return c.SearchLinkedInContext(context.Background(), query)
+}
+
+// SearchLinkedInContext is like SearchLinkedIn but with a context for cancellation and the parent trace span
+func (c *Client) SearchLinkedInContext(ctx context.Context, query string) ([]types.Document, error) {
+	args := linkedin.NewProfileArguments()
+	args.Query = query
+	return traced(ctx, c, "SearchLinkedIn", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.LinkedInJob, args))
+	})
 }
 
 // SearchLinkedInWithArgs searches LinkedIn with custom arguments and waits for completion, returning results directly
 func (c *Client) SearchLinkedInWithArgs(args linkedin.ProfileArguments) ([]types.Document, error) {
-	resp, err := c.SearchLinkedInWithArgsAsync(args)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
+	return c.SearchLinkedInWithArgsContext(context.Background(), args)
+}
+
+// SearchLinkedInWithArgsContext is like SearchLinkedInWithArgs but with a context for cancellation and the parent trace span
+func (c *Client) SearchLinkedInWithArgsContext(ctx context.Context, args linkedin.ProfileArguments) ([]types.Document, error) {
+	return traced(ctx, c, "SearchLinkedInWithArgs", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.LinkedInJob, args))
+	})
 }
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// requestIDHeader is the header carrying the request ID, sent with every request and echoed or replaced by the server
+const requestIDHeader = "X-Request-Id"
+
+// discardLogger is used when no logger is configured, keeping the library silent
+var discardLogger = slog.New(slog.DiscardHandler)
+
+type contextKey int
+
+const (
+	jobIDKey contextKey = iota
+	requestIDKey
+	idempotencyKeyKey
+	targetHostKey
+)
+
+// withJobID returns a context whose requests are logged with the job ID
+func withJobID(ctx context.Context, jobID string) context.Context {
+	return context.WithValue(ctx, jobIDKey, jobID)
+}
+
+// jobIDFrom returns the job ID of withJobID, or an empty string
+func jobIDFrom(ctx context.Context) string {
+	jobID, _ := ctx.Value(jobIDKey).(string)
+	return jobID
+}
+
+// log returns the client's logger, or a logger discarding all records if none is configured
+func (c *Client) log() *slog.Logger {
+	if c.logger == nil {
+		return discardLogger
+	}
+	return c.logger
+}
+
+// logRequest logs a finished HTTP request with the job and request IDs
+func (c *Client) logRequest(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+	if c.logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", endpointLabel(req.URL.Path)),
+		slog.Duration("duration", duration),
+	}
+	if jobID := jobIDFrom(req.Context()); jobID != "" {
+		attrs = append(attrs, slog.String("job_id", jobID))
+	}
+	if err != nil {
+		if requestID := req.Header.Get(requestIDHeader); requestID != "" {
+			attrs = append(attrs, slog.String("request_id", requestID))
+		}
+		attrs = append(attrs, slog.String("error", err.Error()))
+		c.logger.LogAttrs(req.Context(), slog.LevelWarn, "HTTP request failed", attrs...)
+		return
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	if requestID := responseRequestID(resp); requestID != "" {
+		attrs = append(attrs, slog.String("request_id", requestID))
+	}
+	level := slog.LevelDebug
+	if resp.StatusCode >= 400 {
+		level = slog.LevelWarn
+	}
+	c.logger.LogAttrs(req.Context(), level, "HTTP request", attrs...)
+}
+
+// responseRequestID returns the request ID of the server, or the request ID sent by the client
+func responseRequestID(resp *http.Response) string {
+	if requestID := resp.Header.Get(requestIDHeader); requestID != "" {
+		return requestID
+	}
+	if resp.Request != nil {
+		return resp.Request.Header.Get(requestIDHeader)
+	}
+	return ""
+}
@@ -0,0 +1,186 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/gopher-lab/gopher-client/redact"
+	"github.com/gopher-lab/gopher-client/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes by the log handler
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// records returns the logged JSON records
+func (b *syncBuffer) records() []map[string]any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+		records = append(records, record)
+	}
+	return records
+}
+
+var _ = Describe("Logging", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /v1/search/live", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Request-Id", "req-1")
+			_, _ = w.Write([]byte(`{"uuid": "job-1"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/status/job-1", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"status": "done"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/result/job-1", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`[]`))
+		})
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should log requests and the job lifecycle with job and request IDs", func() {
+		var output syncBuffer
+		client, err := NewClientWithOptions(server.URL, "test-token",
+			LogHandler(slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.SearchTwitter("golang")
+		Expect(err).NotTo(HaveOccurred())
+
+		records := output.records()
+		var messages []string
+		for _, record := range records {
+			messages = append(messages, record["msg"].(string))
+		}
+		Expect(messages).To(Equal([]string{
+			"HTTP request", "Job submitted", "HTTP request", "Job polled", "HTTP request", "Job finished",
+		}))
+
+		Expect(records[0]).To(HaveKeyWithValue("request_id", "req-1"))
+		Expect(records[0]).To(HaveKeyWithValue("endpoint", "/v1/search/live"))
+		Expect(records[1]).To(HaveKeyWithValue("job_id", "job-1"))
+		Expect(records[1]).To(HaveKeyWithValue("job_type", "twitter"))
+		Expect(records[2]).To(HaveKeyWithValue("job_id", "job-1"))
+		Expect(records[2]).To(HaveKeyWithValue("endpoint", "/v1/search/live/status"))
+		Expect(records[5]).To(HaveKeyWithValue("outcome", "done"))
+	})
+
+	It("should log failures at warn level", func() {
+		var output syncBuffer
+		client, err := NewClientWithOptions(server.URL, "test-token",
+			Logger(slog.New(slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelWarn}))))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.GetJobStatus("unknown")
+		Expect(err).To(HaveOccurred())
+
+		records := output.records()
+		Expect(records).To(HaveLen(1))
+		Expect(records[0]).To(HaveKeyWithValue("level", "WARN"))
+		Expect(records[0]).To(HaveKeyWithValue("status", float64(http.StatusNotFound)))
+	})
+
+	It("should reject a nil log handler", func() {
+		_, err := NewClientWithOptions(server.URL, "test-token", LogHandler(nil))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Redaction", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "bad request", "echo": {"prompt": "summarize my diary"}}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should redact request and response bodies in errors by default", func() {
+		client := NewClient(server.URL, "test-token")
+
+		_, err := client.SearchTwitterAsync("my secret plan")
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Status code 400"))
+		Expect(err.Error()).NotTo(ContainSubstring("my secret plan"))
+		Expect(err.Error()).NotTo(ContainSubstring("summarize my diary"))
+		Expect(err.Error()).To(ContainSubstring(redact.Hash("my secret plan")))
+	})
+
+	It("should hash the queries of analysis requests in errors", func() {
+		client := NewClient(server.URL, "test-token")
+		history := []types.ChatHistoryItem{{Query: "my earlier plan"}}
+
+		_, err := client.ExtractSearchTerms("my secret plan", 3)
+		Expect(err).To(MatchError(ContainSubstring(redact.Hash("my secret plan"))))
+		Expect(err.Error()).NotTo(ContainSubstring("my secret plan"))
+
+		_, err = client.ContextualizeQuery("my secret plan", history, 1)
+		Expect(err).To(MatchError(ContainSubstring("Status code 400")))
+		Expect(err.Error()).NotTo(ContainSubstring("my secret plan"))
+		Expect(err.Error()).NotTo(ContainSubstring("my earlier plan"))
+
+		_, err = client.AnalyzeDataWithArgs([]string{"my private tweet"}, "prompt", "", false, history, "my secret plan")
+		Expect(err).To(MatchError(ContainSubstring("Status code 400")))
+		Expect(err.Error()).NotTo(ContainSubstring("my private tweet"))
+		Expect(err.Error()).NotTo(ContainSubstring("my secret plan"))
+	})
+
+	It("should keep bodies when redaction is disabled", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token", Redaction(redact.None()))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.SearchTwitterAsync("my secret plan")
+
+		Expect(err).To(MatchError(ContainSubstring("my secret plan")))
+	})
+
+	It("should redact log attributes", func() {
+		var output syncBuffer
+		client, err := NewClientWithOptions(server.URL, "test-token",
+			LogHandler(slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.SearchSimilarity("my secret plan", nil, nil, "", 10)
+		Expect(err).To(HaveOccurred())
+
+		records := output.records()
+		Expect(records).NotTo(BeEmpty())
+		last := records[len(records)-1]
+		Expect(last).To(HaveKeyWithValue("msg", "Error while performing similarity search"))
+		Expect(last).To(HaveKeyWithValue("query", redact.Hash("my secret plan")))
+	})
+})
@@ -1,32 +1,46 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/gopher-lab/gopher-client/log"
     "github.com/masa-finance/tee-worker/v2/api/types"
 )
 
 func (c *Client) GetAllMetrics(refresh bool) ([]types.CollectionStats, error) {
-	url := fmt.Sprintf("%s/v1/metrics?refresh=%t", c.BaseURL, refresh)
-
-	var stats []types.CollectionStats
-	err := c.doMetricsRequest(url, &stats)
-	if err != nil {
-		log.Error("Error while getting all metrics", "refresh", refresh, "error", err.Error())
-		return nil, err
-	}
-	return stats, nil
+	return c.GetAllMetricsContext(context.Background(), refresh)
+}
+
+// GetAllMetricsContext is like GetAllMetrics but with a context for cancellation and the parent trace span
+func (c *Client) GetAllMetricsContext(ctx context.Context, refresh bool) ([]types.CollectionStats, error) {
+	return traced(ctx, c, "GetAllMetrics", func(ctx context.Context) ([]types.CollectionStats, error) {
+		url := fmt.Sprintf("%s/v1/metrics?refresh=%t", c.BaseURL, refresh)
+
+		var stats []types.CollectionStats
+		err := c.get(ctx, url, &stats)
+		if err != nil {
+			c.log().ErrorContext(ctx, "Error while getting all metrics", "refresh", refresh, "error", err.Error())
+			return nil, err
+		}
+		return stats, nil
+	})
 }
 
 func (c *Client) GetMetrics(source string, refresh bool) (*types.CollectionStats, error) {
-	url := fmt.Sprintf("%s/v1/metrics/%s?refresh=%t", c.BaseURL, source, refresh)
-
-	var stats types.CollectionStats
-	err := c.doMetricsRequest(url, &stats)
-	if err != nil {
-		log.Error("Error while getting metrics", "source", source, "refresh", refresh, "error", err.Error())
-		return nil, err
-	}
-	return &stats, nil
+	return c.GetMetricsContext(context.Background(), source, refresh)
+}
+
+// GetMetricsContext is like GetMetrics but with a context for cancellation and the parent trace span
+func (c *Client) GetMetricsContext(ctx context.Context, source string, refresh bool) (*types.CollectionStats, error) {
+	return traced(ctx, c, "GetMetrics", func(ctx context.Context) (*types.CollectionStats, error) {
+		url := fmt.Sprintf("%s/v1/metrics/%s?refresh=%t", c.BaseURL, source, refresh)
+
+		var stats types.CollectionStats
+		err := c.get(ctx, url, &stats)
+		if err != nil {
+			c.log().ErrorContext(ctx, "Error while getting metrics", "source", source, "refresh", refresh, "error", err.Error())
+			return nil, err
+		}
+		return &stats, nil
+	})
 }
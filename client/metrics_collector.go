@@ -0,0 +1,298 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCollectorInterval  = time.Minute
+	defaultCollectorRetention = 1440 // one day of samples at the default interval
+)
+
+// MetricsSample is a single observation of a collection's statistics
+type MetricsSample struct {
+	Time     time.Time `json:"time"`      // When the sample was taken
+	RowCount uint      `json:"row_count"` // Number of rows in the collection
+	Delta    int64     `json:"delta"`     // Change in row count since the previous sample
+	Rate     float64   `json:"rate"`      // Ingestion rate in rows per second since the previous sample
+}
+
+// MetricsEvent is emitted when a collection is first observed or its row count changes
+type MetricsEvent struct {
+	Collection string         // Collection name
+	Sample     MetricsSample  // The new sample
+	Previous   *MetricsSample // The previous sample, nil when the collection is first observed
+}
+
+// MetricsCollector periodically polls GetAllMetrics and keeps a time series per collection
+type MetricsCollector struct {
+	client    *Client
+	interval  time.Duration
+	retention int
+	refresh   bool
+	path      string
+	now       func() time.Time
+
+	mu          sync.Mutex
+	series      map[string][]MetricsSample
+	subscribers map[chan MetricsEvent]struct{}
+	lastErr     error
+}
+
+// MetricsCollectorOption configures a MetricsCollector
+type MetricsCollectorOption func(*MetricsCollector)
+
+// CollectorInterval sets how often metrics are polled. The default is 1 minute.
+func CollectorInterval(interval time.Duration) MetricsCollectorOption {
+	return func(m *MetricsCollector) {
+		if interval > 0 {
+			m.interval = interval
+		}
+	}
+}
+
+// CollectorRetention sets the maximum number of samples kept per collection. The default is 1440.
+func CollectorRetention(samples int) MetricsCollectorOption {
+	return func(m *MetricsCollector) {
+		if samples > 0 {
+			m.retention = samples
+		}
+	}
+}
+
+// CollectorRefresh sets whether the server is asked to refresh the statistics on every poll. The default is false.
+func CollectorRefresh(refresh bool) MetricsCollectorOption {
+	return func(m *MetricsCollector) {
+		m.refresh = refresh
+	}
+}
+
+// CollectorPersistence persists the time series as JSON to path after every poll and restores it on creation
+func CollectorPersistence(path string) MetricsCollectorOption {
+	return func(m *MetricsCollector) {
+		m.path = path
+	}
+}
+
+// NewMetricsCollector creates a metrics collector, restoring persisted history if configured.
+// Call Run to start polling.
+func (c *Client) NewMetricsCollector(opts ...MetricsCollectorOption) (*MetricsCollector, error) {
+	m := &MetricsCollector{
+		client:      c,
+		interval:    defaultCollectorInterval,
+		retention:   defaultCollectorRetention,
+		now:         time.Now,
+		series:      make(map[string][]MetricsSample),
+		subscribers: make(map[chan MetricsEvent]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.path != "" {
+		data, err := os.ReadFile(m.path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read metrics history %s: %w", m.path, err)
+		}
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &m.series); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal metrics history %s: %w", m.path, err)
+			}
+		}
+	}
+	return m, nil
+}
+
+// Run polls metrics immediately and then on every interval until ctx is done. A poll in flight is canceled with ctx.
+// Poll errors don't stop the collector, the last one is available through Err.
+func (m *MetricsCollector) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		_ = m.collect(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Collect polls the metrics once, appends a sample per collection and notifies subscribers of changes
+func (m *MetricsCollector) Collect() error {
+	return m.collect(context.Background())
+}
+
+// collect polls the metrics once with ctx, see Collect
+func (m *MetricsCollector) collect(ctx context.Context) error {
+	stats, err := m.client.GetAllMetricsContext(ctx, m.refresh)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastErr = err
+	if err != nil {
+		return err
+	}
+
+	now := m.now()
+	for _, stat := range stats {
+		sample := MetricsSample{Time: now, RowCount: stat.RowCount}
+		history := m.series[stat.CollectionName]
+
+		var previous *MetricsSample
+		if len(history) > 0 {
+			last := history[len(history)-1]
+			previous = &last
+			sample.Delta = int64(stat.RowCount) - int64(last.RowCount)
+			if elapsed := now.Sub(last.Time).Seconds(); elapsed > 0 {
+				sample.Rate = float64(sample.Delta) / elapsed
+			}
+		}
+
+		history = append(history, sample)
+		if len(history) > m.retention {
+			history = slices.Clone(history[len(history)-m.retention:])
+		}
+		m.series[stat.CollectionName] = history
+
+		if previous == nil || sample.Delta != 0 {
+			m.publish(MetricsEvent{Collection: stat.CollectionName, Sample: sample, Previous: previous})
+		}
+	}
+
+	if m.path != "" {
+		m.lastErr = m.persist()
+	}
+	return m.lastErr
+}
+
+// publish sends an event to every subscriber without blocking, callers must hold m.mu
+func (m *MetricsCollector) publish(event MetricsEvent) {
+	for subscriber := range m.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			// Slow subscribers miss events rather than stalling the collector
+		}
+	}
+}
+
+// persist atomically writes the time series to m.path, callers must hold m.mu
+func (m *MetricsCollector) persist() error {
+	data, err := json.Marshal(m.series)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), filepath.Base(m.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to persist metrics history: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to persist metrics history: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to persist metrics history: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), m.path); err != nil {
+		return fmt.Errorf("failed to persist metrics history: %w", err)
+	}
+	return nil
+}
+
+// Subscribe returns a channel receiving change events and a function to unsubscribe.
+// Events are dropped when the channel buffer is full.
+func (m *MetricsCollector) Subscribe(buffer int) (<-chan MetricsEvent, func()) {
+	events := make(chan MetricsEvent, buffer)
+
+	m.mu.Lock()
+	m.subscribers[events] = struct{}{}
+	m.mu.Unlock()
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			m.mu.Lock()
+			delete(m.subscribers, events)
+			m.mu.Unlock()
+			close(events)
+		})
+	}
+}
+
+// Collections returns the names of all observed collections in alphabetical order
+func (m *MetricsCollector) Collections() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	collections := make([]string, 0, len(m.series))
+	for collection := range m.series {
+		collections = append(collections, collection)
+	}
+	sort.Strings(collections)
+	return collections
+}
+
+// History returns a copy of the samples of a collection, oldest first
+func (m *MetricsCollector) History(collection string) []MetricsSample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.series[collection])
+}
+
+// Latest returns the most recent sample of a collection
+func (m *MetricsCollector) Latest(collection string) (MetricsSample, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	history := m.series[collection]
+	if len(history) == 0 {
+		return MetricsSample{}, false
+	}
+	return history[len(history)-1], true
+}
+
+// Rate returns the average ingestion rate of a collection in rows per second over the given window
+// ending at the latest sample, or 0 if there are fewer than two samples in the window
+func (m *MetricsCollector) Rate(collection string, window time.Duration) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	history := m.series[collection]
+	if len(history) < 2 {
+		return 0
+	}
+	last := history[len(history)-1]
+	first := last
+	for i := len(history) - 2; i >= 0 && last.Time.Sub(history[i].Time) <= window; i-- {
+		first = history[i]
+	}
+
+	elapsed := last.Time.Sub(first.Time).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return (float64(last.RowCount) - float64(first.RowCount)) / elapsed
+}
+
+// Err returns the error of the latest poll, if any
+func (m *MetricsCollector) Err() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastErr
+}
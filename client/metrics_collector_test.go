@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics Collector", func() {
+	var (
+		server  *httptest.Server
+		client  *Client
+		webRows atomic.Int64
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		webRows.Store(100)
+		now = time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/v1/metrics"))
+			_, _ = fmt.Fprintf(w, `[{"collection_name": "web", "row_count": %d}, {"collection_name": "twitter", "row_count": 7}]`, webRows.Load())
+		}))
+		client = NewClient(server.URL, "test-token")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newCollector := func(opts ...MetricsCollectorOption) *MetricsCollector {
+		collector, err := client.NewMetricsCollector(opts...)
+		Expect(err).NotTo(HaveOccurred())
+		collector.now = func() time.Time { return now }
+		return collector
+	}
+
+	It("should record samples with deltas and rates", func() {
+		collector := newCollector()
+
+		Expect(collector.Collect()).To(Succeed())
+		now = now.Add(10 * time.Second)
+		webRows.Store(150)
+		Expect(collector.Collect()).To(Succeed())
+
+		Expect(collector.Collections()).To(Equal([]string{"twitter", "web"}))
+		history := collector.History("web")
+		Expect(history).To(HaveLen(2))
+		Expect(history[1].RowCount).To(Equal(uint(150)))
+		Expect(history[1].Delta).To(Equal(int64(50)))
+		Expect(history[1].Rate).To(Equal(5.0))
+
+		latest, ok := collector.Latest("twitter")
+		Expect(ok).To(BeTrue())
+		Expect(latest.Delta).To(BeZero())
+	})
+
+	It("should compute rates over a window", func() {
+		collector := newCollector()
+
+		for i := range 4 {
+			webRows.Store(int64(100 + i*60))
+			Expect(collector.Collect()).To(Succeed())
+			now = now.Add(time.Minute)
+		}
+
+		Expect(collector.Rate("web", time.Hour)).To(Equal(1.0))
+		Expect(collector.Rate("web", time.Minute)).To(Equal(1.0))
+		Expect(collector.Rate("unknown", time.Hour)).To(BeZero())
+	})
+
+	It("should enforce the retention", func() {
+		collector := newCollector(CollectorRetention(2))
+
+		for range 3 {
+			Expect(collector.Collect()).To(Succeed())
+		}
+
+		Expect(collector.History("web")).To(HaveLen(2))
+	})
+
+	It("should publish events for new collections and changes only", func() {
+		collector := newCollector()
+		events, unsubscribe := collector.Subscribe(10)
+
+		Expect(collector.Collect()).To(Succeed())
+		Expect(collector.Collect()).To(Succeed())
+		webRows.Store(101)
+		Expect(collector.Collect()).To(Succeed())
+		unsubscribe()
+
+		var received []MetricsEvent
+		for event := range events {
+			received = append(received, event)
+		}
+		Expect(received).To(HaveLen(3))
+		Expect(received[0].Previous).To(BeNil())
+		Expect(received[2].Collection).To(Equal("web"))
+		Expect(received[2].Sample.Delta).To(Equal(int64(1)))
+		Expect(received[2].Previous.RowCount).To(Equal(uint(100)))
+	})
+
+	It("should persist and restore the history", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "metrics.json")
+		collector := newCollector(CollectorPersistence(path))
+		Expect(collector.Collect()).To(Succeed())
+
+		restored := newCollector(CollectorPersistence(path))
+
+		Expect(restored.History("web")).To(Equal(collector.History("web")))
+	})
+
+	It("should keep polling until the context is done and remember errors", func() {
+		server.Close()
+		collector := newCollector(CollectorInterval(10 * time.Millisecond))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		Expect(collector.Run(ctx)).To(MatchError(context.DeadlineExceeded))
+		Expect(collector.Err()).To(HaveOccurred())
+	})
+
+	It("should cancel a poll in flight when the context is done", func() {
+		release := make(chan struct{})
+		defer close(release)
+		hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+		}))
+		defer hanging.Close()
+		client.BaseURL = hanging.URL
+		collector := newCollector()
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		Expect(collector.Run(ctx)).To(MatchError(context.DeadlineExceeded))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		Expect(collector.Err()).To(MatchError(context.DeadlineExceeded))
+	})
+})
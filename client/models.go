@@ -0,0 +1,223 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/types"
+	"golang.org/x/sync/singleflight"
+)
+
+// defaultModelCatalogTTL is how long the model catalog is cached before it is fetched again
+const defaultModelCatalogTTL = time.Hour
+
+// modelCatalogRetryInterval is how long a failed catalog fetch is remembered before fetching again
+const modelCatalogRetryInterval = time.Minute
+
+// modelCatalogFetchTimeout bounds a catalog fetch, which is not canceled with the context of any one caller
+const modelCatalogFetchTimeout = 30 * time.Second
+
+// UnknownModelError is returned when an analysis request names a model that is not in the model catalog
+type UnknownModelError struct {
+	Model      string // The requested model
+	Suggestion string // The closest available model, empty if none is close
+}
+
+func (e *UnknownModelError) Error() string {
+	if e.Suggestion != "" {
+		return fmt.Sprintf("unknown model %q, did you mean %q?", e.Model, e.Suggestion)
+	}
+	return fmt.Sprintf("unknown model %q", e.Model)
+}
+
+// ModelCatalog caches the models available for analysis. The catalog is fetched without holding its lock, so
+// concurrent callers share a single fetch and cached lookups are not blocked by it.
+type ModelCatalog struct {
+	client       *Client
+	ttl          time.Duration
+	fetchTimeout time.Duration
+	fetches      singleflight.Group
+
+	mu        sync.Mutex
+	models    []types.Model
+	fetchedAt time.Time
+	lastErr   error
+	failedAt  time.Time
+}
+
+// GetModels retrieves the available AI models for analysis, including provider, context window,
+// pricing and capabilities when the API reports them
+//
+// Returns:
+//   - A slice of Model, or an error if the operation fails
+func (c *Client) GetModels() ([]types.Model, error) {
+	return c.GetModelsContext(context.Background())
+}
+
+// GetModelsContext is like GetModels but with a context for cancellation and the parent trace span
+func (c *Client) GetModelsContext(ctx context.Context) ([]types.Model, error) {
+	return traced(ctx, c, "GetModels", c.getModels)
+}
+
+func (c *Client) getModels(ctx context.Context) ([]types.Model, error) {
+	var models []types.Model
+	err := c.get(ctx, c.BaseURL+"/v1/analysis", &models)
+	if err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// Models returns the client's cached model catalog
+func (c *Client) Models() *ModelCatalog {
+	c.modelsOnce.Do(func() {
+		c.models = &ModelCatalog{client: c, ttl: defaultModelCatalogTTL, fetchTimeout: modelCatalogFetchTimeout}
+	})
+	return c.models
+}
+
+// List returns the available models, fetching them if the cache is empty or expired.
+// An expired catalog is still returned if fetching it again fails.
+func (m *ModelCatalog) List() ([]types.Model, error) {
+	return m.list(context.Background())
+}
+
+func (m *ModelCatalog) list(ctx context.Context) ([]types.Model, error) {
+	m.mu.Lock()
+	stale := m.models == nil || time.Since(m.fetchedAt) > m.ttl
+	recentlyFailed := m.lastErr != nil && time.Since(m.failedAt) < modelCatalogRetryInterval
+	m.mu.Unlock()
+	var err error
+	if stale && !recentlyFailed {
+		err = m.refresh(ctx)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.models == nil {
+		if m.lastErr != nil {
+			return nil, m.lastErr
+		}
+		return nil, err
+	}
+	// A copy, so that callers can't modify the cached catalog
+	return slices.Clone(m.models), nil
+}
+
+// Refresh fetches the available models, replacing the cached catalog
+func (m *ModelCatalog) Refresh() error {
+	return m.refresh(context.Background())
+}
+
+// refresh fetches the catalog, sharing the fetch with concurrent callers. Fetches that time out are not
+// remembered as failed.
+func (m *ModelCatalog) refresh(ctx context.Context) error {
+	ch := m.fetches.DoChan("models", func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), m.fetchTimeout)
+		defer cancel()
+		models, err := m.client.getModels(ctx)
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if err != nil {
+			err = fmt.Errorf("failed to fetch model catalog: %w", err)
+			if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+				m.lastErr = err
+				m.failedAt = time.Now()
+			}
+			return nil, err
+		}
+		if models == nil {
+			models = []types.Model{}
+		}
+		m.models = models
+		m.fetchedAt = time.Now()
+		m.lastErr = nil
+		return nil, nil
+	})
+	select {
+	case result := <-ch:
+		return result.Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Lookup returns the model with the given ID
+func (m *ModelCatalog) Lookup(id string) (types.Model, bool, error) {
+	return m.lookup(context.Background(), id)
+}
+
+func (m *ModelCatalog) lookup(ctx context.Context, id string) (types.Model, bool, error) {
+	models, err := m.list(ctx)
+	if err != nil {
+		return types.Model{}, false, err
+	}
+	for _, model := range models {
+		if model.ID == id {
+			return model, true, nil
+		}
+	}
+	return types.Model{}, false, nil
+}
+
+// Validate returns an UnknownModelError with the closest available model if id is not in the catalog.
+// An empty catalog accepts every model. If the catalog cannot be fetched, the fetch error is returned.
+func (m *ModelCatalog) Validate(id string) error {
+	return m.validate(context.Background(), id)
+}
+
+func (m *ModelCatalog) validate(ctx context.Context, id string) error {
+	models, err := m.list(ctx)
+	if err != nil {
+		return err
+	}
+	if len(models) == 0 {
+		return nil
+	}
+
+	suggestion, distance := "", -1
+	for _, model := range models {
+		if model.ID == id {
+			return nil
+		}
+		d := levenshtein(strings.ToLower(id), strings.ToLower(model.ID))
+		if !strings.Contains(id, "/") {
+			// Compare names without provider too, so that "gpt-4o" suggests "openai/gpt-4o"
+			d = min(d, levenshtein(strings.ToLower(id), strings.ToLower(model.Name)))
+		}
+		if distance < 0 || d < distance {
+			suggestion, distance = model.ID, d
+		}
+	}
+	if distance > max(len(id)/2, 2) {
+		suggestion = ""
+	}
+	return &UnknownModelError{Model: id, Suggestion: suggestion}
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
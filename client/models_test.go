@@ -0,0 +1,228 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Model Catalog", func() {
+	var (
+		server       *httptest.Server
+		client       *Client
+		catalog      string
+		catalogGets  atomic.Int32
+		analysisPost atomic.Int32
+		release      chan struct{}
+	)
+
+	BeforeEach(func() {
+		catalogGets.Store(0)
+		analysisPost.Store(0)
+		release = nil
+		catalog = `[
+			"openai/gpt-4o-mini",
+			{
+				"id": "openai/gpt-4o",
+				"name": "OpenAI: GPT-4o",
+				"context_length": 128000,
+				"pricing": {"prompt": "0.0000025", "completion": "0.00001"},
+				"capabilities": ["tools", "vision"]
+			}
+		]`
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				catalogGets.Add(1)
+				if release != nil {
+					<-release
+				}
+				_, _ = w.Write([]byte(catalog))
+				return
+			}
+			analysisPost.Add(1)
+			_, _ = w.Write([]byte(`{"analysis": "ok"}`))
+		}))
+		client = NewClient(server.URL, "test-token")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("GetModels", func() {
+		It("should parse bare names and model metadata", func() {
+			models, err := client.GetModels()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(models).To(Equal([]types.Model{
+				{ID: "openai/gpt-4o-mini", Provider: "openai", Name: "gpt-4o-mini"},
+				{
+					ID:            "openai/gpt-4o",
+					Provider:      "openai",
+					Name:          "gpt-4o",
+					ContextWindow: 128000,
+					Pricing:       &types.ModelPricing{Prompt: 0.0000025, Completion: 0.00001},
+					Capabilities:  []string{"tools", "vision"},
+				},
+			}))
+		})
+
+		It("should keep returning names from GetAvailableModels", func() {
+			models, err := client.GetAvailableModels()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(models).To(Equal([]string{"openai/gpt-4o-mini", "openai/gpt-4o"}))
+		})
+	})
+
+	Describe("ModelCatalog", func() {
+		It("should cache the catalog until refreshed", func() {
+			_, err := client.Models().List()
+			Expect(err).NotTo(HaveOccurred())
+			_, _, err = client.Models().Lookup("openai/gpt-4o")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(catalogGets.Load()).To(Equal(int32(1)))
+
+			catalog = `["openai/gpt-4.1"]`
+			Expect(client.Models().Refresh()).To(Succeed())
+
+			model, ok, err := client.Models().Lookup("openai/gpt-4.1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(model.Provider).To(Equal("openai"))
+			Expect(catalogGets.Load()).To(Equal(int32(2)))
+		})
+
+		It("should share a single fetch between concurrent callers", func() {
+			release = make(chan struct{})
+
+			var wg sync.WaitGroup
+			for range 5 {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					models, err := client.Models().List()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(models).To(HaveLen(2))
+				}()
+			}
+			Eventually(catalogGets.Load).Should(Equal(int32(1)))
+			close(release)
+			wg.Wait()
+
+			Expect(catalogGets.Load()).To(Equal(int32(1)))
+		})
+
+		It("should serve the cached catalog while refreshing", func() {
+			_, err := client.Models().List()
+			Expect(err).NotTo(HaveOccurred())
+
+			release = make(chan struct{})
+			refreshed := make(chan error, 1)
+			go func() { refreshed <- client.Models().Refresh() }()
+			Eventually(catalogGets.Load).Should(Equal(int32(2)))
+
+			_, ok, err := client.Models().Lookup("openai/gpt-4o")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			close(release)
+			Eventually(refreshed).Should(Receive(BeNil()))
+		})
+
+		It("should not cancel the shared fetch with the context of a caller", func() {
+			release = make(chan struct{})
+			ctx, cancel := context.WithCancel(context.Background())
+			listed := make(chan error, 1)
+			go func() {
+				_, err := client.Models().list(ctx)
+				listed <- err
+			}()
+			Eventually(catalogGets.Load).Should(Equal(int32(1)))
+			cancel()
+			Eventually(listed).Should(Receive(MatchError(context.Canceled)))
+
+			close(release)
+			Expect(client.Models().Validate("openai/gpt-4o")).To(Succeed())
+			Expect(catalogGets.Load()).To(Equal(int32(1)))
+		})
+
+		It("should not remember fetches that time out", func() {
+			release = make(chan struct{})
+			client.Models().fetchTimeout = 50 * time.Millisecond
+			_, err := client.Models().List()
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+
+			close(release)
+			_, err = client.Models().List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(catalogGets.Load()).To(Equal(int32(2)))
+		})
+
+		It("should return copies of the cached catalog", func() {
+			models, err := client.Models().List()
+			Expect(err).NotTo(HaveOccurred())
+			models[0].ID = "modified"
+
+			_, ok, err := client.Models().Lookup("openai/gpt-4o-mini")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should suggest the closest model", func() {
+			err := client.Models().Validate("openai/gpt4o-mini")
+
+			var unknownModel *UnknownModelError
+			Expect(errors.As(err, &unknownModel)).To(BeTrue())
+			Expect(unknownModel.Suggestion).To(Equal("openai/gpt-4o-mini"))
+			Expect(err.Error()).To(Equal(`unknown model "openai/gpt4o-mini", did you mean "openai/gpt-4o-mini"?`))
+		})
+
+		It("should suggest the full name for models given without provider", func() {
+			err := client.Models().Validate("gpt-4o")
+
+			Expect(err).To(MatchError(ContainSubstring(`did you mean "openai/gpt-4o"?`)))
+		})
+
+		It("should not suggest unrelated models", func() {
+			err := client.Models().Validate("anthropic/claude-3.5-sonnet")
+
+			Expect(err).To(MatchError(`unknown model "anthropic/claude-3.5-sonnet"`))
+		})
+	})
+
+	Describe("AnalyzeDataWithArgs", func() {
+		It("should reject unknown models before making the request", func() {
+			_, err := client.AnalyzeDataWithArgs([]string{"data"}, "prompt", "openai/gpt-4o-mimi", false, nil, "")
+
+			Expect(err).To(BeAssignableToTypeOf(&UnknownModelError{}))
+			Expect(analysisPost.Load()).To(BeZero())
+		})
+
+		It("should analyze with known models", func() {
+			_, err := client.AnalyzeDataWithArgs([]string{"data"}, "prompt", "openai/gpt-4o", false, nil, "")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(analysisPost.Load()).To(Equal(int32(1)))
+		})
+
+		It("should leave the validation to the API when the catalog is unavailable", func() {
+			catalog = `not json`
+
+			_, err := client.AnalyzeDataWithArgs([]string{"data"}, "prompt", "any/model", false, nil, "")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(analysisPost.Load()).To(Equal(int32(1)))
+			Expect(client.Models().Validate("any/model")).To(MatchError(ContainSubstring("failed to fetch model catalog")))
+		})
+	})
+})
@@ -0,0 +1,210 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxTrackedJobs bounds the number of submitted jobs remembered for instrumentation
+const maxTrackedJobs = 10000
+
+// Job outcomes reported to Observer.JobFinished
+const (
+	JobOutcomeDone    = "done"
+	JobOutcomeError   = "error"
+	JobOutcomeTimeout = "timeout"
+)
+
+// Observer receives instrumentation events from the client. Implementations must be safe for concurrent use
+// and should return quickly, as they are called synchronously on the request path.
+type Observer interface {
+	// RequestDone is called after every HTTP request with the endpoint path (without job IDs or query),
+	// the HTTP method, the status code (0 if the request failed before a response) and the request latency
+	RequestDone(endpoint string, method string, status int, duration time.Duration)
+	// JobSubmitted is called after every job submission with the submission error, if any
+	JobSubmitted(jobType string, err error)
+	// JobPolled is called after every status poll of a job with the reported status
+	JobPolled(jobType string, jobID string, status string)
+	// JobFinished is called when waiting for a job ends with one of the JobOutcome values and the time since submission
+	JobFinished(jobType string, jobID string, outcome string, duration time.Duration)
+}
+
+// trackedJob is a submitted job remembered to label its polls and outcome
+type trackedJob struct {
+	jobType     string
+	submittedAt time.Time
+}
+
+// jobTracker remembers the type and submission time of submitted jobs
+type jobTracker struct {
+	mu   sync.Mutex
+	jobs map[string]trackedJob
+}
+
+// do sends the request, tracing it and reporting it to the observer. Compressed responses are decoded.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	negotiate := req.Header.Get("Accept-Encoding") == ""
+	if negotiate {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	if err := c.compressRequest(req); err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(context.WithValue(req.Context(), targetHostKey, req.URL.Hostname()))
+	req, span := c.traceRequest(req)
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(req)
+	endRequestSpan(span, resp, err)
+	c.logRequest(req, resp, err, time.Since(start))
+
+	if c.observer != nil {
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		c.observer.RequestDone(endpointLabel(req.URL.Path), req.Method, status, time.Since(start))
+	}
+	if err == nil && negotiate {
+		if err := decodeResponse(resp); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+	return resp, err
+}
+
+// instrumented reports whether jobs need to be tracked for an observer, tracing or logging
+func (c *Client) instrumented() bool {
+	return c.observer != nil || c.tracer != nil || c.logger != nil
+}
+
+// jobTypeOf returns the job type of a job submission request body
+func jobTypeOf(requestBody []byte) string {
+	var params struct {
+		JobType string `json:"type"`
+	}
+	_ = json.Unmarshal(requestBody, &params)
+	return params.JobType
+}
+
+// jobSubmitted reports a job submission and starts tracking the job
+func (c *Client) jobSubmitted(ctx context.Context, jobType string, jobID string, err error) {
+	if !c.instrumented() {
+		return
+	}
+
+	if c.observer != nil {
+		c.observer.JobSubmitted(jobType, err)
+	}
+	c.annotate(ctx, AttrJobType.String(jobType))
+	if err != nil {
+		c.log().WarnContext(ctx, "Job submission failed", "job_type", jobType, "error", err.Error())
+		return
+	}
+	if jobID == "" {
+		return
+	}
+	c.annotate(ctx, AttrJobUUID.String(jobID))
+	c.log().DebugContext(ctx, "Job submitted", "job_id", jobID, "job_type", jobType)
+	c.trackJob(jobID, jobType, time.Now())
+}
+
+// trackJob starts tracking a job submitted at submittedAt
+func (c *Client) trackJob(jobID string, jobType string, submittedAt time.Time) {
+	c.jobs.mu.Lock()
+	defer c.jobs.mu.Unlock()
+	if c.jobs.jobs == nil {
+		c.jobs.jobs = make(map[string]trackedJob)
+	}
+	if _, ok := c.jobs.jobs[jobID]; !ok && len(c.jobs.jobs) >= maxTrackedJobs {
+		c.jobs.evict(time.Now().Add(-c.Timeout))
+	}
+	c.jobs.jobs[jobID] = trackedJob{jobType: jobType, submittedAt: submittedAt}
+}
+
+// evict forgets the jobs submitted before cutoff, which were never waited for, and then the oldest jobs until
+// a tenth of maxTrackedJobs is free. Callers must hold t.mu.
+func (t *jobTracker) evict(cutoff time.Time) {
+	for id, job := range t.jobs {
+		if job.submittedAt.Before(cutoff) {
+			delete(t.jobs, id)
+		}
+	}
+	excess := len(t.jobs) - (maxTrackedJobs - maxTrackedJobs/10)
+	if excess <= 0 {
+		return
+	}
+	ids := slices.Collect(maps.Keys(t.jobs))
+	slices.SortFunc(ids, func(a, b string) int {
+		return t.jobs[a].submittedAt.Compare(t.jobs[b].submittedAt)
+	})
+	for _, id := range ids[:excess] {
+		delete(t.jobs, id)
+	}
+}
+
+// trackedJob returns the tracked job, jobs submitted by other clients or processes are reported with an empty type
+func (c *Client) trackedJob(jobID string) (trackedJob, bool) {
+	c.jobs.mu.Lock()
+	defer c.jobs.mu.Unlock()
+	job, ok := c.jobs.jobs[jobID]
+	return job, ok
+}
+
+// jobPolled reports a status poll of a job
+func (c *Client) jobPolled(ctx context.Context, jobID string, status string) {
+	if !c.instrumented() {
+		return
+	}
+	job, _ := c.trackedJob(jobID)
+	if c.observer != nil {
+		c.observer.JobPolled(job.jobType, jobID, status)
+	}
+	c.log().DebugContext(ctx, "Job polled", "job_id", jobID, "job_type", job.jobType, "status", status)
+}
+
+// jobFinished reports the outcome of a job and stops tracking it
+func (c *Client) jobFinished(ctx context.Context, jobID string, outcome string, waitStart time.Time) {
+	if !c.instrumented() {
+		return
+	}
+
+	job, ok := c.trackedJob(jobID)
+	if !ok {
+		job.submittedAt = waitStart
+	}
+	c.jobs.mu.Lock()
+	delete(c.jobs.jobs, jobID)
+	c.jobs.mu.Unlock()
+
+	duration := time.Since(job.submittedAt)
+	if c.observer != nil {
+		c.observer.JobFinished(job.jobType, jobID, outcome, duration)
+	}
+	level := slog.LevelDebug
+	if outcome != JobOutcomeDone {
+		level = slog.LevelWarn
+	}
+	c.log().Log(ctx, level, "Job finished", "job_id", jobID, "job_type", job.jobType, "outcome", outcome, "duration", duration)
+}
+
+// endpointLabel returns the API endpoint of a request path without the base path and job IDs, e.g. "/v1/search/live/status"
+func endpointLabel(path string) string {
+	if i := strings.Index(path, "/v1/"); i >= 0 {
+		path = path[i:]
+	}
+	for _, prefix := range []string{jobEndpoint + "/status/", jobEndpoint + "/result/", jobEndpoint + "/attestation/"} {
+		if strings.HasPrefix(path, prefix) {
+			return strings.TrimSuffix(prefix, "/")
+		}
+	}
+	return path
+}
@@ -0,0 +1,25 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Job tracking", func() {
+	It("should evict the oldest jobs once over the bound", func() {
+		client := NewClient("http://localhost", "test-token")
+		start := time.Now()
+		for i := range maxTrackedJobs + 1 {
+			client.trackJob(fmt.Sprintf("job-%d", i), "twitter", start.Add(time.Duration(i)*time.Millisecond))
+		}
+
+		Expect(len(client.jobs.jobs)).To(BeNumerically("<=", maxTrackedJobs))
+		_, ok := client.trackedJob("job-0")
+		Expect(ok).To(BeFalse())
+		_, ok = client.trackedJob(fmt.Sprintf("job-%d", maxTrackedJobs))
+		Expect(ok).To(BeTrue())
+	})
+})
@@ -2,8 +2,20 @@ package client
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"time"
+
+	"github.com/gopher-lab/gopher-client/attest"
+	"github.com/gopher-lab/gopher-client/config"
+	"github.com/gopher-lab/gopher-client/redact"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Options struct {
@@ -14,6 +26,25 @@ type Options struct {
 	MaxIdleConns        int
 	IdleConnTimeout     time.Duration
 	HttpClient          *http.Client
+	Observer            Observer
+	TracerProvider      trace.TracerProvider
+	TracePropagator     propagation.TextMapPropagator
+	Logger              *slog.Logger
+	Redactor            *redact.Redactor
+	TokenSource         TokenSource
+	Verifier            *attest.Verifier
+	JobStore            JobStore
+	JobRetention        time.Duration
+	RootCAs             *x509.CertPool
+	Certificates        []tls.Certificate
+	MinTLSVersion       uint16
+	ProxyURL            *url.URL
+	DialTimeout         time.Duration
+	HostDialTimeouts    map[string]time.Duration
+	MaxResponseSize     int64
+	CompressMinSize     int // Request bodies of at least this size are compressed, disabled if 0
+	tracing             bool
+	dedupeSubmissions   bool
 }
 
 type Option func(*Options) error
@@ -64,7 +95,7 @@ func IdleConnTimeout(timeout time.Duration) Option {
 	}
 }
 
-// HttpClient specifies the http.Client to use. If provided, connection options are ignored.
+// HttpClient specifies the http.Client to use. If provided, connection, TLS and proxy options are ignored.
 func HttpClient(c *http.Client) Option {
 	return func(o *Options) error {
 		o.HttpClient = c
@@ -72,6 +103,109 @@ func HttpClient(c *http.Client) Option {
 	}
 }
 
+// Instrument sets an Observer that receives request, job submission and job polling events, e.g. a Prometheus exporter.
+func Instrument(observer Observer) Option {
+	return func(o *Options) error {
+		o.Observer = observer
+		return nil
+	}
+}
+
+// Tracing enables OpenTelemetry tracing with a span per public method and a child span per HTTP request.
+// The global tracer provider is used if provider is nil. The trace context is propagated in the request headers.
+func Tracing(provider trace.TracerProvider) Option {
+	return func(o *Options) error {
+		o.tracing = true
+		o.TracerProvider = provider
+		return nil
+	}
+}
+
+// TracePropagator sets how the trace context is propagated in request headers when tracing is enabled. The default is W3C Trace Context.
+func TracePropagator(propagator propagation.TextMapPropagator) Option {
+	return func(o *Options) error {
+		o.TracePropagator = propagator
+		return nil
+	}
+}
+
+// Logger sets the structured logger of the client. The client logs nothing by default.
+// Requests are logged at debug level with the job and request IDs, failures at warn level.
+func Logger(logger *slog.Logger) Option {
+	return func(o *Options) error {
+		o.Logger = logger
+		return nil
+	}
+}
+
+// LogHandler sets the slog.Handler the client logs to, see Logger
+func LogHandler(handler slog.Handler) Option {
+	return func(o *Options) error {
+		if handler == nil {
+			return errors.New("log handler must not be nil")
+		}
+		o.Logger = slog.New(handler)
+		return nil
+	}
+}
+
+// Redaction sets how request and response bodies in error messages and log attributes are redacted.
+// The default is redact.Default(), use redact.None() to disable redaction.
+func Redaction(redactor *redact.Redactor) Option {
+	return func(o *Options) error {
+		if redactor == nil {
+			return errors.New("redactor must not be nil, use redact.None() to disable redaction")
+		}
+		o.Redactor = redactor
+		return nil
+	}
+}
+
+// Credentials sets the source of the API token, which then takes precedence over the token passed to the constructor.
+// Tokens that expire are cached and refreshed before they expire, see CachedToken.
+func Credentials(src TokenSource) Option {
+	return func(o *Options) error {
+		if src == nil {
+			return errors.New("token source must not be nil")
+		}
+		o.TokenSource = CachedToken(src, DefaultTokenRefreshWindow)
+		return nil
+	}
+}
+
+// Attestation verifies the attestation evidence of every job result with verifier, failing results that do
+// not verify with an error wrapping attest.ErrVerification. See GetVerifiedResult to inspect results instead.
+func Attestation(verifier *attest.Verifier) Option {
+	return func(o *Options) error {
+		if verifier == nil {
+			return errors.New("attestation verifier must not be nil")
+		}
+		o.Verifier = verifier
+		return nil
+	}
+}
+
+// PersistJobs records every submitted job and its status in the job store, so that ResumePending can reattach
+// to unfinished jobs after a restart
+func PersistJobs(store JobStore) Option {
+	return func(o *Options) error {
+		if store == nil {
+			return errors.New("job store must not be nil")
+		}
+		o.JobStore = store
+		return nil
+	}
+}
+
+// JobRetention sets how long finished jobs are kept in the job store of PersistJobs before they are removed.
+// The default is DefaultJobRetention, a negative retention keeps finished jobs.
+func JobRetention(retention time.Duration) Option {
+	return func(o *Options) error {
+		o.JobRetention = retention
+		return nil
+	}
+}
+
 func NewOptions(opts ...Option) (*Options, error) {
 	o := &Options{
 		Timeout:             1 * time.Minute,
@@ -79,6 +213,9 @@ func NewOptions(opts ...Option) (*Options, error) {
 		MaxIdleConns:        100,
 		MaxIdleConnsPerHost: 10,
 		IdleConnTimeout:     2 * time.Minute,
+		DialTimeout:         DefaultDialTimeout,
+		MaxResponseSize:     DefaultMaxResponseSize,
+		JobRetention:        DefaultJobRetention,
 	}
 	for _, opt := range opts {
 		if err := opt(o); err != nil {
@@ -86,6 +223,37 @@ func NewOptions(opts ...Option) (*Options, error) {
 		}
 	}
 
+	if o.Timeout < 0 {
+		return nil, fmt.Errorf("timeout must not be negative, got %v", o.Timeout)
+	}
+	if o.IdleConnTimeout < 0 {
+		return nil, fmt.Errorf("idle connection timeout must not be negative, got %v", o.IdleConnTimeout)
+	}
+	if o.DialTimeout < 0 {
+		return nil, fmt.Errorf("dial timeout must not be negative, got %v", o.DialTimeout)
+	}
+	for host, timeout := range o.HostDialTimeouts {
+		if timeout <= 0 {
+			return nil, fmt.Errorf("dial timeout of %s must be positive, got %v", host, timeout)
+		}
+	}
+
+	if o.Redactor == nil {
+		o.Redactor = redact.Default()
+	}
+	if o.Logger != nil {
+		o.Logger = slog.New(o.Redactor.Handler(o.Logger.Handler()))
+	}
+
+	if o.tracing {
+		if o.TracerProvider == nil {
+			o.TracerProvider = otel.GetTracerProvider()
+		}
+		if o.TracePropagator == nil {
+			o.TracePropagator = propagation.TraceContext{}
+		}
+	}
+
 	if o.HttpClient == nil {
 		c := &http.Client{
 			Timeout: o.Timeout,
@@ -96,16 +264,102 @@ func NewOptions(opts ...Option) (*Options, error) {
 		t.MaxIdleConns = o.MaxIdleConns
 		t.MaxIdleConnsPerHost = o.MaxIdleConnsPerHost
 		t.MaxConnsPerHost = o.MaxConnsPerHost
-		if o.ignoreTLSCert {
-			if t.TLSClientConfig == nil {
-				t.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			} else {
-				t.TLSClientConfig.InsecureSkipVerify = true
-			}
-		}
+		o.configureTransport(t)
 		c.Transport = t
 
 		o.HttpClient = c
 	}
 	return o, nil
 }
+
+// ConfigOptions validates cfg and returns the options it configures. This is the single mapping from
+// config.Config onto Options, used by NewClientFromConfig, NewClientFromProfile and NewClientWithConfig.
+// Options that take Go values (HttpClient, Instrument, Logger, a TracerProvider) can only be set in code.
+func ConfigOptions(cfg *config.Config) ([]Option, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	opts := []Option{
+		Timeout(cfg.Timeout),
+		MaxConnsPerHost(uint(cfg.MaxConnsPerHost)),
+		MaxIdleConnsPerHost(uint(cfg.MaxIdleConnsPerHost)),
+		MaxIdleConns(uint(cfg.MaxIdleConns)),
+		IdleConnTimeout(cfg.IdleConnTimeout),
+	}
+	if cfg.IgnoreTLSCert {
+		opts = append(opts, IgnoreTLSCert())
+	}
+	if cfg.CACertFile != "" {
+		opts = append(opts, CACertFile(cfg.CACertFile))
+	}
+	if cfg.ClientCertFile != "" {
+		opts = append(opts, ClientCertificate(cfg.ClientCertFile, cfg.ClientKeyFile))
+	}
+	if version, _ := config.TLSVersion(cfg.MinTLSVersion); version != 0 {
+		opts = append(opts, MinTLSVersion(version))
+	}
+	if cfg.Proxy != "" {
+		opts = append(opts, Proxy(cfg.Proxy))
+	}
+	if cfg.DialTimeout > 0 {
+		opts = append(opts, DialTimeout(cfg.DialTimeout))
+	}
+	for host, timeout := range cfg.HostDialTimeouts {
+		opts = append(opts, HostDialTimeout(host, timeout))
+	}
+	if cfg.TokenFile != "" {
+		opts = append(opts, Credentials(FileToken(cfg.TokenFile)))
+	}
+	command, err := config.SplitCommand(cfg.TokenCommand)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token command: %w", err)
+	}
+	if len(command) > 0 {
+		opts = append(opts, Credentials(CommandToken(DefaultCommandTokenTTL, command[0], command[1:]...)))
+	}
+	opts = append(opts, MaxResponseSize(cfg.MaxResponseSize))
+	if cfg.CompressRequests {
+		opts = append(opts, CompressRequests(0))
+	}
+	if cfg.JobStoreFile != "" {
+		store, err := NewFileJobStore(cfg.JobStoreFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, PersistJobs(store))
+	}
+	if cfg.Tracing {
+		opts = append(opts, Tracing(nil))
+	}
+
+	var redactOpts []redact.Option
+	if cfg.RedactKeys != nil {
+		redactOpts = append(redactOpts, redact.RedactKeys(cfg.RedactKeys...))
+	}
+	if cfg.HashKeys != nil {
+		redactOpts = append(redactOpts, redact.HashKeys(cfg.HashKeys...))
+	}
+	if cfg.MaxBodyLength > 0 {
+		redactOpts = append(redactOpts, redact.MaxBodyLength(cfg.MaxBodyLength))
+	}
+	switch {
+	case cfg.DisableRedaction:
+		opts = append(opts, Redaction(redact.None()))
+	case len(redactOpts) > 0:
+		redactor, err := redact.New(redactOpts...)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, Redaction(redactor))
+	}
+	return opts, nil
+}
+
+// tracer returns the tracer of the client, nil if tracing is disabled
+func (o *Options) tracer() trace.Tracer {
+	if !o.tracing {
+		return nil
+	}
+	return o.TracerProvider.Tracer(tracerName)
+}
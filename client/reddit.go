@@ -1,127 +1,144 @@
 package client
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 
     "github.com/masa-finance/tee-worker/v2/api/args/reddit"
-    "github.com/masa-finance/tee-worker/v2/api/params"
     "github.com/masa-finance/tee-worker/v2/api/types"
 )
 
 // ScrapeRedditURLAsync performs a Reddit URL scraping job and returns a job ID
 func (c *Client) ScrapeRedditURLAsync(url string) (*types.ResultResponse, error) {
+	return c.ScrapeRedditURLAsyncContext(context.Background(), url)
+}
+
+// ScrapeRedditURLAsyncContext is like ScrapeRedditURLAsync but with a context for cancellation and the parent trace span
+func (c *Client) ScrapeRedditURLAsyncContext(ctx context.Context, url string) (*types.ResultResponse, error) {
 	args := reddit.NewScrapeUrlsArguments()
 	args.URLs = []string{url}
-	res, err := c.SearchRedditWithArgsAsync(args)
-	if err != nil {
-		return nil, err
-	}
-	return res, nil
+	return traced(ctx, c, "ScrapeRedditURLAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.RedditJob, args))
+	})
 }
 
 // SearchRedditPostsAsync performs a Reddit posts search job and returns a job ID
 func (c *Client) SearchRedditPostsAsync(query string) (*types.ResultResponse, error) {
+	return c.SearchRedditPostsAsyncContext(context.Background(), query)
+}
+
+// SearchRedditPostsAsyncContext is like SearchRedditPostsAsync but with a context for cancellation and the parent trace span
+func (c *Client) SearchRedditPostsAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error) {
 	args := reddit.NewSearchPostsArguments()
 	args.Queries = []string{query}
-	res, err := c.SearchRedditWithArgsAsync(args)
-	if err != nil {
-		return nil, err
-	}
-	return res, nil
+	return traced(ctx, c, "SearchRedditPostsAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.RedditJob, args))
+	})
 }
 
 // SearchRedditUsersAsync performs a Reddit users search job and returns a job ID
 func (c *Client) SearchRedditUsersAsync(query string) (*types.ResultResponse, error) {
+	return c.SearchRedditUsersAsyncContext(context.Background(), query)
+}
+
+// SearchRedditUsersAsyncContext is like SearchRedditUsersAsync but with a context for cancellation and the parent trace span
+func (c *Client) SearchRedditUsersAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error) {
 	args := reddit.NewSearchUsersArguments()
 	args.Queries = []string{query}
-	res, err := c.SearchRedditWithArgsAsync(args)
-	if err != nil {
-		return nil, err
-	}
-	return res, nil
+	return traced(ctx, c, "SearchRedditUsersAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.RedditJob, args))
+	})
 }
 
 // SearchRedditCommunitiesAsync performs a Reddit communities search job and returns a job ID
 func (c *Client) SearchRedditCommunitiesAsync(query string) (*types.ResultResponse, error) {
+	return c.SearchRedditCommunitiesAsyncContext(context.Background(), query)
+}
+
+// SearchRedditCommunitiesAsyncContext is like SearchRedditCommunitiesAsync but with a context for cancellation and the parent trace span
+func (c *Client) SearchRedditCommunitiesAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error) {
 	args := reddit.NewSearchCommunitiesArguments()
 	args.Queries = []string{query}
-	res, err := c.SearchRedditWithArgsAsync(args)
-	if err != nil {
-		return nil, err
-	}
-	return res, nil
+	return traced(ctx, c, "SearchRedditCommunitiesAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.RedditJob, args))
+	})
 }
 
 // ScrapeRedditURL performs a Reddit URL scraping and waits for completion, returning results directly
 func (c *Client) ScrapeRedditURL(url string) ([]types.Document, error) {
-	resp, err := c.ScrapeRedditURLAsync(url)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
+	return c.ScrapeRedditURLContext(context.Background(), url)
+}
+
+// ScrapeRedditURLContext is like ScrapeRedditURL but with a context for cancellation and the parent trace span
+func (c *Client) ScrapeRedditURLContext(ctx context.Context, url string) ([]types.Document, error) {
+	args := reddit.NewScrapeUrlsArguments()
+	args.URLs = []string{url}
+	return traced(ctx, c, "ScrapeRedditURL", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.RedditJob, args))
+	})
 }
 
 // SearchRedditPosts performs a Reddit posts search and waits for completion, returning results directly
 func (c *Client) SearchRedditPosts(query string) ([]types.Document, error) {
-	resp, err := c.SearchRedditPostsAsync(query)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
+	return c.SearchRedditPostsContext(context.Background(), query)
+}
+
+// SearchRedditPostsContext is like SearchRedditPosts but with a context for cancellation and the parent trace span
+func (c *Client) SearchRedditPostsContext(ctx context.Context, query string) ([]types.Document, error) {
+	args := reddit.NewSearchPostsArguments()
+	args.Queries = []string{query}
+	return traced(ctx, c, "SearchRedditPosts", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.RedditJob, args))
+	})
 }
 
 // SearchRedditUsers performs a Reddit users search and waits for completion, returning results directly
 func (c *Client) SearchRedditUsers(query string) ([]types.Document, error) {
-	resp, err := c.SearchRedditUsersAsync(query)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
+	return c.SearchRedditUsersContext(context.Background(), query)
+}
+
+// SearchRedditUsersContext is like SearchRedditUsers but with a context for cancellation and the parent trace span
+func (c *Client) SearchRedditUsersContext(ctx context.Context, query string) ([]types.Document, error) {
+	args := reddit.NewSearchUsersArguments()
+	args.Queries = []string{query}
+	return traced(ctx, c, "SearchRedditUsers", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.RedditJob, args))
+	})
 }
 
 // SearchRedditCommunities performs a Reddit communities search and waits for completion, returning results directly
 func (c *Client) SearchRedditCommunities(query string) ([]types.Document, error) {
-	resp, err := c.SearchRedditCommunitiesAsync(query)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
+	return c.SearchRedditCommunitiesContext(context.Background(), query)
+}
+
+// SearchRedditCommunitiesContext is like SearchRedditCommunities but with a context for cancellation and the parent trace span
+func (c *Client) SearchRedditCommunitiesContext(ctx context.Context, query string) ([]types.Document, error) {
+	args := reddit.NewSearchCommunitiesArguments()
+	args.Queries = []string{query}
+	return traced(ctx, c, "SearchRedditCommunities", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.RedditJob, args))
+	})
 }
 
 // SearchRedditWithArgs searches Reddit with custom arguments and waits for completion, returning results directly
 func (c *Client) SearchRedditWithArgs(args reddit.SearchArguments) ([]types.Document, error) {
-	resp, err := c.SearchRedditWithArgsAsync(args)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
+	return c.SearchRedditWithArgsContext(context.Background(), args)
+}
+
+// SearchRedditWithArgsContext is like SearchRedditWithArgs but with a context for cancellation and the parent trace span
+func (c *Client) SearchRedditWithArgsContext(ctx context.Context, args reddit.SearchArguments) ([]types.Document, error) {
+	return traced(ctx, c, "SearchRedditWithArgs", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.RedditJob, args))
+	})
 }
 
 // SearchRedditWithArgsAsync searches Reddit with custom arguments and returns a job ID
 func (c *Client) SearchRedditWithArgsAsync(args reddit.SearchArguments) (*types.ResultResponse, error) {
-	params := params.Params[*reddit.SearchArguments]{}
-	params.JobType = types.RedditJob
-	params.Args = &args
-
-	body, err := json.Marshal(params)
-	if err != nil {
-		return nil, err
-	}
-	return c.doRequest(c.BaseURL+jobEndpoint, body)
+	return c.SearchRedditWithArgsAsyncContext(context.Background(), args)
+}
+
+// SearchRedditWithArgsAsyncContext is like SearchRedditWithArgsAsync but with a context for cancellation and the parent trace span
+func (c *Client) SearchRedditWithArgsAsyncContext(ctx context.Context, args reddit.SearchArguments) (*types.ResultResponse, error) {
+	return traced(ctx, c, "SearchRedditWithArgsAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.RedditJob, args))
+	})
 }
@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/masa-finance/tee-worker/v2/api/args/base"
+	"github.com/masa-finance/tee-worker/v2/api/args/linkedin"
+	"github.com/masa-finance/tee-worker/v2/api/args/reddit"
+	"github.com/masa-finance/tee-worker/v2/api/args/tiktok"
+	"github.com/masa-finance/tee-worker/v2/api/args/twitter"
+	"github.com/masa-finance/tee-worker/v2/api/args/web"
+	"github.com/masa-finance/tee-worker/v2/api/params"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+)
+
+// jobRequest is the body of a job submission, as params.Params of tee-worker
+type jobRequest[A any] struct {
+	JobType   types.JobType `json:"type"`
+	Arguments *A            `json:"arguments"`
+}
+
+// newJobRequest returns the body submitting a job of jobType with args
+func newJobRequest[A any](jobType types.JobType, args A) jobRequest[A] {
+	return jobRequest[A]{JobType: jobType, Arguments: &args}
+}
+
+// Source adapts a job type and its arguments type A to Submit and Run, allowing job types to be added
+// without a client release. A job type can have several sources with different arguments types.
+type Source[A any] struct {
+	JobType  types.JobType
+	Defaults func() A           // Default arguments returned by NewArgs, optional
+	Validate func(args A) error // Validates the arguments before submission, optional
+	Decode   resultDecoder      // Decodes the job result, optional, the default decodes documents
+}
+
+// resultDecoder decodes the JSON result of a job into documents
+type resultDecoder = func(result json.RawMessage) ([]types.Document, error)
+
+type sourceKey struct {
+	jobType types.JobType
+	args    reflect.Type
+}
+
+var registry = struct {
+	sync.RWMutex
+	sources map[sourceKey]any
+}{sources: make(map[sourceKey]any)}
+
+// Register registers the source of a job type and arguments type, returning an error if one is already registered
+func Register[A any](src Source[A]) error {
+	if src.JobType == types.UnknownJob {
+		return fmt.Errorf("source of %s arguments has no job type", reflect.TypeFor[A]())
+	}
+	key := sourceKey{jobType: src.JobType, args: reflect.TypeFor[A]()}
+
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.sources[key]; ok {
+		return fmt.Errorf("a source of job type %s with %s arguments is already registered", key.jobType, key.args)
+	}
+	registry.sources[key] = src
+	return nil
+}
+
+// MustRegister registers a source and panics on error, for use in init functions
+func MustRegister[A any](src Source[A]) {
+	if err := Register(src); err != nil {
+		panic(err)
+	}
+}
+
+// RegisteredJobTypes returns the job types with a registered source, in alphabetical order
+func RegisteredJobTypes() []types.JobType {
+	registry.RLock()
+	defer registry.RUnlock()
+	var jobTypes []types.JobType
+	for key := range registry.sources {
+		if !slices.Contains(jobTypes, key.jobType) {
+			jobTypes = append(jobTypes, key.jobType)
+		}
+	}
+	slices.Sort(jobTypes)
+	return jobTypes
+}
+
+// lookupSource returns the registered source of the job type and arguments type, or a source without adapters
+func lookupSource[A any](jobType types.JobType) Source[A] {
+	registry.RLock()
+	defer registry.RUnlock()
+	if src, ok := registry.sources[sourceKey{jobType: jobType, args: reflect.TypeFor[A]()}]; ok {
+		return src.(Source[A])
+	}
+	return Source[A]{JobType: jobType}
+}
+
+// NewArgs returns the default arguments of the registered source, or the zero value without defaults
+func NewArgs[A any](jobType types.JobType) A {
+	if src := lookupSource[A](jobType); src.Defaults != nil {
+		return src.Defaults()
+	}
+	var args A
+	return args
+}
+
+// Submit validates args with the registered source, if any, and submits a job of jobType, returning its job ID.
+// Job types without a registered source are submitted without validation.
+func Submit[A any](ctx context.Context, c *Client, jobType types.JobType, args A) (*types.ResultResponse, error) {
+	return traced(ctx, c, "Submit", func(ctx context.Context) (*types.ResultResponse, error) {
+		c.annotate(ctx, AttrJobType.String(string(jobType)))
+		if err := validateArgs(lookupSource[A](jobType), args); err != nil {
+			return nil, err
+		}
+		return c.submitJob(ctx, newJobRequest(jobType, args))
+	})
+}
+
+// Run submits a job like Submit and waits for its result, decoded by the registered source
+func Run[A any](ctx context.Context, c *Client, jobType types.JobType, args A) ([]types.Document, error) {
+	return traced(ctx, c, "Run", func(ctx context.Context) ([]types.Document, error) {
+		c.annotate(ctx, AttrJobType.String(string(jobType)))
+		src := lookupSource[A](jobType)
+		if err := validateArgs(src, args); err != nil {
+			return nil, err
+		}
+		return c.runDecodedJob(ctx, newJobRequest(jobType, args), src.Decode)
+	})
+}
+
+func validateArgs[A any](src Source[A], args A) error {
+	if src.Validate == nil {
+		return nil
+	}
+	if err := src.Validate(args); err != nil {
+		return fmt.Errorf("invalid %s job arguments: %w", src.JobType, err)
+	}
+	return nil
+}
+
+// decodeDocuments is the default result decoder
+func decodeDocuments(result json.RawMessage) ([]types.Document, error) {
+	var docs []types.Document
+	if err := json.Unmarshal(result, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// teeWorkerSource returns the source of a job type of tee-worker, validated like tee-worker does
+func teeWorkerSource[A any, P interface {
+	*A
+	base.JobArgument
+}](jobType types.JobType, defaults func() A) Source[A] {
+	return Source[A]{
+		JobType:  jobType,
+		Defaults: defaults,
+		Validate: func(args A) error {
+			return params.Params[P]{JobType: jobType, Args: &args}.Validate(nil)
+		},
+	}
+}
+
+func init() {
+	MustRegister(teeWorkerSource(types.WebJob, web.NewScraperArguments))
+	MustRegister(teeWorkerSource(types.TwitterJob, twitter.NewSearchArguments))
+	MustRegister(teeWorkerSource(types.RedditJob, reddit.NewSearchPostsArguments))
+	MustRegister(teeWorkerSource(types.LinkedInJob, linkedin.NewProfileArguments))
+	MustRegister(teeWorkerSource(types.TiktokJob, tiktok.NewTranscriptionArguments))
+	MustRegister(teeWorkerSource(types.TiktokJob, tiktok.NewQueryArguments))
+	MustRegister(teeWorkerSource(types.TiktokJob, tiktok.NewTrendingArguments))
+}
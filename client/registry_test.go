@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/masa-finance/tee-worker/v2/api/args/twitter"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const weatherJob = types.JobType("weather")
+
+type weatherArgs struct {
+	City  string `json:"city"`
+	Units string `json:"units"`
+}
+
+func init() {
+	MustRegister(Source[weatherArgs]{
+		JobType:  weatherJob,
+		Defaults: func() weatherArgs { return weatherArgs{Units: "metric"} },
+		Validate: func(args weatherArgs) error {
+			if args.City == "" {
+				return errors.New("city is required")
+			}
+			return nil
+		},
+		Decode: func(result json.RawMessage) ([]types.Document, error) {
+			var forecast struct {
+				Summary string `json:"summary"`
+			}
+			if err := json.Unmarshal(result, &forecast); err != nil {
+				return nil, err
+			}
+			return []types.Document{{Content: forecast.Summary}}, nil
+		},
+	})
+}
+
+var _ = Describe("Source registry", func() {
+	var (
+		server    *httptest.Server
+		submitted map[string]any
+	)
+
+	BeforeEach(func() {
+		submitted = nil
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /v1/search/live", func(w http.ResponseWriter, r *http.Request) {
+			Expect(json.NewDecoder(r.Body).Decode(&submitted)).To(Succeed())
+			_, _ = w.Write([]byte(`{"uuid": "job-1"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/status/job-1", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"status": "done"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/result/job-1", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"summary": "sunny"}`))
+		})
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should list the registered job types", func() {
+		Expect(RegisteredJobTypes()).To(ContainElements(weatherJob, types.TwitterJob, types.TiktokJob))
+	})
+
+	It("should return the default arguments of a source", func() {
+		Expect(NewArgs[weatherArgs](weatherJob)).To(Equal(weatherArgs{Units: "metric"}))
+		Expect(NewArgs[twitter.SearchArguments](types.TwitterJob)).To(Equal(twitter.NewSearchArguments()))
+		Expect(NewArgs[weatherArgs](types.TwitterJob)).To(BeZero())
+	})
+
+	It("should submit jobs of a registered job type", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		args := NewArgs[weatherArgs](weatherJob)
+		args.City = "Lisbon"
+		resp, err := Submit(context.Background(), client, weatherJob, args)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.UUID).To(Equal("job-1"))
+		Expect(submitted).To(Equal(map[string]any{
+			"type":      "weather",
+			"arguments": map[string]any{"city": "Lisbon", "units": "metric"},
+		}))
+	})
+
+	It("should decode results with the decoder of the source", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		docs, err := Run(context.Background(), client, weatherJob, weatherArgs{City: "Lisbon"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(docs).To(HaveLen(1))
+		Expect(docs[0].Content).To(Equal("sunny"))
+	})
+
+	It("should reject invalid arguments before submission", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = Submit(context.Background(), client, weatherJob, weatherArgs{})
+		Expect(err).To(MatchError(ContainSubstring("invalid weather job arguments: city is required")))
+		Expect(submitted).To(BeNil())
+
+		args := twitter.NewSearchArguments()
+		args.Count = -1
+		_, err = Submit(context.Background(), client, types.TwitterJob, args)
+		Expect(err).To(MatchError(ContainSubstring("invalid twitter job arguments")))
+		Expect(submitted).To(BeNil())
+	})
+
+	It("should submit job types without a source as they are", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = Submit(context.Background(), client, types.JobType("unregistered"), map[string]any{"key": "value"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(submitted).To(HaveKeyWithValue("type", "unregistered"))
+	})
+
+	It("should reject duplicate registrations", func() {
+		Expect(Register(Source[weatherArgs]{JobType: weatherJob})).To(MatchError(ContainSubstring("already registered")))
+		Expect(Register(Source[weatherArgs]{})).To(MatchError(ContainSubstring("no job type")))
+	})
+})
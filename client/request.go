@@ -0,0 +1,139 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxErrorBody bounds the response body of error statuses read into an APIError
+const maxErrorBody = 64 << 10
+
+// apiRequest is a request to the API sent by send or open
+type apiRequest struct {
+	method string
+	url    string
+	body   []byte      // JSON request body, none if nil
+	header http.Header // Additional request headers
+}
+
+// APIError is returned when the API responds with an error status, or with an "error" field in the response body
+type APIError struct {
+	StatusCode int    // HTTP status code of the response
+	URL        string // URL of the request
+	Message    string // The "error" field of the response, if any
+	RequestID  string // Request ID of the server, or the one sent by the client
+
+	requestBody  string // Redacted request body
+	responseBody string // Redacted response body
+}
+
+func (e *APIError) Error() string {
+	var msg string
+	switch {
+	case e.StatusCode >= 200 && e.StatusCode < 300:
+		msg = fmt.Sprintf("job errored: %s", e.Message)
+	case e.requestBody != "":
+		msg = fmt.Sprintf("job errored: Status code %d during call to %s with request body %s. Response body: %s",
+			e.StatusCode, e.URL, e.requestBody, e.responseBody)
+	default:
+		msg = fmt.Sprintf("job errored: Status code %d during call to %s. Response body: %s", e.StatusCode, e.URL, e.responseBody)
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request ID %s)", e.RequestID)
+	}
+	return msg
+}
+
+// get sends a GET request and decodes the JSON response into receiver, see send
+func (c *Client) get(ctx context.Context, url string, receiver any) error {
+	return c.send(ctx, apiRequest{method: http.MethodGet, url: url}, receiver)
+}
+
+// post sends a POST request with a JSON body and decodes the JSON response into receiver, see send
+func (c *Client) post(ctx context.Context, url string, body []byte, receiver any) error {
+	return c.send(ctx, apiRequest{method: http.MethodPost, url: url, body: body}, receiver)
+}
+
+// send sends a request and decodes the JSON response body into receiver, if not nil. Error statuses fail with an
+// *APIError. A response with an "error" field is decoded and then also fails with an *APIError.
+func (c *Client) send(ctx context.Context, r apiRequest, receiver any) error {
+	resp, err := c.open(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	requestID := responseRequestID(resp)
+	body, err := c.readBody(resp)
+	if err != nil {
+		return fmt.Errorf("failed to read body from %s request to %s (request ID %s): %w", r.method, r.url, requestID, err)
+	}
+	if receiver != nil {
+		if err := json.Unmarshal(body, receiver); err != nil {
+			return fmt.Errorf("failed to unmarshal %s %s response %s (request ID %s): %w", r.method, r.url, c.redaction().Body(body), requestID, err)
+		}
+	}
+	if message := errorMessage(body); message != "" {
+		return &APIError{StatusCode: resp.StatusCode, URL: r.url, Message: message, RequestID: requestID}
+	}
+	return nil
+}
+
+// open sends a request and returns the response of a successful status with its body to be read and closed
+func (c *Client) open(ctx context.Context, r apiRequest) (*http.Response, error) {
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, r.url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request to %s: %w", r.method, r.url, err)
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	if r.body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	requestID := requestIDOf(ctx)
+	req.Header.Set(requestIDHeader, requestID)
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to do %s request to %s (request ID %s): %w", r.method, r.url, requestID, err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body from %s request to %s (request ID %s): %w", r.method, r.url, requestID, err)
+	}
+	apiErr := &APIError{
+		StatusCode:   resp.StatusCode,
+		URL:          r.url,
+		RequestID:    responseRequestID(resp),
+		responseBody: c.redaction().Body(respBody),
+	}
+	if r.body != nil {
+		apiErr.requestBody = c.redaction().Body(r.body)
+	}
+	apiErr.Message = errorMessage(respBody)
+	return nil, apiErr
+}
+
+// isResponseError reports whether err is the "error" field of a successful response
+func isResponseError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= 200 && apiErr.StatusCode < 300
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/masa-finance/tee-worker/v2/api/args/reddit"
+	"github.com/masa-finance/tee-worker/v2/api/args/twitter"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+)
+
+// ResearchSource selects where the research pipeline looks for documents
+type ResearchSource string
+
+const (
+	ResearchIndex   ResearchSource = "index"   // Hybrid search over the indexed collections
+	ResearchTwitter ResearchSource = "twitter" // Live Twitter search job
+	ResearchReddit  ResearchSource = "reddit"  // Live Reddit posts search job
+)
+
+const (
+	defaultResearchMaxResults   = 20
+	defaultResearchMaxDocuments = 50
+)
+
+// citationPattern matches document references such as [3] in research answers
+var citationPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// ResearchOptions configures the Research pipeline
+type ResearchOptions struct {
+	Sources      []ResearchSource // Where to look for documents (optional, defaults to the index)
+	IndexSources []types.Source   // Collections searched by the index source (optional, defaults to all)
+	MaxResults   int              // Maximum number of documents requested per source (optional, defaults to 20)
+	MaxDocuments int              // Maximum number of documents passed to the analysis (optional, defaults to 50)
+	MaxTerms     int              // Maximum number of search terms to extract (optional, 1-6, defaults to 4)
+	Model        string           // AI model to use for the analysis (optional, defaults to "openai/gpt-4o-mini")
+}
+
+// ResearchStep describes one step of the research pipeline
+type ResearchStep struct {
+	Name      string // Step name, e.g. "extraction", "search:twitter" or "analysis"
+	Reasoning string // Reasoning reported by the step
+	Documents int    // Number of documents produced by the step
+	Error     string // Error of a failed search step, the pipeline continues with the remaining sources
+}
+
+// ResearchResult is the outcome of the Research pipeline
+type ResearchResult struct {
+	Question   string           // Original question
+	SearchTerm string           // Search term extracted from the question
+	Answer     string           // Answer produced by the analysis
+	Documents  []types.Document // Documents passed to the analysis, referenced as [1], [2], ... in the answer
+	Citations  []types.Document // Documents cited in the answer
+	Steps      []ResearchStep   // Steps in execution order
+	ModelUsed  string           // AI model used for the analysis
+	TokensUsed int              // Tokens consumed by the analysis
+}
+
+// researchSearchResult is the result of a single search step
+type researchSearchResult struct {
+	source    ResearchSource
+	documents []types.Document
+	err       error
+}
+
+// Research answers a question by extracting a search term, searching the configured sources and analyzing the found documents
+//
+// Args:
+//   - ctx: Context to cancel the pipeline between steps
+//   - question: The question to research
+//   - opts: Sources, result caps and model
+//
+// Returns:
+//   - A pointer to ResearchResult containing the answer, the search term, the documents and the reasoning of each step,
+//     or an error if the extraction or the analysis fails, or no source returned any documents
+func (c *Client) Research(ctx context.Context, question string, opts ResearchOptions) (*ResearchResult, error) {
+	return traced(ctx, c, "Research", func(ctx context.Context) (*ResearchResult, error) {
+		return c.research(ctx, question, opts)
+	})
+}
+
+func (c *Client) research(ctx context.Context, question string, opts ResearchOptions) (*ResearchResult, error) {
+	if len(opts.Sources) == 0 {
+		opts.Sources = []ResearchSource{ResearchIndex}
+	}
+	if opts.MaxResults <= 0 {
+		opts.MaxResults = defaultResearchMaxResults
+	}
+	if opts.MaxDocuments <= 0 {
+		opts.MaxDocuments = defaultResearchMaxDocuments
+	}
+	result := &ResearchResult{Question: question}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	extraction, err := c.extractSearchTerms(ctx, question, opts.MaxTerms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract search terms: %w", err)
+	}
+	result.SearchTerm = extraction.SearchTerm
+	if result.SearchTerm == "" {
+		result.SearchTerm = question
+	}
+	result.Steps = append(result.Steps, ResearchStep{Name: "extraction", Reasoning: extraction.Thinking})
+
+	// Search all sources concurrently, the channel is buffered so that searches finishing after cancellation don't block
+	searches := make(chan researchSearchResult, len(opts.Sources))
+	for _, source := range opts.Sources {
+		go func() {
+			documents, err := c.researchSearch(ctx, source, result.SearchTerm, question, opts)
+			searches <- researchSearchResult{source: source, documents: documents, err: err}
+		}()
+	}
+
+	collected := make(map[ResearchSource]researchSearchResult, len(opts.Sources))
+	for range opts.Sources {
+		select {
+		case search := <-searches:
+			collected[search.source] = search
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, source := range opts.Sources {
+		search := collected[source]
+		step := ResearchStep{Name: "search:" + string(source), Documents: len(search.documents)}
+		if search.err != nil {
+			step.Error = search.err.Error()
+		} else {
+			step.Reasoning = fmt.Sprintf("Found %d documents for %q", len(search.documents), result.SearchTerm)
+		}
+		result.Steps = append(result.Steps, step)
+
+		for _, document := range search.documents {
+			key := string(document.Source) + "/" + document.Id
+			if document.Id != "" && seen[key] {
+				continue
+			}
+			seen[key] = true
+			if len(result.Documents) < opts.MaxDocuments && strings.TrimSpace(document.Content) != "" {
+				result.Documents = append(result.Documents, document)
+			}
+		}
+	}
+	if len(result.Documents) == 0 {
+		return nil, fmt.Errorf("no documents found for search term %q", result.SearchTerm)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	data := make([]string, len(result.Documents))
+	for i, document := range result.Documents {
+		data[i] = fmt.Sprintf("[%d] %s", i+1, document.Content)
+	}
+	prompt := fmt.Sprintf("Answer the question %q using only the provided documents. "+
+		"Cite the documents that support the answer by their number in square brackets, e.g. [1].", question)
+	analysis, err := c.analyzeData(ctx, data, prompt, opts.Model, false, nil, question)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze %d documents: %w", len(data), err)
+	}
+
+	result.Answer = analysis.Analysis
+	result.ModelUsed = analysis.ModelUsed
+	result.TokensUsed = analysis.TokensUsed
+	result.Citations = citedDocuments(analysis.Analysis, result.Documents)
+	result.Steps = append(result.Steps, ResearchStep{Name: "analysis", Reasoning: analysis.Reasoning, Documents: len(result.Citations)})
+	return result, nil
+}
+
+// researchSearch runs the search for a single source
+func (c *Client) researchSearch(ctx context.Context, source ResearchSource, searchTerm string, question string, opts ResearchOptions) ([]types.Document, error) {
+	switch source {
+	case ResearchIndex:
+		return c.searchHybrid(ctx, searchTerm, opts.IndexSources, question, 0.5, 0.5, nil, "", opts.MaxResults)
+	case ResearchTwitter:
+		args := twitter.NewSearchArguments()
+		args.Query = searchTerm
+		args.MaxResults = opts.MaxResults
+		return c.runJob(ctx, newJobRequest(types.TwitterJob, args))
+	case ResearchReddit:
+		args := reddit.NewSearchPostsArguments()
+		args.Queries = []string{searchTerm}
+		args.MaxItems = uint(opts.MaxResults)
+		return c.runJob(ctx, newJobRequest(types.RedditJob, args))
+	}
+	return nil, fmt.Errorf("unknown research source %q", source)
+}
+
+// citedDocuments returns the documents referenced as [n] in the answer, in order of first citation
+func citedDocuments(answer string, documents []types.Document) []types.Document {
+	var cited []int
+	for _, match := range citationPattern.FindAllStringSubmatch(answer, -1) {
+		n, err := strconv.Atoi(match[1])
+		if err != nil || n < 1 || n > len(documents) || slices.Contains(cited, n) {
+			continue
+		}
+		cited = append(cited, n)
+	}
+
+	citations := make([]types.Document, len(cited))
+	for i, n := range cited {
+		citations[i] = documents[n-1]
+	}
+	return citations
+}
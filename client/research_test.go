@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	gophertypes "github.com/gopher-lab/gopher-client/types"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Research", func() {
+	var (
+		server   *httptest.Server
+		client   *Client
+		analysis gophertypes.AnalysisRequest
+	)
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /v1/extraction", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(gophertypes.ExtractionResponse{SearchTerm: "tesla competition", Thinking: "extracted"})
+		})
+		mux.HandleFunc("POST /v1/search/hybrid", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode([]types.Document{
+				{Id: "1", Source: types.WebSource, Content: "Tesla competes with BYD"},
+				{Id: "2", Source: types.WebSource, Content: "Rivian is a competitor"},
+				{Id: "3", Source: types.WebSource, Content: ""},
+			})
+		})
+		mux.HandleFunc("POST /v1/search/live", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(types.ResultResponse{UUID: "twitter-job"})
+		})
+		mux.HandleFunc("GET /v1/search/live/status/twitter-job", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(types.IndexerJobResult{Status: types.JobStatusDone})
+		})
+		mux.HandleFunc("GET /v1/search/live/result/twitter-job", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode([]types.Document{
+				{Id: "1", Source: types.WebSource, Content: "Tesla competes with BYD"},
+				{Id: "t1", Source: types.TwitterSource, Content: "BYD outsold Tesla"},
+			})
+		})
+		mux.HandleFunc("POST /v1/analysis", func(w http.ResponseWriter, r *http.Request) {
+			Expect(json.NewDecoder(r.Body).Decode(&analysis)).To(Succeed())
+			_ = json.NewEncoder(w).Encode(gophertypes.AnalysisResponse{
+				Analysis:   "BYD is the main competitor [1][3], see also [3] and [9]",
+				Reasoning:  "compared sources",
+				ModelUsed:  analysis.Model,
+				TokensUsed: 42,
+			})
+		})
+		server = httptest.NewServer(mux)
+		client = NewClient(server.URL, "test-token")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should extract, search and analyze", func() {
+		result, err := client.Research(context.Background(), "Who competes with Tesla?", ResearchOptions{
+			Sources: []ResearchSource{ResearchIndex, ResearchTwitter},
+			Model:   "openai/gpt-4o",
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.SearchTerm).To(Equal("tesla competition"))
+		Expect(result.Answer).To(ContainSubstring("BYD"))
+		Expect(result.ModelUsed).To(Equal("openai/gpt-4o"))
+		Expect(result.TokensUsed).To(Equal(42))
+
+		// Duplicates and empty documents are dropped
+		Expect(result.Documents).To(HaveLen(3))
+		Expect(analysis.Tweets).To(Equal([]string{
+			"[1] Tesla competes with BYD",
+			"[2] Rivian is a competitor",
+			"[3] BYD outsold Tesla",
+		}))
+		Expect(analysis.CurrentQuery).To(Equal("Who competes with Tesla?"))
+
+		Expect(result.Citations).To(HaveLen(2))
+		Expect(result.Citations[0].Id).To(Equal("1"))
+		Expect(result.Citations[1].Id).To(Equal("t1"))
+
+		Expect(result.Steps).To(HaveLen(4))
+		Expect(result.Steps[0]).To(Equal(ResearchStep{Name: "extraction", Reasoning: "extracted"}))
+		Expect(result.Steps[1].Name).To(Equal("search:index"))
+		Expect(result.Steps[2].Name).To(Equal("search:twitter"))
+		Expect(result.Steps[3].Reasoning).To(Equal("compared sources"))
+	})
+
+	It("should cap the number of analyzed documents", func() {
+		result, err := client.Research(context.Background(), "Who competes with Tesla?", ResearchOptions{MaxDocuments: 1})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Documents).To(HaveLen(1))
+	})
+
+	It("should record failing sources and continue with the others", func() {
+		result, err := client.Research(context.Background(), "Who competes with Tesla?", ResearchOptions{
+			Sources: []ResearchSource{ResearchIndex, "unknown"},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Steps[2].Error).To(ContainSubstring(`unknown research source "unknown"`))
+	})
+
+	It("should stop when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := client.Research(ctx, "Who competes with Tesla?", ResearchOptions{})
+
+		Expect(err).To(MatchError(context.Canceled))
+	})
+})
@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/types"
+)
+
+// defaultSessionHistory is the number of queries a Session keeps when no limit is specified
+const defaultSessionHistory = 20
+
+// maxContextualizeHistory is the maximum number of history items accepted by the contextualize endpoint
+const maxContextualizeHistory = 10
+
+// Session keeps the chat history of a conversation and feeds it into contextualize and analysis requests.
+// A Session is safe for concurrent use; queries are recorded in the order in which they complete.
+type Session struct {
+	client *Client
+
+	mu           sync.Mutex
+	history      []types.ChatHistoryItem
+	currentQuery string
+	maxHistory   int
+	model        string
+	now          func() time.Time
+}
+
+// SessionOption configures a Session
+type SessionOption func(*Session)
+
+// SessionMaxHistory sets the maximum number of queries kept in the session history. Older queries are dropped first. The default is 20.
+func SessionMaxHistory(n int) SessionOption {
+	return func(s *Session) {
+		if n > 0 {
+			s.maxHistory = n
+		}
+	}
+}
+
+// SessionModel sets the AI model used for analysis requests made through the session
+func SessionModel(model string) SessionOption {
+	return func(s *Session) {
+		s.model = model
+	}
+}
+
+// sessionState is the serialized form of a Session
+type sessionState struct {
+	History      []types.ChatHistoryItem `json:"history"`
+	CurrentQuery string                  `json:"currentQuery,omitempty"`
+	MaxHistory   int                     `json:"maxHistory"`
+	Model        string                  `json:"model,omitempty"`
+}
+
+// NewSession creates a new conversation session with an empty history
+func (c *Client) NewSession(opts ...SessionOption) *Session {
+	s := &Session{
+		client:     c,
+		maxHistory: defaultSessionHistory,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// RestoreSession recreates a session serialized with json.Marshal, e.g. after a restart
+func (c *Client) RestoreSession(data []byte) (*Session, error) {
+	var state sessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+
+	s := c.NewSession(SessionMaxHistory(state.MaxHistory), SessionModel(state.Model))
+	s.currentQuery = state.CurrentQuery
+	s.history = state.History
+	s.trim()
+	return s, nil
+}
+
+// MarshalJSON serializes the session history and settings
+func (s *Session) MarshalJSON() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return json.Marshal(sessionState{
+		History:      s.history,
+		CurrentQuery: s.currentQuery,
+		MaxHistory:   s.maxHistory,
+		Model:        s.model,
+	})
+}
+
+// Query records a query in the session history. Follow-up queries are contextualized with the previous
+// queries of the session; the first query is returned unchanged without calling the API.
+//
+// Args:
+//   - query: The user query
+//
+// Returns:
+//   - A pointer to ContextualizeResponse containing the contextualized query, or an error if the operation fails
+func (s *Session) Query(query string) (*types.ContextualizeResponse, error) {
+	return s.QueryContext(context.Background(), query)
+}
+
+// QueryContext is like Query but with a context for cancellation and the parent trace span
+func (s *Session) QueryContext(ctx context.Context, query string) (*types.ContextualizeResponse, error) {
+	history := s.History()
+
+	response := &types.ContextualizeResponse{
+		ContextualizedQuery: query,
+		OriginalQuery:       query,
+	}
+	if len(history) > 0 {
+		var err error
+		response, err = traced(ctx, s.client, "Session.Query", func(ctx context.Context) (*types.ContextualizeResponse, error) {
+			history := recentHistory(history)
+			return s.client.contextualizeQuery(ctx, query, history, len(history))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history = append(s.history, types.ChatHistoryItem{
+		Query:     query,
+		Timestamp: s.now().UTC().Format(time.RFC3339),
+	})
+	s.currentQuery = response.ContextualizedQuery
+	s.trim()
+
+	return response, nil
+}
+
+// Analyze analyzes data in the context of the session, passing the history before the latest
+// query as chat history and the latest contextualized query as the current query
+//
+// Args:
+//   - data: Array of tweets or other data to analyze
+//   - prompt: Analysis prompt
+//
+// Returns:
+//   - A pointer to AnalysisResponse containing the analysis results and metadata, or an error if the operation fails
+func (s *Session) Analyze(data []string, prompt string) (*types.AnalysisResponse, error) {
+	return s.AnalyzeContext(context.Background(), data, prompt)
+}
+
+// AnalyzeContext is like Analyze but with a context for cancellation and the parent trace span
+func (s *Session) AnalyzeContext(ctx context.Context, data []string, prompt string) (*types.AnalysisResponse, error) {
+	s.mu.Lock()
+	history := slices.Clone(s.history)
+	currentQuery := s.currentQuery
+	model := s.model
+	s.mu.Unlock()
+
+	if currentQuery != "" && len(history) > 0 {
+		// The latest query is passed as the current query
+		history = history[:len(history)-1]
+	}
+	history = recentHistory(history)
+	return traced(ctx, s.client, "Session.Analyze", func(ctx context.Context) (*types.AnalysisResponse, error) {
+		return s.client.analyzeData(ctx, data, prompt, model, false, history, currentQuery)
+	})
+}
+
+// History returns a copy of the recorded queries, oldest first
+func (s *Session) History() []types.ChatHistoryItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.history)
+}
+
+// CurrentQuery returns the contextualized form of the latest query
+func (s *Session) CurrentQuery() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.currentQuery
+}
+
+// Reset clears the session history
+func (s *Session) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history = nil
+	s.currentQuery = ""
+}
+
+// recentHistory returns the latest history items accepted by the API, oldest first
+func recentHistory(history []types.ChatHistoryItem) []types.ChatHistoryItem {
+	if len(history) > maxContextualizeHistory {
+		return history[len(history)-maxContextualizeHistory:]
+	}
+	return history
+}
+
+// trim drops the oldest queries exceeding the history limit, callers must hold s.mu
+func (s *Session) trim() {
+	if len(s.history) > s.maxHistory {
+		s.history = slices.Clone(s.history[len(s.history)-s.maxHistory:])
+	}
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session", func() {
+	var (
+		server                *httptest.Server
+		client                *Client
+		contextualizeRequests []types.ContextualizeRequest
+		analysisRequests      []types.AnalysisRequest
+	)
+
+	BeforeEach(func() {
+		contextualizeRequests = nil
+		analysisRequests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v1/contextualize":
+				var request types.ContextualizeRequest
+				Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+				contextualizeRequests = append(contextualizeRequests, request)
+				_ = json.NewEncoder(w).Encode(types.ContextualizeResponse{
+					ContextualizedQuery: fmt.Sprintf("%s (in context)", request.CurrentQuery),
+					OriginalQuery:       request.CurrentQuery,
+					UsedContext:         true,
+				})
+			case "/v1/analysis":
+				if r.Method == http.MethodGet {
+					_ = json.NewEncoder(w).Encode([]string{"openai/gpt-4o"})
+					return
+				}
+				var request types.AnalysisRequest
+				Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+				analysisRequests = append(analysisRequests, request)
+				_ = json.NewEncoder(w).Encode(types.AnalysisResponse{Analysis: "analysis", ModelUsed: request.Model})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		client = NewClient(server.URL, "test-token")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("Query", func() {
+		It("should not contextualize the first query", func() {
+			session := client.NewSession()
+
+			response, err := session.Query("Tell me about Tesla stock")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.ContextualizedQuery).To(Equal("Tell me about Tesla stock"))
+			Expect(response.UsedContext).To(BeFalse())
+			Expect(contextualizeRequests).To(BeEmpty())
+			Expect(session.History()).To(HaveLen(1))
+		})
+
+		It("should contextualize follow-up queries with timestamped history", func() {
+			session := client.NewSession()
+			session.now = func() time.Time { return time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC) }
+
+			_, err := session.Query("Tell me about Tesla stock")
+			Expect(err).NotTo(HaveOccurred())
+			response, err := session.Query("What about the competition?")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.ContextualizedQuery).To(Equal("What about the competition? (in context)"))
+			Expect(contextualizeRequests).To(HaveLen(1))
+			Expect(contextualizeRequests[0].ChatHistory).To(Equal([]types.ChatHistoryItem{
+				{Query: "Tell me about Tesla stock", Timestamp: "2024-01-15T10:00:00Z"},
+			}))
+			Expect(contextualizeRequests[0].MaxHistoryItems).To(Equal(1))
+			Expect(session.CurrentQuery()).To(Equal(response.ContextualizedQuery))
+		})
+
+		It("should enforce the history limit", func() {
+			session := client.NewSession(SessionMaxHistory(2))
+
+			for _, query := range []string{"one", "two", "three"} {
+				_, err := session.Query(query)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			history := session.History()
+			Expect(history).To(HaveLen(2))
+			Expect(history[0].Query).To(Equal("two"))
+			Expect(history[1].Query).To(Equal("three"))
+		})
+
+		It("should send only the latest history items accepted by the API", func() {
+			session := client.NewSession()
+			for i := range 15 {
+				_, err := session.Query(fmt.Sprintf("query %d", i))
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			last := contextualizeRequests[len(contextualizeRequests)-1]
+			Expect(last.ChatHistory).To(HaveLen(maxContextualizeHistory))
+			Expect(last.ChatHistory[0].Query).To(Equal("query 4"))
+			Expect(last.ChatHistory[maxContextualizeHistory-1].Query).To(Equal("query 13"))
+			Expect(last.MaxHistoryItems).To(Equal(maxContextualizeHistory))
+		})
+	})
+
+	Describe("Analyze", func() {
+		It("should feed the history and the current query into the analysis", func() {
+			session := client.NewSession(SessionModel("openai/gpt-4o"))
+			_, _ = session.Query("Tell me about Tesla stock")
+			_, _ = session.Query("What about the competition?")
+
+			_, err := session.Analyze([]string{"tweet"}, "Summarize")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(analysisRequests).To(HaveLen(1))
+			Expect(analysisRequests[0].Model).To(Equal("openai/gpt-4o"))
+			Expect(analysisRequests[0].CurrentQuery).To(Equal("What about the competition? (in context)"))
+			Expect(analysisRequests[0].ChatHistory).To(HaveLen(1))
+			Expect(analysisRequests[0].ChatHistory[0].Query).To(Equal("Tell me about Tesla stock"))
+		})
+
+		It("should send only the latest history items accepted by the API", func() {
+			session := client.NewSession()
+			for i := range 15 {
+				_, err := session.Query(fmt.Sprintf("query %d", i))
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			_, err := session.Analyze([]string{"tweet"}, "Summarize")
+
+			Expect(err).NotTo(HaveOccurred())
+			history := analysisRequests[0].ChatHistory
+			Expect(history).To(HaveLen(maxContextualizeHistory))
+			Expect(history[0].Query).To(Equal("query 4"))
+			Expect(history[maxContextualizeHistory-1].Query).To(Equal("query 13"))
+		})
+	})
+
+	Describe("Serialization", func() {
+		It("should restore a marshaled session", func() {
+			session := client.NewSession(SessionMaxHistory(5), SessionModel("openai/gpt-4o"))
+			_, _ = session.Query("Tell me about Tesla stock")
+			_, _ = session.Query("What about the competition?")
+
+			data, err := json.Marshal(session)
+			Expect(err).NotTo(HaveOccurred())
+
+			restored, err := client.RestoreSession(data)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(restored.History()).To(Equal(session.History()))
+			Expect(restored.CurrentQuery()).To(Equal(session.CurrentQuery()))
+			Expect(restored.maxHistory).To(Equal(5))
+			Expect(restored.model).To(Equal("openai/gpt-4o"))
+		})
+
+		It("should return an error for malformed data", func() {
+			_, err := client.RestoreSession([]byte("not json"))
+
+			Expect(err).To(MatchError(ContainSubstring("failed to unmarshal session")))
+		})
+	})
+})
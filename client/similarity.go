@@ -1,9 +1,9 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 
-	"github.com/gopher-lab/gopher-client/log"
     "github.com/masa-finance/tee-worker/v2/api/params"
     "github.com/masa-finance/tee-worker/v2/api/types"
 )
@@ -16,23 +16,37 @@ func (c *Client) SearchSimilarity(
 	operator string,
 	maxResults int,
 ) ([]types.Document, error) {
-	requestBody, err := json.Marshal(params.SimilaritySearch{
-		Query:           query,
-		Keywords:        keywords,
-		Sources:         sources,
-		MaxResults:      maxResults,
-		KeywordOperator: operator,
-	})
-	if err != nil {
-		log.Error("Error while performing similarity search", "query", query, "keywords", keywords, "error", err.Error())
-		return nil, err
-	}
+	return c.SearchSimilarityContext(context.Background(), query, sources, keywords, operator, maxResults)
+}
+
+// SearchSimilarityContext is like SearchSimilarity but with a context for cancellation and the parent trace span
+func (c *Client) SearchSimilarityContext(
+	ctx context.Context,
+	query string,
+	sources []types.Source,
+	keywords []string,
+	operator string,
+	maxResults int,
+) ([]types.Document, error) {
+	return traced(ctx, c, "SearchSimilarity", func(ctx context.Context) ([]types.Document, error) {
+		requestBody, err := json.Marshal(params.SimilaritySearch{
+			Query:           query,
+			Keywords:        keywords,
+			Sources:         sources,
+			MaxResults:      maxResults,
+			KeywordOperator: operator,
+		})
+		if err != nil {
+			c.log().ErrorContext(ctx, "Error while performing similarity search", "query", query, "keywords", keywords, "error", err.Error())
+			return nil, err
+		}
 
-	var results []types.Document
-	err = c.doImmediateRequest(c.BaseURL+"/v1/search/similarity", requestBody, &results)
-	if err != nil {
-		log.Error("Error while performing similarity search", "query", query, "keywords", keywords, "error", err.Error())
-		return nil, err
-	}
-	return results, nil
+		var results []types.Document
+		err = c.post(ctx, c.BaseURL+"/v1/search/similarity", requestBody, &results)
+		if err != nil {
+			c.log().ErrorContext(ctx, "Error while performing similarity search", "query", query, "keywords", keywords, "error", err.Error())
+			return nil, err
+		}
+		return results, nil
+	})
 }
@@ -0,0 +1,261 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/masa-finance/tee-worker/v2/api/types"
+)
+
+// streamAccept is the Accept header of streamed results, preferring formats that need no array decoding
+const streamAccept = "application/x-ndjson, text/event-stream;q=0.9, application/json;q=0.8"
+
+// StreamResult streams the result of a finished job, decoding its documents one by one so that large results
+// are processed in bounded memory. Results sent as a JSON array, newline-delimited JSON or Server-Sent Events
+// are supported. The iteration ends after the first error. Streamed results are not verified, use
+// GetVerifiedResult with attestation.
+//
+//	for doc, err := range c.StreamResult(ctx, jobID) {
+//		if err != nil {
+//			return err
+//		}
+//		process(doc)
+//	}
+func (c *Client) StreamResult(ctx context.Context, jobID string) iter.Seq2[types.Document, error] {
+	return func(yield func(types.Document, error) bool) {
+		ctx := withJobID(ctx, jobID)
+		if _, err := c.streamResult(ctx, jobID, func(doc types.Document) bool { return yield(doc, nil) }); err != nil {
+			yield(types.Document{}, err)
+		}
+	}
+}
+
+// StreamJobResults polls the job status until completion like WaitForJobCompletion and streams the result
+// like StreamResult
+func (c *Client) StreamJobResults(ctx context.Context, jobID string) iter.Seq2[types.Document, error] {
+	return func(yield func(types.Document, error) bool) {
+		ctx := withJobID(ctx, jobID)
+		status, start, err := c.waitForJobDone(ctx, jobID)
+		if err != nil {
+			yield(types.Document{}, err)
+			return
+		}
+
+		count, err := c.streamResult(ctx, jobID, func(doc types.Document) bool { return yield(doc, nil) })
+		if err != nil {
+			c.jobFinished(ctx, jobID, JobOutcomeError, start)
+			yield(types.Document{}, fmt.Errorf("failed to get job results: %w", err))
+			return
+		}
+		c.storeStatus(ctx, jobID, status, "")
+		c.jobFinished(ctx, jobID, JobOutcomeDone, start)
+		c.log().DebugContext(ctx, "Job result streamed", "job_id", jobID, "documents", count)
+	}
+}
+
+// streamResult gets the result of a job and calls fn with every document until it returns false, returning the
+// number of documents
+func (c *Client) streamResult(ctx context.Context, jobID string, fn func(types.Document) bool) (int, error) {
+	if c.verifier != nil {
+		return 0, errors.New("streamed results cannot be verified, use GetVerifiedResult")
+	}
+
+	url := c.BaseURL + jobEndpoint + "/result/" + jobID
+	resp, err := c.open(ctx, apiRequest{method: http.MethodGet, url: url, header: http.Header{"Accept": {streamAccept}}})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var count int
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-ndjson", "application/jsonl", "application/jsonlines":
+		count, err = decodeJSONLines(resp.Body, fn)
+	case "text/event-stream":
+		count, err = decodeEvents(resp.Body, fn)
+	default:
+		count, err = decodeDocumentArray(resp.Body, fn)
+	}
+	if err != nil {
+		return count, fmt.Errorf("failed to decode GET %s response after %d documents: %w", url, count, err)
+	}
+	return count, nil
+}
+
+// decodeDocumentArray decodes a JSON array of documents element by element. A JSON object is decoded as an
+// error response.
+func decodeDocumentArray(r io.Reader, fn func(types.Document) bool) (int, error) {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	decoder := json.NewDecoder(br)
+	switch first {
+	case '{':
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return 0, err
+		}
+		if err := getErrorFromResponse(raw); err != nil {
+			return 0, err
+		}
+		return 0, errors.New("unexpected JSON object instead of a document array")
+	case 'n':
+		var null any
+		return 0, decoder.Decode(&null)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return 0, err
+	}
+	count := 0
+	for decoder.More() {
+		var doc types.Document
+		if err := decoder.Decode(&doc); err != nil {
+			return count, err
+		}
+		count++
+		if !fn(doc) {
+			return count, nil
+		}
+	}
+	_, err = decoder.Token()
+	return count, err
+}
+
+// decodeJSONLines decodes newline-delimited JSON documents, failing on a line with an error
+func decodeJSONLines(r io.Reader, fn func(types.Document) bool) (int, error) {
+	decoder := json.NewDecoder(r)
+	count := 0
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			return count, nil
+		} else if err != nil {
+			return count, err
+		}
+		doc, err := decodeDocument(raw)
+		if err != nil {
+			return count, err
+		}
+		count++
+		if !fn(doc) {
+			return count, nil
+		}
+	}
+}
+
+// decodeEvents decodes Server-Sent Events whose data are documents or arrays of documents. An "error" event
+// fails the stream, an "end" event or a [DONE] data ends it.
+func decodeEvents(r io.Reader, fn func(types.Document) bool) (int, error) {
+	br := bufio.NewReader(r)
+	count := 0
+	var event string
+	var data bytes.Buffer
+	for {
+		line, readErr := br.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return count, readErr
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" && (data.Len() > 0 || event != "") {
+			n, done, err := dispatchEvent(event, data.Bytes(), fn)
+			count += n
+			if err != nil || done {
+				return count, err
+			}
+			event = ""
+			data.Reset()
+		} else if line != "" && !strings.HasPrefix(line, ":") {
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				event = value
+			case "data":
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(value)
+			}
+		}
+
+		if readErr == io.EOF {
+			if data.Len() > 0 {
+				n, _, err := dispatchEvent(event, data.Bytes(), fn)
+				count += n
+				return count, err
+			}
+			return count, nil
+		}
+	}
+}
+
+// dispatchEvent decodes the documents of an event and reports whether the stream is done
+func dispatchEvent(event string, data []byte, fn func(types.Document) bool) (int, bool, error) {
+	data = bytes.TrimSpace(data)
+	switch {
+	case event == "error":
+		if err := getErrorFromResponse(data); err != nil {
+			return 0, true, err
+		}
+		return 0, true, fmt.Errorf("job errored: %s", data)
+	case event == "end" || event == "done" || string(data) == "[DONE]":
+		return 0, true, nil
+	case len(data) == 0:
+		return 0, false, nil
+	case data[0] == '[':
+		stopped := false
+		n, err := decodeDocumentArray(bytes.NewReader(data), func(doc types.Document) bool {
+			stopped = !fn(doc)
+			return !stopped
+		})
+		return n, stopped, err
+	}
+	doc, err := decodeDocument(data)
+	if err != nil {
+		return 0, true, err
+	}
+	return 1, !fn(doc), nil
+}
+
+// decodeDocument decodes a document, or the error of an error object
+func decodeDocument(data []byte) (types.Document, error) {
+	if err := getErrorFromResponse(data); err != nil {
+		return types.Document{}, err
+	}
+	var doc types.Document
+	err := json.Unmarshal(data, &doc)
+	return doc, err
+}
+
+// peekNonSpace skips JSON whitespace and returns the next byte without consuming it
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = br.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Streaming results", func() {
+	var (
+		server *httptest.Server
+		client *Client
+	)
+
+	results := map[string]struct {
+		contentType string
+		body        string
+	}{
+		"array":  {"application/json", `[{"id": "1", "content": "one"}, {"id": "2", "content": "two"}, {"id": "3", "content": "three"}]`},
+		"ndjson": {"application/x-ndjson", "{\"id\": \"1\", \"content\": \"one\"}\n{\"id\": \"2\", \"content\": \"two\"}\n{\"id\": \"3\", \"content\": \"three\"}\n"},
+		"sse":    {"text/event-stream", ": keep-alive\n\ndata: {\"id\": \"1\", \"content\": \"one\"}\n\ndata: [{\"id\": \"2\", \"content\": \"two\"},\ndata: {\"id\": \"3\", \"content\": \"three\"}]\n\nevent: end\ndata: {}\n\n"},
+		"failed": {"application/x-ndjson", "{\"id\": \"1\", \"content\": \"one\"}\n{\"error\": \"scraper crashed\"}\n"},
+		"event":  {"text/event-stream", "data: {\"id\": \"1\", \"content\": \"one\"}\n\nevent: error\ndata: {\"error\": \"scraper crashed\"}\n\n"},
+	}
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /v1/search/live/result/{id}", func(w http.ResponseWriter, r *http.Request) {
+			result, ok := results[r.PathValue("id")]
+			if !ok {
+				http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+				return
+			}
+			Expect(r.Header.Get("Accept")).To(ContainSubstring("application/x-ndjson"))
+			w.Header().Set("Content-Type", result.contentType)
+			_, _ = w.Write([]byte(result.body))
+		})
+		mux.HandleFunc("GET /v1/search/live/status/array", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"status": "done"}`))
+		})
+		server = httptest.NewServer(mux)
+
+		var err error
+		client, err = NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	collect := func(jobID string) ([]string, error) {
+		var contents []string
+		for doc, err := range client.StreamResult(context.Background(), jobID) {
+			if err != nil {
+				return contents, err
+			}
+			contents = append(contents, doc.Content)
+		}
+		return contents, nil
+	}
+
+	DescribeTable("should decode the documents one by one",
+		func(jobID string) {
+			contents, err := collect(jobID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contents).To(Equal([]string{"one", "two", "three"}))
+		},
+		Entry("from a JSON array", "array"),
+		Entry("from newline-delimited JSON", "ndjson"),
+		Entry("from Server-Sent Events", "sse"),
+	)
+
+	DescribeTable("should end with the error of the result",
+		func(jobID string) {
+			contents, err := collect(jobID)
+			Expect(err).To(MatchError(ContainSubstring("scraper crashed")))
+			Expect(contents).To(Equal([]string{"one"}))
+		},
+		Entry("in newline-delimited JSON", "failed"),
+		Entry("in an error event", "event"),
+	)
+
+	It("should fail on error statuses", func() {
+		_, err := collect("missing")
+		Expect(err).To(MatchError(ContainSubstring("Status code 404")))
+	})
+
+	It("should stop when the loop breaks", func() {
+		var docs []types.Document
+		for doc, err := range client.StreamResult(context.Background(), "ndjson") {
+			Expect(err).NotTo(HaveOccurred())
+			docs = append(docs, doc)
+			break
+		}
+		Expect(docs).To(HaveLen(1))
+	})
+
+	It("should wait for the job before streaming its result", func() {
+		var contents []string
+		for doc, err := range client.StreamJobResults(context.Background(), "array") {
+			Expect(err).NotTo(HaveOccurred())
+			contents = append(contents, doc.Content)
+		}
+		Expect(contents).To(Equal([]string{"one", "two", "three"}))
+	})
+})
@@ -0,0 +1,383 @@
+package client
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/types"
+)
+
+// defaultStructuredRetries is the number of corrective retries made by AnalyzeInto when none is specified
+const defaultStructuredRetries = 2
+
+// JSONSchema is the subset of JSON Schema that is derived from Go types and used to validate structured analysis output
+type JSONSchema struct {
+	Type        string                 `json:"type,omitempty"`
+	Format      string                 `json:"format,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Items       *JSONSchema            `json:"items,omitempty"`
+	Values      *JSONSchema            `json:"additionalProperties,omitempty"`
+
+	nullable bool // Whether null is accepted, true for Go pointers, slices and maps
+}
+
+// SchemaValidationError is returned when a value does not conform to a JSONSchema
+type SchemaValidationError struct {
+	Path    string // JSON path of the offending value, e.g. "$.labels[2]"
+	Message string // Description of the violation
+}
+
+func (e *SchemaValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// StructuredOptions configures AnalyzeInto
+type StructuredOptions struct {
+	Model        string                  // AI model to use for analysis (optional, defaults to "openai/gpt-4o-mini")
+	MaxRetries   int                     // Number of corrective retries when the output fails validation (optional, defaults to 2, use -1 to disable)
+	App          bool                    // Whether this is an app request (optional, defaults to false)
+	ChatHistory  []types.ChatHistoryItem // Previous chat history for context (optional)
+	CurrentQuery string                  // Current query being analyzed (optional)
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// SchemaFor derives a JSON schema from the Go type T.
+// Struct fields follow the encoding/json naming rules; fields without omitempty and of non-pointer type are required.
+// The optional `description` and `enum` (comma separated) struct tags are added to the field schema.
+func SchemaFor[T any]() (*JSONSchema, error) {
+	return schemaOf(reflect.TypeOf((*T)(nil)).Elem(), nil)
+}
+
+func schemaOf(t reflect.Type, seen []reflect.Type) (*JSONSchema, error) {
+	nullable := t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Map
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	schema, err := schemaOfElem(t, seen)
+	if err != nil {
+		return nil, err
+	}
+	schema.nullable = nullable
+	return schema, nil
+}
+
+func schemaOfElem(t reflect.Type, seen []reflect.Type) (*JSONSchema, error) {
+	switch {
+	case t == timeType:
+		return &JSONSchema{Type: "string", Format: "date-time"}, nil
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		// Custom JSON encodings can't be described, accept any value
+		return &JSONSchema{}, nil
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &JSONSchema{Type: "string"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: "string"}, nil
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}, nil
+	case reflect.Interface:
+		return &JSONSchema{}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := schemaOf(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		values, err := schemaOf(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchema{Type: "object", Values: values}, nil
+	case reflect.Struct:
+		if slices.Contains(seen, t) {
+			// Recursive types are not expanded any further
+			return &JSONSchema{Type: "object"}, nil
+		}
+		schema := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}}
+		if err := addStructFields(schema, t, append(seen, t)); err != nil {
+			return nil, err
+		}
+		return schema, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+func addStructFields(schema *JSONSchema, t reflect.Type, seen []reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := addStructFields(schema, embedded, seen); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema, err := schemaOf(field.Type, seen)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if description := field.Tag.Get("description"); description != "" {
+			fieldSchema.Description = description
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			fieldSchema.Enum = strings.Split(enum, ",")
+		}
+		schema.Properties[name] = fieldSchema
+
+		optional := field.Type.Kind() == reflect.Pointer || slices.Contains(strings.Split(opts, ","), "omitempty")
+		if !optional {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return nil
+}
+
+// Validate checks that a decoded JSON value (as produced by a json.Decoder with UseNumber) conforms to the schema
+func (s *JSONSchema) Validate(value any) error {
+	return s.validate("$", value)
+}
+
+func (s *JSONSchema) validate(path string, value any) error {
+	if value == nil {
+		if s.Type == "" || s.nullable {
+			return nil
+		}
+		return &SchemaValidationError{Path: path, Message: fmt.Sprintf("expected %s, got null", s.Type)}
+	}
+
+	switch s.Type {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return &SchemaValidationError{Path: path, Message: fmt.Sprintf("expected string, got %s", jsonTypeName(value))}
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+			return &SchemaValidationError{Path: path, Message: fmt.Sprintf("%q is not one of %s", str, strings.Join(s.Enum, ", "))}
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return &SchemaValidationError{Path: path, Message: fmt.Sprintf("%q is not an RFC 3339 date-time", str)}
+			}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return &SchemaValidationError{Path: path, Message: fmt.Sprintf("expected boolean, got %s", jsonTypeName(value))}
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			return &SchemaValidationError{Path: path, Message: fmt.Sprintf("expected %s, got %s", s.Type, jsonTypeName(value))}
+		}
+		if s.Type == "integer" {
+			if _, err := number.Int64(); err != nil {
+				return &SchemaValidationError{Path: path, Message: fmt.Sprintf("expected integer, got %s", number)}
+			}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return &SchemaValidationError{Path: path, Message: fmt.Sprintf("expected array, got %s", jsonTypeName(value))}
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return &SchemaValidationError{Path: path, Message: fmt.Sprintf("expected object, got %s", jsonTypeName(value))}
+		}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				return &SchemaValidationError{Path: path, Message: fmt.Sprintf("missing required property %q", name)}
+			}
+		}
+		for name, property := range object {
+			propertySchema, ok := s.Properties[name]
+			if !ok {
+				propertySchema = s.Values
+			}
+			if propertySchema == nil {
+				continue
+			}
+			if err := propertySchema.validate(path+"."+name, property); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number, float64:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// AnalyzeInto analyzes data and decodes the model's answer into a value of type T.
+// A JSON schema derived from T is added to the prompt, the answer is validated against it and,
+// when validation fails, the request is retried with a corrective prompt.
+//
+// Args:
+//   - c: Client used to perform the analysis requests
+//   - data: Array of tweets or other data to analyze
+//   - prompt: Analysis prompt
+//   - opts: Model, retry and context options
+//
+// Returns:
+//   - The decoded value, the AnalysisResponse of the last request with the tokens used by all attempts,
+//     or an error if the requests fail or no attempt produced valid output
+func AnalyzeInto[T any](c *Client, data []string, prompt string, opts StructuredOptions) (T, *types.AnalysisResponse, error) {
+	return AnalyzeIntoContext[T](context.Background(), c, data, prompt, opts)
+}
+
+// AnalyzeIntoContext is like AnalyzeInto but with a context for cancellation and the parent trace span
+func AnalyzeIntoContext[T any](ctx context.Context, c *Client, data []string, prompt string, opts StructuredOptions) (T, *types.AnalysisResponse, error) {
+	var result T
+	var response *types.AnalysisResponse
+	_, err := traced(ctx, c, "AnalyzeInto", func(ctx context.Context) (struct{}, error) {
+		var err error
+		result, response, err = analyzeInto[T](ctx, c, data, prompt, opts)
+		return struct{}{}, err
+	})
+	return result, response, err
+}
+
+func analyzeInto[T any](ctx context.Context, c *Client, data []string, prompt string, opts StructuredOptions) (T, *types.AnalysisResponse, error) {
+	var result T
+
+	schema, err := SchemaFor[T]()
+	if err != nil {
+		return result, nil, fmt.Errorf("failed to derive JSON schema for %T: %w", result, err)
+	}
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return result, nil, err
+	}
+
+	retries := opts.MaxRetries
+	if retries == 0 {
+		retries = defaultStructuredRetries
+	}
+	retries = max(retries, 0)
+
+	basePrompt := fmt.Sprintf("%s\n\nRespond only with a JSON value that conforms to the following JSON schema, "+
+		"without any explanation or markdown formatting:\n%s", prompt, schemaJSON)
+	attemptPrompt := basePrompt
+
+	tokensUsed := 0
+	for attempt := 0; ; attempt++ {
+		response, err := c.analyzeData(ctx, data, attemptPrompt, opts.Model, opts.App, opts.ChatHistory, opts.CurrentQuery)
+		if err != nil {
+			return result, nil, err
+		}
+		tokensUsed += response.TokensUsed
+		response.TokensUsed = tokensUsed
+
+		output := extractJSON(response.Analysis)
+		err = decodeStructured(output, schema, &result)
+		if err == nil {
+			return result, response, nil
+		}
+		if attempt >= retries {
+			return result, response, fmt.Errorf("analysis output did not match the schema after %d attempts: %w", attempt+1, err)
+		}
+
+		attemptPrompt = fmt.Sprintf("%s\n\nYour previous response was invalid: %s\nPrevious response:\n%s\n"+
+			"Respond again with only the corrected JSON value.", basePrompt, err, output)
+	}
+}
+
+// decodeStructured validates output against the schema and unmarshals it into receiver
+func decodeStructured(output string, schema *JSONSchema, receiver any) error {
+	decoder := json.NewDecoder(strings.NewReader(output))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("response is not valid JSON: %w", err)
+	}
+	if err := schema.Validate(value); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(output), receiver)
+}
+
+// extractJSON strips markdown code fences and surrounding prose from a model answer
+func extractJSON(output string) string {
+	output = strings.TrimSpace(output)
+	if strings.HasPrefix(output, "```") {
+		output = strings.TrimPrefix(output, "```")
+		output = strings.TrimPrefix(output, "json")
+		output = strings.TrimSuffix(strings.TrimSpace(output), "```")
+		return strings.TrimSpace(output)
+	}
+
+	start := strings.IndexAny(output, "{[")
+	if start < 0 {
+		return output
+	}
+	closing := byte('}')
+	if output[start] == '[' {
+		closing = ']'
+	}
+	end := strings.LastIndexByte(output, closing)
+	if end < start {
+		return output
+	}
+	return output[start : end+1]
+}
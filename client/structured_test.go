@@ -0,0 +1,150 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type sentimentResult struct {
+	Sentiment string    `json:"sentiment" enum:"positive,neutral,negative" description:"Overall sentiment"`
+	Score     float64   `json:"score"`
+	Labels    []string  `json:"labels,omitempty"`
+	Topic     *string   `json:"topic"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	Ignored   string    `json:"-"`
+}
+
+var _ = Describe("Structured Analysis", func() {
+	Describe("SchemaFor", func() {
+		It("should derive a schema from struct fields and tags", func() {
+			schema, err := SchemaFor[sentimentResult]()
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schema.Type).To(Equal("object"))
+			Expect(schema.Required).To(Equal([]string{"sentiment", "score"}))
+			Expect(schema.Properties).To(HaveLen(5))
+			Expect(schema.Properties["sentiment"].Enum).To(Equal([]string{"positive", "neutral", "negative"}))
+			Expect(schema.Properties["sentiment"].Description).To(Equal("Overall sentiment"))
+			Expect(schema.Properties["score"].Type).To(Equal("number"))
+			Expect(schema.Properties["labels"].Items.Type).To(Equal("string"))
+			Expect(schema.Properties["created_at"].Format).To(Equal("date-time"))
+		})
+
+		It("should reject unsupported types", func() {
+			_, err := SchemaFor[struct{ C chan int }]()
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Validate", func() {
+		var schema *JSONSchema
+
+		BeforeEach(func() {
+			var err error
+			schema, err = SchemaFor[sentimentResult]()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should report missing required properties", func() {
+			err := decodeStructured(`{"sentiment": "positive"}`, schema, &sentimentResult{})
+
+			Expect(err).To(MatchError(ContainSubstring(`missing required property "score"`)))
+		})
+
+		It("should report values outside the enum with their path", func() {
+			err := decodeStructured(`{"sentiment": "great", "score": 1, "labels": ["a"]}`, schema, &sentimentResult{})
+
+			var validationErr *SchemaValidationError
+			Expect(err).To(BeAssignableToTypeOf(validationErr))
+			Expect(err.(*SchemaValidationError).Path).To(Equal("$.sentiment"))
+		})
+
+		It("should report wrongly typed array items", func() {
+			err := decodeStructured(`{"sentiment": "positive", "score": 1, "labels": ["a", 2]}`, schema, &sentimentResult{})
+
+			Expect(err).To(MatchError(ContainSubstring("$.labels[1]: expected string, got number")))
+		})
+	})
+
+	Describe("extractJSON", func() {
+		It("should strip markdown code fences", func() {
+			Expect(extractJSON("```json\n{\"a\": 1}\n```")).To(Equal(`{"a": 1}`))
+		})
+
+		It("should strip surrounding prose", func() {
+			Expect(extractJSON(`Here you go: [1, 2] Hope this helps`)).To(Equal(`[1, 2]`))
+		})
+	})
+
+	Describe("AnalyzeInto", func() {
+		var (
+			server  *httptest.Server
+			client  *Client
+			prompts []string
+			answers []string
+		)
+
+		BeforeEach(func() {
+			prompts = nil
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				var request types.AnalysisRequest
+				Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+				prompts = append(prompts, request.Prompt)
+
+				answer := answers[0]
+				answers = answers[1:]
+				_ = json.NewEncoder(w).Encode(types.AnalysisResponse{Analysis: answer, TokensUsed: 5, JobUUID: "job"})
+			}))
+			client = NewClient(server.URL, "test-token")
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should decode a valid answer", func() {
+			answers = []string{`{"sentiment": "positive", "score": 0.9, "topic": null}`}
+
+			result, response, err := AnalyzeInto[sentimentResult](client, []string{"great"}, "Classify", StructuredOptions{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Sentiment).To(Equal("positive"))
+			Expect(result.Score).To(Equal(0.9))
+			Expect(response.TokensUsed).To(Equal(5))
+			Expect(prompts[0]).To(ContainSubstring(`"enum":["positive","neutral","negative"]`))
+		})
+
+		It("should retry with a corrective prompt when validation fails", func() {
+			answers = []string{`{"sentiment": "great"}`, "```json\n{\"sentiment\": \"neutral\", \"score\": 0}\n```"}
+
+			result, response, err := AnalyzeInto[sentimentResult](client, []string{"ok"}, "Classify", StructuredOptions{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Sentiment).To(Equal("neutral"))
+			Expect(response.TokensUsed).To(Equal(10))
+			Expect(prompts).To(HaveLen(2))
+			Expect(prompts[1]).To(ContainSubstring("Your previous response was invalid"))
+		})
+
+		It("should give up after the configured number of retries", func() {
+			answers = []string{"not json", "still not json"}
+
+			_, _, err := AnalyzeInto[sentimentResult](client, []string{"ok"}, "Classify", StructuredOptions{MaxRetries: 1})
+
+			Expect(err).To(MatchError(ContainSubstring("after 2 attempts")))
+			Expect(prompts).To(HaveLen(2))
+		})
+	})
+})
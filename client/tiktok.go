@@ -1,184 +1,164 @@
 package client
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 
     "github.com/masa-finance/tee-worker/v2/api/args/tiktok"
-    "github.com/masa-finance/tee-worker/v2/api/params"
     "github.com/masa-finance/tee-worker/v2/api/types"
 )
 
 // TranscribeTikTok performs a TikTok transcription and waits for completion, returning results directly
 func (c *Client) TranscribeTikTok(url string) ([]types.Document, error) {
-	resp, err := c.TranscribeTikTokAsync(url)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
+	return c.TranscribeTikTokContext(context.Background(), url)
+}
+
+// TranscribeTikTokContext is like TranscribeTikTok but with a context for cancellation and the parent trace span
+func (c *Client) TranscribeTikTokContext(ctx context.Context, url string) ([]types.Document, error) {
+	args := tiktok.NewTranscriptionArguments()
+	args.VideoURL = url
+	return traced(ctx, c, "TranscribeTikTok", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.TiktokJob, args))
+	})
 }
 
 // TranscribeTikTokAsync performs a TikTok transcription job and returns a job ID
 func (c *Client) TranscribeTikTokAsync(url string) (*types.ResultResponse, error) {
+	return c.TranscribeTikTokAsyncContext(context.Background(), url)
+}
+
+// TranscribeTikTokAsyncContext is like TranscribeTikTokAsync but with a context for cancellation and the parent trace span
+func (c *Client) TranscribeTikTokAsyncContext(ctx context.Context, url string) (*types.ResultResponse, error) {
 	args := tiktok.NewTranscriptionArguments()
 	args.VideoURL = url
-
-	body, err := json.Marshal(params.TikTokTranscription{
-		JobType: types.TiktokJob,
-		Args:    &args,
+	return traced(ctx, c, "TranscribeTikTokAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.TiktokJob, args))
 	})
-	if err != nil {
-		return nil, err
-	}
-	return c.doRequest(c.BaseURL+jobEndpoint, body)
 }
 
 // TranscribeTikTokWithArgs transcribes TikTok with custom arguments and waits for completion, returning results directly
 func (c *Client) TranscribeTikTokWithArgs(args tiktok.TranscriptionArguments) ([]types.Document, error) {
-	body, err := json.Marshal(params.TikTokTranscription{
-		JobType: types.TiktokJob,
-		Args:    &args,
+	return c.TranscribeTikTokWithArgsContext(context.Background(), args)
+}
+
+// TranscribeTikTokWithArgsContext is like TranscribeTikTokWithArgs but with a context for cancellation and the parent trace span
+func (c *Client) TranscribeTikTokWithArgsContext(ctx context.Context, args tiktok.TranscriptionArguments) ([]types.Document, error) {
+	return traced(ctx, c, "TranscribeTikTokWithArgs", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.TiktokJob, args))
 	})
-	if err != nil {
-		return nil, err
-	}
-	resp, err := c.doRequest(c.BaseURL+jobEndpoint, body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
 }
 
 // TranscribeTikTokWithArgsAsync transcribes TikTok with custom arguments and returns a job ID
 func (c *Client) TranscribeTikTokWithArgsAsync(args tiktok.TranscriptionArguments) (*types.ResultResponse, error) {
-	body, err := json.Marshal(params.TikTokTranscription{
-		JobType: types.TiktokJob,
-		Args:    &args,
+	return c.TranscribeTikTokWithArgsAsyncContext(context.Background(), args)
+}
+
+// TranscribeTikTokWithArgsAsyncContext is like TranscribeTikTokWithArgsAsync but with a context for cancellation and the parent trace span
+func (c *Client) TranscribeTikTokWithArgsAsyncContext(ctx context.Context, args tiktok.TranscriptionArguments) (*types.ResultResponse, error) {
+	return traced(ctx, c, "TranscribeTikTokWithArgsAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.TiktokJob, args))
 	})
-	if err != nil {
-		return nil, err
-	}
-	return c.doRequest(c.BaseURL+jobEndpoint, body)
 }
 
 // SearchTikTok performs a TikTok search and waits for completion, returning results directly
 func (c *Client) SearchTikTok(query string) ([]types.Document, error) {
-	resp, err := c.SearchTikTokAsync(query)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
+	return c.SearchTikTokContext(context.Background(), query)
+}
+
+// SearchTikTokContext is like SearchTikTok but with a context for cancellation and the parent trace span
+func (c *Client) SearchTikTokContext(ctx context.Context, query string) ([]types.Document, error) {
+	args := tiktok.NewQueryArguments()
+	args.Search = []string{query}
+	return traced(ctx, c, "SearchTikTok", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.TiktokJob, args))
+	})
 }
 
 // SearchTikTokAsync performs a TikTok search job and returns a job ID
 func (c *Client) SearchTikTokAsync(query string) (*types.ResultResponse, error) {
+	return c.SearchTikTokAsyncContext(context.Background(), query)
+}
+
+// SearchTikTokAsyncContext is like SearchTikTokAsync but with a context for cancellation and the parent trace span
+func (c *Client) SearchTikTokAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error) {
 	args := tiktok.NewQueryArguments()
 	args.Search = []string{query}
-
-	body, err := json.Marshal(params.TikTokSearch{
-		JobType: types.TiktokJob,
-		Args:    &args,
+	return traced(ctx, c, "SearchTikTokAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.TiktokJob, args))
 	})
-	if err != nil {
-		return nil, err
-	}
-	return c.doRequest(c.BaseURL+jobEndpoint, body)
 }
 
 // SearchTikTokWithArgs searches TikTok with query arguments and waits for completion, returning results directly
 func (c *Client) SearchTikTokWithArgs(args tiktok.QueryArguments) ([]types.Document, error) {
-	body, err := json.Marshal(params.TikTokSearch{
-		JobType: types.TiktokJob,
-		Args:    &args,
+	return c.SearchTikTokWithArgsContext(context.Background(), args)
+}
+
+// SearchTikTokWithArgsContext is like SearchTikTokWithArgs but with a context for cancellation and the parent trace span
+func (c *Client) SearchTikTokWithArgsContext(ctx context.Context, args tiktok.QueryArguments) ([]types.Document, error) {
+	return traced(ctx, c, "SearchTikTokWithArgs", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.TiktokJob, args))
 	})
-	if err != nil {
-		return nil, err
-	}
-	resp, err := c.doRequest(c.BaseURL+jobEndpoint, body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
 }
 
 // SearchTikTokWithArgsAsync searches TikTok with query arguments and returns a job ID
 func (c *Client) SearchTikTokWithArgsAsync(args tiktok.QueryArguments) (*types.ResultResponse, error) {
-	body, err := json.Marshal(params.TikTokSearch{
-		JobType: types.TiktokJob,
-		Args:    &args,
+	return c.SearchTikTokWithArgsAsyncContext(context.Background(), args)
+}
+
+// SearchTikTokWithArgsAsyncContext is like SearchTikTokWithArgsAsync but with a context for cancellation and the parent trace span
+func (c *Client) SearchTikTokWithArgsAsyncContext(ctx context.Context, args tiktok.QueryArguments) (*types.ResultResponse, error) {
+	return traced(ctx, c, "SearchTikTokWithArgsAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.TiktokJob, args))
 	})
-	if err != nil {
-		return nil, err
-	}
-	return c.doRequest(c.BaseURL+jobEndpoint, body)
 }
 
 // SearchTikTokTrending performs a TikTok trending search and waits for completion, returning results directly
 func (c *Client) SearchTikTokTrending(sortBy string) ([]types.Document, error) {
-	resp, err := c.SearchTikTokTrendingAsync(sortBy)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
+	return c.SearchTikTokTrendingContext(context.Background(), sortBy)
+}
+
+// SearchTikTokTrendingContext is like SearchTikTokTrending but with a context for cancellation and the parent trace span
+func (c *Client) SearchTikTokTrendingContext(ctx context.Context, sortBy string) ([]types.Document, error) {
+	args := tiktok.NewTrendingArguments()
+	args.SortBy = sortBy
+	return traced(ctx, c, "SearchTikTokTrending", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.TiktokJob, args))
+	})
 }
 
 // SearchTikTokTrendingAsync performs a TikTok trending search job and returns a job ID
 func (c *Client) SearchTikTokTrendingAsync(sortBy string) (*types.ResultResponse, error) {
+	return c.SearchTikTokTrendingAsyncContext(context.Background(), sortBy)
+}
+
+// SearchTikTokTrendingAsyncContext is like SearchTikTokTrendingAsync but with a context for cancellation and the parent trace span
+func (c *Client) SearchTikTokTrendingAsyncContext(ctx context.Context, sortBy string) (*types.ResultResponse, error) {
 	args := tiktok.NewTrendingArguments()
 	args.SortBy = sortBy
-
-	body, err := json.Marshal(params.TikTokTrending{
-		JobType: types.TiktokJob,
-		Args:    &args,
+	return traced(ctx, c, "SearchTikTokTrendingAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.TiktokJob, args))
 	})
-	if err != nil {
-		return nil, err
-	}
-	return c.doRequest(c.BaseURL+jobEndpoint, body)
 }
 
 // SearchTikTokTrendingWithArgs searches TikTok trending with custom arguments and waits for completion, returning results directly
 func (c *Client) SearchTikTokTrendingWithArgs(args tiktok.TrendingArguments) ([]types.Document, error) {
-	body, err := json.Marshal(params.TikTokTrending{
-		JobType: types.TiktokJob,
-		Args:    &args,
+	return c.SearchTikTokTrendingWithArgsContext(context.Background(), args)
+}
+
+// SearchTikTokTrendingWithArgsContext is like SearchTikTokTrendingWithArgs but with a context for cancellation and the parent trace span
+func (c *Client) SearchTikTokTrendingWithArgsContext(ctx context.Context, args tiktok.TrendingArguments) ([]types.Document, error) {
+	return traced(ctx, c, "SearchTikTokTrendingWithArgs", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.TiktokJob, args))
 	})
-	if err != nil {
-		return nil, err
-	}
-	resp, err := c.doRequest(c.BaseURL+jobEndpoint, body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
 }
 
 // SearchTikTokTrendingWithArgsAsync searches TikTok trending with custom arguments and returns a job ID
 func (c *Client) SearchTikTokTrendingWithArgsAsync(args tiktok.TrendingArguments) (*types.ResultResponse, error) {
-	body, err := json.Marshal(params.TikTokTrending{
-		JobType: types.TiktokJob,
-		Args:    &args,
+	return c.SearchTikTokTrendingWithArgsAsyncContext(context.Background(), args)
+}
+
+// SearchTikTokTrendingWithArgsAsyncContext is like SearchTikTokTrendingWithArgsAsync but with a context for cancellation and the parent trace span
+func (c *Client) SearchTikTokTrendingWithArgsAsyncContext(ctx context.Context, args tiktok.TrendingArguments) (*types.ResultResponse, error) {
+	return traced(ctx, c, "SearchTikTokTrendingWithArgsAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.TiktokJob, args))
 	})
-	if err != nil {
-		return nil, err
-	}
-	return c.doRequest(c.BaseURL+jobEndpoint, body)
 }
@@ -0,0 +1,249 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultTokenRefreshWindow is how long before expiry a cached token is refreshed in the background
+	DefaultTokenRefreshWindow = time.Minute
+	// DefaultCommandTokenTTL is how long the output of a token command is used when it reports no expiry
+	DefaultCommandTokenTTL = 5 * time.Minute
+	// tokenRefreshTimeout bounds a background token refresh, so that a hanging source doesn't stop refreshes
+	tokenRefreshTimeout = 30 * time.Second
+)
+
+// AccessToken is an API token with its expiry. A zero Expiry means the token does not expire.
+type AccessToken struct {
+	Value  string
+	Expiry time.Time
+}
+
+// expiresWithin reports whether the token expires within d of now
+func (t AccessToken) expiresWithin(d time.Duration, now time.Time) bool {
+	return !t.Expiry.IsZero() && !now.Add(d).Before(t.Expiry)
+}
+
+// TokenSource provides the API token sent with every request, allowing tokens to be rotated without
+// recreating the client. Implementations must be safe for concurrent use.
+type TokenSource interface {
+	Token(ctx context.Context) (AccessToken, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource
+type TokenSourceFunc func(ctx context.Context) (AccessToken, error)
+
+// Token calls f
+func (f TokenSourceFunc) Token(ctx context.Context) (AccessToken, error) {
+	return f(ctx)
+}
+
+// StaticToken returns a TokenSource that always provides token
+func StaticToken(token string) TokenSource {
+	return TokenSourceFunc(func(context.Context) (AccessToken, error) {
+		return AccessToken{Value: token}, nil
+	})
+}
+
+// EnvToken returns a TokenSource reading the token from the environment variable name on every request
+func EnvToken(name string) TokenSource {
+	return TokenSourceFunc(func(context.Context) (AccessToken, error) {
+		token, ok := os.LookupEnv(name)
+		if !ok {
+			return AccessToken{}, fmt.Errorf("environment variable %s is not set", name)
+		}
+		return AccessToken{Value: strings.TrimSpace(token)}, nil
+	})
+}
+
+// FileToken returns a TokenSource reading the token from the file at path, e.g. one written by a secret
+// manager sidecar. The file is read again whenever its modification time or size changes.
+func FileToken(path string) TokenSource {
+	return &fileTokenSource{path: path}
+}
+
+type fileTokenSource struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	token   string
+}
+
+func (s *fileTokenSource) Token(context.Context) (AccessToken, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return AccessToken{Value: s.token}, nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("failed to read token file: %w", err)
+	}
+	s.token = strings.TrimSpace(string(data))
+	s.modTime, s.size = info.ModTime(), info.Size()
+	return AccessToken{Value: s.token}, nil
+}
+
+// CommandToken returns a TokenSource running an external command for the token, e.g. a secret manager CLI.
+// The command prints either the token or a JSON object {"token": "...", "expiry": "<RFC 3339 time>"}.
+// Without an expiry the token is used for ttl, or DefaultCommandTokenTTL if ttl is not positive.
+// Wrap the source with CachedToken, or pass it to Credentials, to avoid running the command on every request.
+func CommandToken(ttl time.Duration, name string, args ...string) TokenSource {
+	if ttl <= 0 {
+		ttl = DefaultCommandTokenTTL
+	}
+	return TokenSourceFunc(func(ctx context.Context) (AccessToken, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		if err := cmd.Run(); err != nil {
+			return AccessToken{}, fmt.Errorf("token command %s failed: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+		}
+
+		output := bytes.TrimSpace(stdout.Bytes())
+		var result struct {
+			Token  string    `json:"token"`
+			Expiry time.Time `json:"expiry"`
+		}
+		if err := json.Unmarshal(output, &result); err != nil || result.Token == "" {
+			result.Token, result.Expiry = string(output), time.Time{}
+		}
+		if result.Token == "" {
+			return AccessToken{}, fmt.Errorf("token command %s printed no token", name)
+		}
+		if result.Expiry.IsZero() {
+			result.Expiry = time.Now().Add(ttl)
+		}
+		return AccessToken{Value: result.Token, Expiry: result.Expiry}, nil
+	})
+}
+
+// CachedToken returns a TokenSource caching the tokens of src until they expire. A token expiring within
+// refreshWindow is refreshed in the background while the cached one is still used, so requests do not wait
+// for the refresh. Concurrent requests without a valid token share a single refresh. A refresh taking longer
+// than 30 seconds is canceled, a background refresh is retried on the next request. Tokens without an expiry
+// are not cached. A non-positive refreshWindow uses DefaultTokenRefreshWindow.
+func CachedToken(src TokenSource, refreshWindow time.Duration) TokenSource {
+	if cached, ok := src.(*cachedTokenSource); ok {
+		return cached
+	}
+	if refreshWindow <= 0 {
+		refreshWindow = DefaultTokenRefreshWindow
+	}
+	return &cachedTokenSource{src: src, refreshWindow: refreshWindow, refreshTimeout: tokenRefreshTimeout}
+}
+
+type cachedTokenSource struct {
+	src            TokenSource
+	refreshWindow  time.Duration
+	refreshTimeout time.Duration
+
+	mu         sync.Mutex
+	token      AccessToken
+	refreshing bool
+	refreshes  singleflight.Group
+}
+
+func (s *cachedTokenSource) Token(ctx context.Context) (AccessToken, error) {
+	now := time.Now()
+	s.mu.Lock()
+	token := s.token
+	switch {
+	case token.Value == "" || token.expiresWithin(0, now):
+		s.mu.Unlock()
+		return s.refreshShared(ctx)
+	case token.expiresWithin(s.refreshWindow, now) && !s.refreshing:
+		s.refreshing = true
+		go func() {
+			defer func() {
+				s.mu.Lock()
+				s.refreshing = false
+				s.mu.Unlock()
+			}()
+			// the cached token remains valid until it expires, a failed refresh is retried on the next request
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.refreshTimeout)
+			defer cancel()
+			_, _ = s.refresh(ctx)
+		}()
+	}
+	s.mu.Unlock()
+	return token, nil
+}
+
+// refreshShared refreshes the token once for all concurrent callers. The refresh is not canceled with the
+// context of any one caller.
+func (s *cachedTokenSource) refreshShared(ctx context.Context) (AccessToken, error) {
+	ch := s.refreshes.DoChan("token", func() (any, error) {
+		// Another caller may have refreshed the token in the meantime
+		s.mu.Lock()
+		token := s.token
+		s.mu.Unlock()
+		if token.Value != "" && !token.expiresWithin(0, time.Now()) {
+			return token, nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.refreshTimeout)
+		defer cancel()
+		return s.refresh(ctx)
+	})
+	select {
+	case result := <-ch:
+		token, _ := result.Val.(AccessToken)
+		return token, result.Err
+	case <-ctx.Done():
+		return AccessToken{}, ctx.Err()
+	}
+}
+
+// refresh gets a new token from the source and caches it if it expires
+func (s *cachedTokenSource) refresh(ctx context.Context) (AccessToken, error) {
+	token, err := s.src.Token(ctx)
+	if err != nil {
+		return AccessToken{}, err
+	}
+	if token.Expiry.IsZero() {
+		return token, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token.Expiry.After(s.token.Expiry) {
+		s.token = token
+	}
+	return token, nil
+}
+
+// authorize sets the Authorization header of req from the token source, or from the static Token if there is none
+func (c *Client) authorize(req *http.Request) error {
+	token := c.Token
+	if c.tokens != nil {
+		accessToken, err := c.tokens.Token(req.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get API token: %w", err)
+		}
+		token = accessToken.Value
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return nil
+}
@@ -0,0 +1,322 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Token sources", func() {
+	ctx := context.Background()
+
+	It("should provide a static token", func() {
+		token, err := StaticToken("static").Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal(AccessToken{Value: "static"}))
+	})
+
+	It("should re-read the environment variable", func() {
+		src := EnvToken("GOPHER_TEST_TOKEN")
+		_, err := src.Token(ctx)
+		Expect(err).To(MatchError(ContainSubstring("GOPHER_TEST_TOKEN is not set")))
+
+		GinkgoT().Setenv("GOPHER_TEST_TOKEN", "first")
+		token, err := src.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.Value).To(Equal("first"))
+
+		GinkgoT().Setenv("GOPHER_TEST_TOKEN", "second")
+		token, err = src.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.Value).To(Equal("second"))
+	})
+
+	It("should reload the token file when it changes", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(path, []byte("first\n"), 0600)).To(Succeed())
+		src := FileToken(path)
+
+		token, err := src.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.Value).To(Equal("first"))
+
+		Expect(os.WriteFile(path, []byte("second\n"), 0600)).To(Succeed())
+		Expect(os.Chtimes(path, time.Now(), time.Now().Add(time.Second))).To(Succeed())
+		token, err = src.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.Value).To(Equal("second"))
+
+		Expect(os.Remove(path)).To(Succeed())
+		_, err = src.Token(ctx)
+		Expect(err).To(MatchError(ContainSubstring("failed to read token file")))
+	})
+
+	It("should run the token command", func() {
+		token, err := CommandToken(time.Minute, "echo", "command-token").Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.Value).To(Equal("command-token"))
+		Expect(token.Expiry).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+
+		token, err = CommandToken(time.Minute, "echo", `{"token": "json-token", "expiry": "2030-01-02T03:04:05Z"}`).Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.Value).To(Equal("json-token"))
+		Expect(token.Expiry).To(Equal(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+		_, err = CommandToken(time.Minute, "false").Token(ctx)
+		Expect(err).To(MatchError(ContainSubstring("token command false failed")))
+	})
+
+	Describe("CachedToken", func() {
+		var src *countingTokenSource
+
+		BeforeEach(func() {
+			src = &countingTokenSource{expiry: time.Hour}
+		})
+
+		It("should cache tokens until they are due for refresh", func() {
+			cached := CachedToken(src, time.Minute)
+			for range 3 {
+				token, err := cached.Token(ctx)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(token.Value).To(Equal("token-1"))
+			}
+			Expect(src.calls.Load()).To(Equal(int32(1)))
+		})
+
+		It("should refresh tokens in the background before they expire", func() {
+			src.expiry = 30 * time.Second
+			cached := CachedToken(src, time.Minute)
+
+			token, err := cached.Token(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.Value).To(Equal("token-1"))
+
+			token, err = cached.Token(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.Value).To(Equal("token-1"))
+
+			Eventually(func() string {
+				token, _ := cached.Token(ctx)
+				return token.Value
+			}).Should(Equal("token-2"))
+		})
+
+		It("should keep the cached token if a background refresh fails", func() {
+			src.expiry = 30 * time.Second
+			cached := CachedToken(src, time.Minute)
+			_, err := cached.Token(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			src.fail.Store(true)
+			Consistently(func() (string, error) {
+				token, err := cached.Token(ctx)
+				return token.Value, err
+			}, 50*time.Millisecond).Should(Equal("token-1"))
+			Expect(src.calls.Load()).To(BeNumerically(">", 1))
+		})
+
+		It("should cancel a hanging background refresh and retry it", func() {
+			var calls atomic.Int32
+			hanging := TokenSourceFunc(func(ctx context.Context) (AccessToken, error) {
+				n := calls.Add(1)
+				if n == 2 {
+					<-ctx.Done()
+					return AccessToken{}, ctx.Err()
+				}
+				return AccessToken{Value: fmt.Sprintf("token-%d", n), Expiry: time.Now().Add(30 * time.Second)}, nil
+			})
+			cached := CachedToken(hanging, time.Minute)
+			cached.(*cachedTokenSource).refreshTimeout = 50 * time.Millisecond
+
+			token, err := cached.Token(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.Value).To(Equal("token-1"))
+
+			Eventually(func() string {
+				token, _ := cached.Token(ctx)
+				return token.Value
+			}).Should(Equal("token-3"))
+		})
+
+		It("should share the refresh of concurrent requests without a token", func() {
+			release := make(chan struct{})
+			var calls atomic.Int32
+			slow := TokenSourceFunc(func(ctx context.Context) (AccessToken, error) {
+				n := calls.Add(1)
+				<-release
+				return AccessToken{Value: fmt.Sprintf("token-%d", n), Expiry: time.Now().Add(time.Hour)}, nil
+			})
+			cached := CachedToken(slow, time.Minute)
+
+			var wg sync.WaitGroup
+			tokens := make([]string, 8)
+			for i := range tokens {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					token, err := cached.Token(ctx)
+					Expect(err).NotTo(HaveOccurred())
+					tokens[i] = token.Value
+				}()
+			}
+			Eventually(calls.Load).Should(Equal(int32(1)))
+			time.Sleep(20 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			Expect(calls.Load()).To(Equal(int32(1)))
+			Expect(tokens).To(HaveEach("token-1"))
+		})
+
+		It("should not cancel a shared refresh with the context of a request", func() {
+			release := make(chan struct{})
+			slow := TokenSourceFunc(func(ctx context.Context) (AccessToken, error) {
+				select {
+				case <-release:
+				case <-ctx.Done():
+					return AccessToken{}, ctx.Err()
+				}
+				return AccessToken{Value: "token-1", Expiry: time.Now().Add(time.Hour)}, nil
+			})
+			cached := CachedToken(slow, time.Minute)
+
+			canceled, cancel := context.WithCancel(ctx)
+			cancel()
+			_, err := cached.Token(canceled)
+			Expect(err).To(MatchError(context.Canceled))
+
+			close(release)
+			token, err := cached.Token(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.Value).To(Equal("token-1"))
+		})
+
+		It("should refresh expired tokens before using them", func() {
+			src.expiry = -time.Second
+			cached := CachedToken(src, time.Minute)
+
+			token, err := cached.Token(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.Value).To(Equal("token-1"))
+			token, err = cached.Token(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.Value).To(Equal("token-2"))
+
+			src.fail.Store(true)
+			_, err = cached.Token(ctx)
+			Expect(err).To(MatchError("unavailable"))
+		})
+	})
+
+	Describe("Credentials", func() {
+		var (
+			server  *httptest.Server
+			mu      sync.Mutex
+			headers []string
+		)
+
+		BeforeEach(func() {
+			headers = nil
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				headers = append(headers, r.Header.Get("Authorization"))
+				mu.Unlock()
+				_, _ = w.Write([]byte(`{"status": "done"}`))
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should send the current token of the source with every request", func() {
+			GinkgoT().Setenv("GOPHER_TEST_TOKEN", "first")
+			client, err := NewClientWithOptions(server.URL, "ignored", Credentials(EnvToken("GOPHER_TEST_TOKEN")))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetJobStatus("job-1")
+			Expect(err).NotTo(HaveOccurred())
+			GinkgoT().Setenv("GOPHER_TEST_TOKEN", "second")
+			_, err = client.GetJobStatus("job-1")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(headers).To(Equal([]string{"Bearer first", "Bearer second"}))
+		})
+
+		It("should fail requests without a token", func() {
+			client, err := NewClientWithOptions(server.URL, "", Credentials(EnvToken("GOPHER_TEST_UNSET_TOKEN")))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetJobStatus("job-1")
+			Expect(err).To(MatchError(ContainSubstring("failed to get API token")))
+			Expect(headers).To(BeEmpty())
+		})
+
+		It("should use the token file of the config", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "token")
+			Expect(os.WriteFile(path, []byte("file-token"), 0600)).To(Succeed())
+			client, err := NewClientWithConfig(&config.Config{
+				BaseUrl:             server.URL,
+				Token:               "ignored",
+				TokenFile:           path,
+				Timeout:             time.Minute,
+				MaxConnsPerHost:     1,
+				MaxIdleConnsPerHost: 1,
+				MaxIdleConns:        1,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetJobStatus("job-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(headers).To(Equal([]string{"Bearer file-token"}))
+		})
+
+		It("should split the token command of the config like a shell", func() {
+			client, err := NewClientWithConfig(&config.Config{
+				BaseUrl:             server.URL,
+				TokenCommand:        `printf '%s' "command token"`,
+				Timeout:             time.Minute,
+				MaxConnsPerHost:     1,
+				MaxIdleConnsPerHost: 1,
+				MaxIdleConns:        1,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.GetJobStatus("job-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(headers).To(Equal([]string{"Bearer command token"}))
+		})
+
+		It("should reject a nil source", func() {
+			_, err := NewClientWithOptions(server.URL, "", Credentials(nil))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+// countingTokenSource numbers the tokens it provides, each expiring after expiry
+type countingTokenSource struct {
+	expiry time.Duration
+	calls  atomic.Int32
+	fail   atomic.Bool
+}
+
+func (s *countingTokenSource) Token(context.Context) (AccessToken, error) {
+	n := s.calls.Add(1)
+	if s.fail.Load() {
+		return AccessToken{}, errors.New("unavailable")
+	}
+	return AccessToken{Value: fmt.Sprintf("token-%d", n), Expiry: time.Now().Add(s.expiry)}, nil
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName is the instrumentation scope of the client's spans
+const tracerName = "github.com/gopher-lab/gopher-client/client"
+
+// Span attributes set on the span of the public method that submits or waits for a job
+const (
+	AttrJobUUID       = attribute.Key("gopher.job.uuid")
+	AttrJobType       = attribute.Key("gopher.job.type")
+	AttrJobStatus     = attribute.Key("gopher.job.status")
+	AttrJobPolls      = attribute.Key("gopher.job.polls")
+	AttrDocumentCount = attribute.Key("gopher.documents.count")
+	AttrJobVerified   = attribute.Key("gopher.job.verified")
+	AttrRequestID     = attribute.Key("gopher.request.id") // Set on the span of every HTTP request
+)
+
+// jobStatusEvent is the span event recorded whenever the status of a polled job changes
+const jobStatusEvent = "gopher.job.status_change"
+
+// traced runs fn in a span named after the public method, recording the returned error. The context of fn
+// carries the request ID of ctx, or a new one shared by all requests of the call.
+func traced[T any](ctx context.Context, c *Client, method string, fn func(ctx context.Context) (T, error)) (T, error) {
+	if id, _ := ctx.Value(requestIDKey).(string); id == "" {
+		ctx = WithRequestID(ctx, uuid.NewString())
+	}
+	ctx, span := c.startSpan(ctx, "Client."+method, trace.SpanKindInternal)
+	defer span.End()
+
+	result, err := fn(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
+}
+
+// startSpan starts a span if tracing is enabled, otherwise it returns ctx and a no-op span
+func (c *Client) startSpan(ctx context.Context, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if c.tracer == nil {
+		return ctx, noop.Span{}
+	}
+	return c.tracer.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// annotate sets attributes on the current span of ctx if tracing is enabled
+func (c *Client) annotate(ctx context.Context, attrs ...attribute.KeyValue) {
+	if c.tracer == nil {
+		return
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+}
+
+// statusChanged records a job status transition as an event on the current span of ctx
+func (c *Client) statusChanged(ctx context.Context, from types.JobStatus, to types.JobStatus) {
+	if c.tracer == nil {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent(jobStatusEvent, trace.WithAttributes(
+		attribute.String("gopher.job.status.previous", from.String()),
+		AttrJobStatus.String(to.String()),
+	))
+	span.SetAttributes(AttrJobStatus.String(to.String()))
+}
+
+// traceRequest starts the client span of an HTTP request and injects the trace context into its headers
+func (c *Client) traceRequest(req *http.Request) (*http.Request, trace.Span) {
+	if c.tracer == nil {
+		return req, noop.Span{}
+	}
+
+	ctx, span := c.startSpan(req.Context(), req.Method+" "+endpointLabel(req.URL.Path), trace.SpanKindClient,
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLPath(req.URL.Path),
+		semconv.ServerAddress(req.URL.Hostname()),
+		AttrRequestID.String(req.Header.Get(requestIDHeader)),
+	)
+	req = req.WithContext(ctx)
+	c.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return req, span
+}
+
+// endRequestSpan records the outcome of an HTTP request and ends its span
+func endRequestSpan(span trace.Span, resp *http.Response, err error) {
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+}
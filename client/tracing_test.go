@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var _ = Describe("Tracing", func() {
+	var (
+		server       *httptest.Server
+		recorder     *tracetest.SpanRecorder
+		mu           sync.Mutex
+		traceparents []string
+		statusPolls  int
+	)
+
+	BeforeEach(func() {
+		traceparents = nil
+		statusPolls = 0
+		mux := http.NewServeMux()
+		record := func(r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			traceparents = append(traceparents, r.Header.Get("traceparent"))
+		}
+		mux.HandleFunc("POST /v1/search/live", func(w http.ResponseWriter, r *http.Request) {
+			record(r)
+			_, _ = w.Write([]byte(`{"uuid": "job-1"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/status/job-1", func(w http.ResponseWriter, r *http.Request) {
+			record(r)
+			mu.Lock()
+			statusPolls++
+			status := "in progress"
+			if statusPolls > 1 {
+				status = "done"
+			}
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"status": "` + status + `"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/result/job-1", func(w http.ResponseWriter, r *http.Request) {
+			record(r)
+			_, _ = w.Write([]byte(`[{"id": "1", "content": "one"}, {"id": "2", "content": "two"}]`))
+		})
+		server = httptest.NewServer(mux)
+		recorder = tracetest.NewSpanRecorder()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	attributes := func(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+		values := make(map[attribute.Key]attribute.Value)
+		for _, kv := range span.Attributes() {
+			values[kv.Key] = kv.Value
+		}
+		return values
+	}
+
+	It("should trace a job with a child span per HTTP request and propagate the trace context", func() {
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		client, err := NewClientWithOptions(server.URL, "test-token", Tracing(provider))
+		Expect(err).NotTo(HaveOccurred())
+
+		docs, err := client.SearchTwitter("golang")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(docs).To(HaveLen(2))
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(5))
+		parent := spans[len(spans)-1]
+		Expect(parent.Name()).To(Equal("Client.SearchTwitter"))
+		Expect(parent.Parent().IsValid()).To(BeFalse())
+
+		var names []string
+		for _, span := range spans[:len(spans)-1] {
+			names = append(names, span.Name())
+			Expect(span.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+		}
+		Expect(names).To(Equal([]string{
+			"POST /v1/search/live",
+			"GET /v1/search/live/status",
+			"GET /v1/search/live/status",
+			"GET /v1/search/live/result",
+		}))
+
+		attrs := attributes(parent)
+		Expect(attrs[AttrJobUUID].AsString()).To(Equal("job-1"))
+		Expect(attrs[AttrJobType].AsString()).To(Equal("twitter"))
+		Expect(attrs[AttrJobStatus].AsString()).To(Equal("done"))
+		Expect(attrs[AttrJobPolls].AsInt64()).To(Equal(int64(2)))
+		Expect(attrs[AttrDocumentCount].AsInt64()).To(Equal(int64(2)))
+
+		var transitions []string
+		for _, event := range parent.Events() {
+			Expect(event.Name).To(Equal(jobStatusEvent))
+			for _, kv := range event.Attributes {
+				if kv.Key == AttrJobStatus {
+					transitions = append(transitions, kv.Value.AsString())
+				}
+			}
+		}
+		Expect(transitions).To(Equal([]string{"in progress", "done"}))
+
+		Expect(traceparents).To(HaveLen(4))
+		for i, traceparent := range traceparents {
+			Expect(traceparent).To(Equal("00-" + parent.SpanContext().TraceID().String() + "-" +
+				spans[i].SpanContext().SpanID().String() + "-01"))
+		}
+	})
+
+	It("should start the client span from the span of the context", func() {
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		client, err := NewClientWithOptions(server.URL, "test-token", Tracing(provider))
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, caller := provider.Tracer("caller").Start(context.Background(), "caller")
+		_, err = client.SearchTwitterAsyncContext(ctx, "golang")
+		Expect(err).NotTo(HaveOccurred())
+		caller.End()
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(3))
+		Expect(spans[1].Name()).To(Equal("Client.SearchTwitterAsync"))
+		Expect(spans[1].Parent().SpanID()).To(Equal(caller.SpanContext().SpanID()))
+		Expect(spans[1].SpanContext().TraceID()).To(Equal(caller.SpanContext().TraceID()))
+		Expect(traceparents).To(HaveExactElements(HavePrefix("00-" + caller.SpanContext().TraceID().String())))
+	})
+
+	It("should record failed requests as errors", func() {
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		client, err := NewClientWithOptions(server.URL, "test-token", Tracing(provider))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.GetJobStatus("unknown")
+		Expect(err).To(HaveOccurred())
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Status().Description).To(Equal("404 Not Found"))
+		Expect(spans[1].Name()).To(Equal("Client.GetJobStatus"))
+		Expect(spans[1].Events()).NotTo(BeEmpty())
+	})
+
+	It("should not trace or propagate without the option", func() {
+		client, err := NewClientWithOptions(server.URL, "test-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.SearchTwitterAsync("golang")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(traceparents).To(Equal([]string{""}))
+	})
+})
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// DefaultDialTimeout is the timeout for establishing a connection, as in http.DefaultTransport
+const DefaultDialTimeout = 30 * time.Second
+
+// CACertFile adds the PEM encoded CA certificates in path to the system roots trusted for the server certificate
+func CACertFile(path string) Option {
+	return func(o *Options) error {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read CA certificates: %w", err)
+		}
+		if o.RootCAs == nil {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			o.RootCAs = pool
+		}
+		if !o.RootCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no CA certificates found in %s", path)
+		}
+		return nil
+	}
+}
+
+// ClientCertificate loads a PEM encoded certificate and key presented to the server for mutual TLS
+func ClientCertificate(certFile string, keyFile string) Option {
+	return func(o *Options) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		o.Certificates = append(o.Certificates, cert)
+		return nil
+	}
+}
+
+// MinTLSVersion sets the minimum TLS version, e.g. tls.VersionTLS13. The default is TLS 1.2.
+func MinTLSVersion(version uint16) Option {
+	return func(o *Options) error {
+		if version < tls.VersionTLS10 || version > tls.VersionTLS13 {
+			return fmt.Errorf("unsupported TLS version 0x%04x", version)
+		}
+		o.MinTLSVersion = version
+		return nil
+	}
+}
+
+// Proxy sends all requests through the proxy at rawURL, with the scheme http, https, socks5 or socks5h.
+// By default the proxy is taken from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+func Proxy(rawURL string) Option {
+	return func(o *Options) error {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return fmt.Errorf("unsupported proxy scheme %q, must be http, https, socks5 or socks5h", u.Scheme)
+		}
+		if u.Host == "" {
+			return fmt.Errorf("proxy URL %q has no host", rawURL)
+		}
+		o.ProxyURL = u
+		return nil
+	}
+}
+
+// DialTimeout sets the timeout for establishing a connection. The default is 30 seconds.
+func DialTimeout(timeout time.Duration) Option {
+	return func(o *Options) error {
+		o.DialTimeout = timeout
+		return nil
+	}
+}
+
+// HostDialTimeout sets the timeout for establishing a connection to host, overriding DialTimeout.
+// The host is matched without its port, against the host of the request URL and then the dialed host, so that
+// it also applies to connections made through a proxy. A connection to an HTTP proxy may be reused for requests
+// to other hosts, it keeps the timeout of the request it was dialed for.
+func HostDialTimeout(host string, timeout time.Duration) Option {
+	return func(o *Options) error {
+		if o.HostDialTimeouts == nil {
+			o.HostDialTimeouts = make(map[string]time.Duration)
+		}
+		o.HostDialTimeouts[host] = timeout
+		return nil
+	}
+}
+
+// configureTransport applies the TLS, proxy and dial options to t
+func (o *Options) configureTransport(t *http.Transport) {
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	t.TLSClientConfig.InsecureSkipVerify = o.ignoreTLSCert
+	t.TLSClientConfig.RootCAs = o.RootCAs
+	t.TLSClientConfig.Certificates = o.Certificates
+	t.TLSClientConfig.MinVersion = o.MinTLSVersion
+
+	if o.ProxyURL != nil {
+		t.Proxy = http.ProxyURL(o.ProxyURL)
+	}
+	t.DialContext = dialContext(o.DialTimeout, o.HostDialTimeouts)
+}
+
+// dialContext dials with the timeout of the request's host, of the dialed host, or the default timeout
+func dialContext(timeout time.Duration, hostTimeouts map[string]time.Duration) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			if hostTimeout, ok := hostTimeouts[host]; ok {
+				dialer.Timeout = hostTimeout
+			}
+		}
+		// With a proxy the dialed host is the proxy's, the request's host is passed along by Client.do
+		if host, _ := ctx.Value(targetHostKey).(string); host != "" {
+			if hostTimeout, ok := hostTimeouts[host]; ok {
+				dialer.Timeout = hostTimeout
+			}
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
@@ -0,0 +1,189 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Transport options", func() {
+	var (
+		dir    string
+		status http.HandlerFunc
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		status = func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"status": "done"}`))
+		}
+	})
+
+	// writeServerCA writes the certificate of a TLS test server as a CA bundle
+	writeServerCA := func(server *httptest.Server) string {
+		path := filepath.Join(dir, "ca.pem")
+		Expect(os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600)).To(Succeed())
+		return path
+	}
+
+	It("should trust a custom CA bundle", func() {
+		server := httptest.NewTLSServer(status)
+		defer server.Close()
+
+		client, err := NewClientWithOptions(server.URL, "")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.GetJobStatus("job-1")
+		Expect(err).To(MatchError(ContainSubstring("certificate")))
+
+		client, err = NewClientWithOptions(server.URL, "", CACertFile(writeServerCA(server)))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.GetJobStatus("job-1")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject files without CA certificates", func() {
+		path := filepath.Join(dir, "empty.pem")
+		Expect(os.WriteFile(path, []byte("not a certificate"), 0600)).To(Succeed())
+
+		_, err := NewClientWithOptions("https://example.com", "", CACertFile(path))
+		Expect(err).To(MatchError(ContainSubstring("no CA certificates found")))
+	})
+
+	It("should present a client certificate for mutual TLS", func() {
+		certFile, keyFile, clientCert := writeClientCertificate(dir)
+		clientCAs := x509.NewCertPool()
+		clientCAs.AddCert(clientCert)
+
+		server := httptest.NewUnstartedServer(status)
+		server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+		server.StartTLS()
+		defer server.Close()
+
+		client, err := NewClientWithOptions(server.URL, "", CACertFile(writeServerCA(server)))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.GetJobStatus("job-1")
+		Expect(err).To(HaveOccurred())
+
+		client, err = NewClientWithConfig(&config.Config{
+			BaseUrl:             server.URL,
+			Timeout:             time.Minute,
+			MaxConnsPerHost:     1,
+			MaxIdleConnsPerHost: 1,
+			MaxIdleConns:        1,
+			CACertFile:          writeServerCA(server),
+			ClientCertFile:      certFile,
+			ClientKeyFile:       keyFile,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.GetJobStatus("job-1")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should enforce the minimum TLS version", func() {
+		server := httptest.NewUnstartedServer(status)
+		server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+		server.StartTLS()
+		defer server.Close()
+
+		client, err := NewClientWithOptions(server.URL, "", CACertFile(writeServerCA(server)), MinTLSVersion(tls.VersionTLS13))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.GetJobStatus("job-1")
+		Expect(err).To(MatchError(ContainSubstring("protocol version")))
+
+		_, err = NewClientWithOptions(server.URL, "", MinTLSVersion(0x0200))
+		Expect(err).To(MatchError(ContainSubstring("unsupported TLS version")))
+	})
+
+	It("should send requests through the proxy", func() {
+		var proxied []string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied = append(proxied, r.URL.String())
+			status(w, r)
+		}))
+		defer proxy.Close()
+
+		client, err := NewClientWithOptions("http://gopher.invalid/api", "", Proxy(proxy.URL))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.GetJobStatus("job-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(proxied).To(Equal([]string{"http://gopher.invalid/api/v1/search/live/status/job-1"}))
+	})
+
+	It("should apply the dial timeout of the requested host to connections through the proxy", func() {
+		proxy := httptest.NewServer(http.HandlerFunc(status))
+		defer proxy.Close()
+
+		client, err := NewClientWithOptions("http://gopher.invalid/api", "", Proxy(proxy.URL),
+			HostDialTimeout("gopher.invalid", time.Nanosecond))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.GetJobStatus("job-1")
+		Expect(err).To(MatchError(ContainSubstring("i/o timeout")))
+
+		client, err = NewClientWithOptions("http://gopher.invalid/api", "", Proxy(proxy.URL),
+			HostDialTimeout("other.invalid", time.Nanosecond))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.GetJobStatus("job-1")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject unsupported proxies", func() {
+		_, err := NewClientWithOptions("https://example.com", "", Proxy("ftp://proxy:21"))
+		Expect(err).To(MatchError(ContainSubstring(`unsupported proxy scheme "ftp"`)))
+
+		_, err = NewClientWithOptions("https://example.com", "", Proxy("socks5://proxy:1080"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should configure dial timeouts per host", func() {
+		opts, err := NewOptions(DialTimeout(5*time.Second), HostDialTimeout("slow.example.com", time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(opts.DialTimeout).To(Equal(5 * time.Second))
+		Expect(opts.HostDialTimeouts).To(Equal(map[string]time.Duration{"slow.example.com": time.Minute}))
+
+		_, err = NewOptions(HostDialTimeout("slow.example.com", 0))
+		Expect(err).To(MatchError(ContainSubstring("dial timeout of slow.example.com must be positive")))
+	})
+})
+
+// writeClientCertificate writes a self-signed client certificate and its key, returning their paths and the certificate
+func writeClientCertificate(dir string) (string, string, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gopher-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	Expect(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)).To(Succeed())
+	Expect(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)).To(Succeed())
+	return certFile, keyFile, cert
+}
@@ -1,57 +1,60 @@
 package client
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 
     "github.com/masa-finance/tee-worker/v2/api/args/twitter"
-    "github.com/masa-finance/tee-worker/v2/api/params"
     "github.com/masa-finance/tee-worker/v2/api/types"
 )
 
 // SearchTwitterWithArgsAsync searches Twitter with custom arguments and returns a job ID
 func (c *Client) SearchTwitterWithArgsAsync(args twitter.SearchArguments) (*types.ResultResponse, error) {
-	body, err := json.Marshal(params.Twitter{
-		JobType: types.TwitterJob,
-		Args:    &args,
+	return c.SearchTwitterWithArgsAsyncContext(context.Background(), args)
+}
+
+// SearchTwitterWithArgsAsyncContext is like SearchTwitterWithArgsAsync but with a context for cancellation and the parent trace span
+func (c *Client) SearchTwitterWithArgsAsyncContext(ctx context.Context, args twitter.SearchArguments) (*types.ResultResponse, error) {
+	return traced(ctx, c, "SearchTwitterWithArgsAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.TwitterJob, args))
 	})
-	if err != nil {
-		return nil, err
-	}
-	return c.doRequest(c.BaseURL+jobEndpoint, body)
 }
 
 // SearchTwitterAsync performs a Twitter search job and returns a job ID
 func (c *Client) SearchTwitterAsync(query string) (*types.ResultResponse, error) {
+	return c.SearchTwitterAsyncContext(context.Background(), query)
+}
+
+// SearchTwitterAsyncContext is like SearchTwitterAsync but with a context for cancellation and the parent trace span
+func (c *Client) SearchTwitterAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error) {
 	args := twitter.NewSearchArguments()
 	args.Query = query
-	res, err := c.SearchTwitterWithArgsAsync(args)
-	if err != nil {
-		return nil, err
-	}
-	return res, nil
+	return traced(ctx, c, "SearchTwitterAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.TwitterJob, args))
+	})
 }
 
 // SearchTwitter performs a Twitter search and waits for completion, returning results directly
 func (c *Client) SearchTwitter(query string) ([]types.Document, error) {
-	resp, err := c.SearchTwitterAsync(query)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
+	return c.SearchTwitterContext(context.Background(), query)
+}
+
+// SearchTwitterContext is like SearchTwitter but with a context for cancellation and the parent trace span
+func (c *Client) SearchTwitterContext(ctx context.Context, query string) ([]types.Document, error) {
+	args := twitter.NewSearchArguments()
+	args.Query = query
+	return traced(ctx, c, "SearchTwitter", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.TwitterJob, args))
+	})
 }
 
 // SearchTwitterWithArgs searches Twitter with custom arguments and waits for completion, returning results directly
 func (c *Client) SearchTwitterWithArgs(args twitter.SearchArguments) ([]types.Document, error) {
-	resp, err := c.SearchTwitterWithArgsAsync(args)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
+	return c.SearchTwitterWithArgsContext(context.Background(), args)
+}
+
+// SearchTwitterWithArgsContext is like SearchTwitterWithArgs but with a context for cancellation and the parent trace span
+func (c *Client) SearchTwitterWithArgsContext(ctx context.Context, args twitter.SearchArguments) ([]types.Document, error) {
+	return traced(ctx, c, "SearchTwitterWithArgs", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.TwitterJob, args))
+	})
 }
@@ -1,57 +1,60 @@
 package client
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 
     "github.com/masa-finance/tee-worker/v2/api/args/web"
-    "github.com/masa-finance/tee-worker/v2/api/params"
     "github.com/masa-finance/tee-worker/v2/api/types"
 )
 
 // ScrapeWebWithArgsAsync scrapes a web scraper with custom arguments and returns a job ID
 func (c *Client) ScrapeWebWithArgsAsync(args web.ScraperArguments) (*types.ResultResponse, error) {
-	body, err := json.Marshal(params.Web{
-		JobType: types.WebJob,
-		Args:    &args,
+	return c.ScrapeWebWithArgsAsyncContext(context.Background(), args)
+}
+
+// ScrapeWebWithArgsAsyncContext is like ScrapeWebWithArgsAsync but with a context for cancellation and the parent trace span
+func (c *Client) ScrapeWebWithArgsAsyncContext(ctx context.Context, args web.ScraperArguments) (*types.ResultResponse, error) {
+	return traced(ctx, c, "ScrapeWebWithArgsAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.WebJob, args))
 	})
-	if err != nil {
-		return nil, err
-	}
-	return c.doRequest(c.BaseURL+jobEndpoint, body)
 }
 
 // ScrapeWebAsync performs a web scraping job using the provided URL and returns a job ID
 func (c *Client) ScrapeWebAsync(url string) (*types.ResultResponse, error) {
+	return c.ScrapeWebAsyncContext(context.Background(), url)
+}
+
+// ScrapeWebAsyncContext is like ScrapeWebAsync but with a context for cancellation and the parent trace span
+func (c *Client) ScrapeWebAsyncContext(ctx context.Context, url string) (*types.ResultResponse, error) {
 	args := web.NewScraperArguments()
 	args.URL = url
-	res, err := c.ScrapeWebWithArgsAsync(args)
-	if err != nil {
-		return nil, err
-	}
-	return res, nil
+	return traced(ctx, c, "ScrapeWebAsync", func(ctx context.Context) (*types.ResultResponse, error) {
+		return c.submitJob(ctx, newJobRequest(types.WebJob, args))
+	})
 }
 
 // ScrapeWeb performs a web scraping job and waits for completion, returning results directly
 func (c *Client) ScrapeWeb(url string) ([]types.Document, error) {
-	resp, err := c.ScrapeWebAsync(url)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
+	return c.ScrapeWebContext(context.Background(), url)
+}
+
+// ScrapeWebContext is like ScrapeWeb but with a context for cancellation and the parent trace span
+func (c *Client) ScrapeWebContext(ctx context.Context, url string) ([]types.Document, error) {
+	args := web.NewScraperArguments()
+	args.URL = url
+	return traced(ctx, c, "ScrapeWeb", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.WebJob, args))
+	})
 }
 
 // ScrapeWebWithArgs scrapes a web scraper with custom arguments and waits for completion, returning results directly
 func (c *Client) ScrapeWebWithArgs(args web.ScraperArguments) ([]types.Document, error) {
-	resp, err := c.ScrapeWebWithArgsAsync(args)
-	if err != nil {
-		return nil, err
-	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("job submission failed: %s", resp.Error)
-	}
-	return c.WaitForJobCompletion(resp.UUID)
+	return c.ScrapeWebWithArgsContext(context.Background(), args)
+}
+
+// ScrapeWebWithArgsContext is like ScrapeWebWithArgs but with a context for cancellation and the parent trace span
+func (c *Client) ScrapeWebWithArgsContext(ctx context.Context, args web.ScraperArguments) ([]types.Document, error) {
+	return traced(ctx, c, "ScrapeWebWithArgs", func(ctx context.Context) ([]types.Document, error) {
+		return c.runJob(ctx, newJobRequest(types.WebJob, args))
+	})
 }
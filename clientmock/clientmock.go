@@ -0,0 +1,35 @@
+// Package clientmock provides mocks of the interfaces of the client package, generated from client/interfaces.go.
+//
+//	scraper := &clientmock.WebScraper{
+//		ScrapeWebFunc: func(url string) ([]types.Document, error) {
+//			return []types.Document{{Content: "page"}}, nil
+//		},
+//	}
+//	docs, err := scraper.ScrapeWeb("https://example.com")
+//	scraper.Calls("ScrapeWeb") // [][]any{{"https://example.com"}}
+package clientmock
+
+import "sync"
+
+//go:generate go run ./internal/mockgen -source ../client/interfaces.go -out mocks.go
+
+// recorder records the arguments of the calls of a mock
+type recorder struct {
+	mu    sync.Mutex
+	calls map[string][][]any
+}
+
+func (r *recorder) record(method string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.calls == nil {
+		r.calls = make(map[string][][]any)
+	}
+	r.calls[method] = append(r.calls[method], args)
+}
+
+func (r *recorder) get(method string) [][]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]any(nil), r.calls[method]...)
+}
@@ -0,0 +1,13 @@
+package clientmock_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestClientmock(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Clientmock Suite")
+}
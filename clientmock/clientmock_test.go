@@ -0,0 +1,94 @@
+package clientmock_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/gopher-lab/gopher-client/client"
+	"github.com/gopher-lab/gopher-client/clientmock"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// searchAll is downstream code depending on interfaces instead of *client.Client
+func searchAll(twitter client.TwitterSearcher, web client.WebScraper, query string) (int, error) {
+	tweets, err := twitter.SearchTwitter(query)
+	if err != nil {
+		return 0, err
+	}
+	pages, err := web.ScrapeWeb("https://example.com/search?q=" + query)
+	if err != nil {
+		return 0, err
+	}
+	return len(tweets) + len(pages), nil
+}
+
+var _ = Describe("Mocks", func() {
+	It("should call the functions and record the arguments", func() {
+		twitter := &clientmock.TwitterSearcher{
+			SearchTwitterFunc: func(query string) ([]types.Document, error) {
+				return []types.Document{{Id: "1"}, {Id: "2"}}, nil
+			},
+		}
+		web := &clientmock.WebScraper{
+			ScrapeWebFunc: func(url string) ([]types.Document, error) {
+				return []types.Document{{Id: "3"}}, nil
+			},
+		}
+
+		count, err := searchAll(twitter, web, "golang")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(3))
+		Expect(twitter.Calls("SearchTwitter")).To(Equal([][]any{{"golang"}}))
+		Expect(web.Calls("ScrapeWeb")).To(Equal([][]any{{"https://example.com/search?q=golang"}}))
+		Expect(twitter.Calls("SearchTwitterAsync")).To(BeEmpty())
+	})
+
+	It("should mock the context variants", func() {
+		twitter := &clientmock.TwitterSearcher{
+			SearchTwitterAsyncContextFunc: func(ctx context.Context, query string) (*types.ResultResponse, error) {
+				return &types.ResultResponse{UUID: "job-1"}, nil
+			},
+		}
+
+		ctx := context.Background()
+		resp, err := twitter.SearchTwitterAsyncContext(ctx, "golang")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.UUID).To(Equal("job-1"))
+		Expect(twitter.Calls("SearchTwitterAsyncContext")).To(Equal([][]any{{ctx, "golang"}}))
+	})
+
+	It("should return the errors of the functions", func() {
+		tracker := &clientmock.JobTracker{
+			GetResultFunc: func(jobID string, receiver any) error {
+				return errors.New("not found")
+			},
+		}
+
+		Expect(tracker.GetResult("job-1", nil)).To(MatchError("not found"))
+	})
+
+	It("should panic on calls without a function", func() {
+		metrics := &clientmock.MetricsReader{}
+
+		Expect(func() { _, _ = metrics.GetAllMetrics(true) }).To(PanicWith("clientmock: MetricsReader.GetAllMetrics called without GetAllMetricsFunc"))
+		Expect(metrics.Calls("GetAllMetrics")).To(Equal([][]any{{true}}))
+	})
+
+	It("should be up to date with the interfaces", func() {
+		out := filepath.Join(GinkgoT().TempDir(), "mocks.go")
+		cmd := exec.Command("go", "run", "./internal/mockgen", "-source", "../client/interfaces.go", "-out", out)
+		output, err := cmd.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(output))
+
+		generated, err := os.ReadFile(out)
+		Expect(err).NotTo(HaveOccurred())
+		current, err := os.ReadFile("mocks.go")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(current)).To(Equal(string(generated)), "run go generate ./clientmock")
+	})
+})
@@ -0,0 +1,223 @@
+// Command mockgen generates the mocks of the clientmock package from the interfaces declared in a source file
+// of the client package. Every mock has a ...Func field per method and records the arguments of its calls.
+//
+//	go run ./internal/mockgen -source ../client/interfaces.go -out mocks.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const clientImport = "github.com/gopher-lab/gopher-client/client"
+
+func main() {
+	source := flag.String("source", "", "Go file declaring the interfaces")
+	out := flag.String("out", "", "output file")
+	pkg := flag.String("package", "clientmock", "package of the generated file")
+	flag.Parse()
+	if *source == "" || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	code, err := generate(*source, *pkg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate returns the formatted source of the mocks of the interfaces in the file source
+func generate(source string, pkg string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, source, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by mockgen from %s. DO NOT EDIT.\n\n", filepath.Base(source))
+	fmt.Fprintf(&buf, "package %s\n\nimport (\n\t%q\n", pkg, clientImport)
+	for _, spec := range file.Imports {
+		if spec.Name != nil {
+			fmt.Fprintf(&buf, "\t%s %s\n", spec.Name.Name, spec.Path.Value)
+		} else {
+			fmt.Fprintf(&buf, "\t%s\n", spec.Path.Value)
+		}
+	}
+	buf.WriteString(")\n")
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			iface, ok := typeSpec.Type.(*ast.InterfaceType)
+			if !ok || !typeSpec.Name.IsExported() {
+				continue
+			}
+			if err := writeMock(&buf, fset, typeSpec.Name.Name, iface); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	code, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w\n%s", err, buf.Bytes())
+	}
+	return code, nil
+}
+
+// method is a method of an interface with its signature printed for the mock
+type method struct {
+	name    string
+	params  string // e.g. "url string, opts ...Option"
+	args    string // e.g. "url, opts..."
+	record  string // e.g. "url, opts"
+	results string // e.g. "([]types.Document, error)"
+}
+
+func writeMock(buf *bytes.Buffer, fset *token.FileSet, name string, iface *ast.InterfaceType) error {
+	var methods []method
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) != 1 {
+			return fmt.Errorf("%s: embedded interfaces are not supported", name)
+		}
+		m, err := newMethod(fset, field.Names[0].Name, fn)
+		if err != nil {
+			return err
+		}
+		methods = append(methods, m)
+	}
+
+	fmt.Fprintf(buf, "\n// %s is a mock of client.%s. Each method calls the function of its Func field, which must be set.\n", name, name)
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, m := range methods {
+		fmt.Fprintf(buf, "\t%sFunc func(%s) %s\n", m.name, m.params, m.results)
+	}
+	buf.WriteString("\n\tcalls recorder\n}\n\n")
+	fmt.Fprintf(buf, "var _ client.%s = (*%s)(nil)\n", name, name)
+
+	for _, m := range methods {
+		fmt.Fprintf(buf, "\n// %s calls %sFunc\n", m.name, m.name)
+		fmt.Fprintf(buf, "func (m *%s) %s(%s) %s {\n", name, m.name, m.params, m.results)
+		fmt.Fprintf(buf, "\tm.calls.record(%q%s)\n", m.name, prefixComma(m.record))
+		fmt.Fprintf(buf, "\tif m.%sFunc == nil {\n\t\tpanic(\"clientmock: %s.%s called without %sFunc\")\n\t}\n", m.name, name, m.name, m.name)
+		if m.results == "" {
+			fmt.Fprintf(buf, "\tm.%sFunc(%s)\n}\n", m.name, m.args)
+		} else {
+			fmt.Fprintf(buf, "\treturn m.%sFunc(%s)\n}\n", m.name, m.args)
+		}
+	}
+
+	fmt.Fprintf(buf, "\n// Calls returns the arguments of every call of the named method, in order\n")
+	fmt.Fprintf(buf, "func (m *%s) Calls(method string) [][]any {\n\treturn m.calls.get(method)\n}\n", name)
+	return nil
+}
+
+func newMethod(fset *token.FileSet, name string, fn *ast.FuncType) (method, error) {
+	m := method{name: name}
+	var params, args, record []string
+	i := 0
+	for _, field := range fn.Params.List {
+		typ, err := printType(fset, field.Type)
+		if err != nil {
+			return m, err
+		}
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{{Name: "_"}}
+		}
+		for _, ident := range names {
+			param := ident.Name
+			if param == "_" {
+				param = fmt.Sprintf("p%d", i)
+			}
+			i++
+			params = append(params, param+" "+typ)
+			record = append(record, param)
+			if _, variadic := field.Type.(*ast.Ellipsis); variadic {
+				param += "..."
+			}
+			args = append(args, param)
+		}
+	}
+	m.params = strings.Join(params, ", ")
+	m.args = strings.Join(args, ", ")
+	m.record = strings.Join(record, ", ")
+
+	if fn.Results != nil {
+		var results []string
+		for _, field := range fn.Results.List {
+			typ, err := printType(fset, field.Type)
+			if err != nil {
+				return m, err
+			}
+			for range max(len(field.Names), 1) {
+				results = append(results, typ)
+			}
+		}
+		m.results = strings.Join(results, ", ")
+		if len(results) > 1 {
+			m.results = "(" + m.results + ")"
+		}
+	}
+	return m, nil
+}
+
+// printType prints a type expression, qualifying the types declared in the client package
+func printType(fset *token.FileSet, expr ast.Expr) (string, error) {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, qualify(expr)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// qualify returns expr with the unqualified, non-predeclared type names prefixed with the client package
+func qualify(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if types.Universe.Lookup(e.Name) != nil {
+			return e
+		}
+		return &ast.SelectorExpr{X: ast.NewIdent("client"), Sel: ast.NewIdent(e.Name)}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(e.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: qualify(e.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(e.Key), Value: qualify(e.Value)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: qualify(e.Elt)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: e.Dir, Value: qualify(e.Value)}
+	case *ast.IndexExpr:
+		return &ast.IndexExpr{X: qualify(e.X), Index: qualify(e.Index)}
+	}
+	return expr
+}
+
+func prefixComma(s string) string {
+	if s == "" {
+		return ""
+	}
+	return ", " + s
+}
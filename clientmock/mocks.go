@@ -0,0 +1,1112 @@
+// Code generated by mockgen from interfaces.go. DO NOT EDIT.
+
+package clientmock
+
+import (
+	"context"
+	"github.com/gopher-lab/gopher-client/client"
+	gophertypes "github.com/gopher-lab/gopher-client/types"
+	"github.com/masa-finance/tee-worker/v2/api/args/linkedin"
+	"github.com/masa-finance/tee-worker/v2/api/args/reddit"
+	"github.com/masa-finance/tee-worker/v2/api/args/tiktok"
+	"github.com/masa-finance/tee-worker/v2/api/args/twitter"
+	"github.com/masa-finance/tee-worker/v2/api/args/web"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+)
+
+// WebScraper is a mock of client.WebScraper. Each method calls the function of its Func field, which must be set.
+type WebScraper struct {
+	ScrapeWebFunc                     func(url string) ([]types.Document, error)
+	ScrapeWebContextFunc              func(ctx context.Context, url string) ([]types.Document, error)
+	ScrapeWebAsyncFunc                func(url string) (*types.ResultResponse, error)
+	ScrapeWebAsyncContextFunc         func(ctx context.Context, url string) (*types.ResultResponse, error)
+	ScrapeWebWithArgsFunc             func(args web.ScraperArguments) ([]types.Document, error)
+	ScrapeWebWithArgsContextFunc      func(ctx context.Context, args web.ScraperArguments) ([]types.Document, error)
+	ScrapeWebWithArgsAsyncFunc        func(args web.ScraperArguments) (*types.ResultResponse, error)
+	ScrapeWebWithArgsAsyncContextFunc func(ctx context.Context, args web.ScraperArguments) (*types.ResultResponse, error)
+
+	calls recorder
+}
+
+var _ client.WebScraper = (*WebScraper)(nil)
+
+// ScrapeWeb calls ScrapeWebFunc
+func (m *WebScraper) ScrapeWeb(url string) ([]types.Document, error) {
+	m.calls.record("ScrapeWeb", url)
+	if m.ScrapeWebFunc == nil {
+		panic("clientmock: WebScraper.ScrapeWeb called without ScrapeWebFunc")
+	}
+	return m.ScrapeWebFunc(url)
+}
+
+// ScrapeWebContext calls ScrapeWebContextFunc
+func (m *WebScraper) ScrapeWebContext(ctx context.Context, url string) ([]types.Document, error) {
+	m.calls.record("ScrapeWebContext", ctx, url)
+	if m.ScrapeWebContextFunc == nil {
+		panic("clientmock: WebScraper.ScrapeWebContext called without ScrapeWebContextFunc")
+	}
+	return m.ScrapeWebContextFunc(ctx, url)
+}
+
+// ScrapeWebAsync calls ScrapeWebAsyncFunc
+func (m *WebScraper) ScrapeWebAsync(url string) (*types.ResultResponse, error) {
+	m.calls.record("ScrapeWebAsync", url)
+	if m.ScrapeWebAsyncFunc == nil {
+		panic("clientmock: WebScraper.ScrapeWebAsync called without ScrapeWebAsyncFunc")
+	}
+	return m.ScrapeWebAsyncFunc(url)
+}
+
+// ScrapeWebAsyncContext calls ScrapeWebAsyncContextFunc
+func (m *WebScraper) ScrapeWebAsyncContext(ctx context.Context, url string) (*types.ResultResponse, error) {
+	m.calls.record("ScrapeWebAsyncContext", ctx, url)
+	if m.ScrapeWebAsyncContextFunc == nil {
+		panic("clientmock: WebScraper.ScrapeWebAsyncContext called without ScrapeWebAsyncContextFunc")
+	}
+	return m.ScrapeWebAsyncContextFunc(ctx, url)
+}
+
+// ScrapeWebWithArgs calls ScrapeWebWithArgsFunc
+func (m *WebScraper) ScrapeWebWithArgs(args web.ScraperArguments) ([]types.Document, error) {
+	m.calls.record("ScrapeWebWithArgs", args)
+	if m.ScrapeWebWithArgsFunc == nil {
+		panic("clientmock: WebScraper.ScrapeWebWithArgs called without ScrapeWebWithArgsFunc")
+	}
+	return m.ScrapeWebWithArgsFunc(args)
+}
+
+// ScrapeWebWithArgsContext calls ScrapeWebWithArgsContextFunc
+func (m *WebScraper) ScrapeWebWithArgsContext(ctx context.Context, args web.ScraperArguments) ([]types.Document, error) {
+	m.calls.record("ScrapeWebWithArgsContext", ctx, args)
+	if m.ScrapeWebWithArgsContextFunc == nil {
+		panic("clientmock: WebScraper.ScrapeWebWithArgsContext called without ScrapeWebWithArgsContextFunc")
+	}
+	return m.ScrapeWebWithArgsContextFunc(ctx, args)
+}
+
+// ScrapeWebWithArgsAsync calls ScrapeWebWithArgsAsyncFunc
+func (m *WebScraper) ScrapeWebWithArgsAsync(args web.ScraperArguments) (*types.ResultResponse, error) {
+	m.calls.record("ScrapeWebWithArgsAsync", args)
+	if m.ScrapeWebWithArgsAsyncFunc == nil {
+		panic("clientmock: WebScraper.ScrapeWebWithArgsAsync called without ScrapeWebWithArgsAsyncFunc")
+	}
+	return m.ScrapeWebWithArgsAsyncFunc(args)
+}
+
+// ScrapeWebWithArgsAsyncContext calls ScrapeWebWithArgsAsyncContextFunc
+func (m *WebScraper) ScrapeWebWithArgsAsyncContext(ctx context.Context, args web.ScraperArguments) (*types.ResultResponse, error) {
+	m.calls.record("ScrapeWebWithArgsAsyncContext", ctx, args)
+	if m.ScrapeWebWithArgsAsyncContextFunc == nil {
+		panic("clientmock: WebScraper.ScrapeWebWithArgsAsyncContext called without ScrapeWebWithArgsAsyncContextFunc")
+	}
+	return m.ScrapeWebWithArgsAsyncContextFunc(ctx, args)
+}
+
+// Calls returns the arguments of every call of the named method, in order
+func (m *WebScraper) Calls(method string) [][]any {
+	return m.calls.get(method)
+}
+
+// TwitterSearcher is a mock of client.TwitterSearcher. Each method calls the function of its Func field, which must be set.
+type TwitterSearcher struct {
+	SearchTwitterFunc                     func(query string) ([]types.Document, error)
+	SearchTwitterContextFunc              func(ctx context.Context, query string) ([]types.Document, error)
+	SearchTwitterAsyncFunc                func(query string) (*types.ResultResponse, error)
+	SearchTwitterAsyncContextFunc         func(ctx context.Context, query string) (*types.ResultResponse, error)
+	SearchTwitterWithArgsFunc             func(args twitter.SearchArguments) ([]types.Document, error)
+	SearchTwitterWithArgsContextFunc      func(ctx context.Context, args twitter.SearchArguments) ([]types.Document, error)
+	SearchTwitterWithArgsAsyncFunc        func(args twitter.SearchArguments) (*types.ResultResponse, error)
+	SearchTwitterWithArgsAsyncContextFunc func(ctx context.Context, args twitter.SearchArguments) (*types.ResultResponse, error)
+
+	calls recorder
+}
+
+var _ client.TwitterSearcher = (*TwitterSearcher)(nil)
+
+// SearchTwitter calls SearchTwitterFunc
+func (m *TwitterSearcher) SearchTwitter(query string) ([]types.Document, error) {
+	m.calls.record("SearchTwitter", query)
+	if m.SearchTwitterFunc == nil {
+		panic("clientmock: TwitterSearcher.SearchTwitter called without SearchTwitterFunc")
+	}
+	return m.SearchTwitterFunc(query)
+}
+
+// SearchTwitterContext calls SearchTwitterContextFunc
+func (m *TwitterSearcher) SearchTwitterContext(ctx context.Context, query string) ([]types.Document, error) {
+	m.calls.record("SearchTwitterContext", ctx, query)
+	if m.SearchTwitterContextFunc == nil {
+		panic("clientmock: TwitterSearcher.SearchTwitterContext called without SearchTwitterContextFunc")
+	}
+	return m.SearchTwitterContextFunc(ctx, query)
+}
+
+// SearchTwitterAsync calls SearchTwitterAsyncFunc
+func (m *TwitterSearcher) SearchTwitterAsync(query string) (*types.ResultResponse, error) {
+	m.calls.record("SearchTwitterAsync", query)
+	if m.SearchTwitterAsyncFunc == nil {
+		panic("clientmock: TwitterSearcher.SearchTwitterAsync called without SearchTwitterAsyncFunc")
+	}
+	return m.SearchTwitterAsyncFunc(query)
+}
+
+// SearchTwitterAsyncContext calls SearchTwitterAsyncContextFunc
+func (m *TwitterSearcher) SearchTwitterAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error) {
+	m.calls.record("SearchTwitterAsyncContext", ctx, query)
+	if m.SearchTwitterAsyncContextFunc == nil {
+		panic("clientmock: TwitterSearcher.SearchTwitterAsyncContext called without SearchTwitterAsyncContextFunc")
+	}
+	return m.SearchTwitterAsyncContextFunc(ctx, query)
+}
+
+// SearchTwitterWithArgs calls SearchTwitterWithArgsFunc
+func (m *TwitterSearcher) SearchTwitterWithArgs(args twitter.SearchArguments) ([]types.Document, error) {
+	m.calls.record("SearchTwitterWithArgs", args)
+	if m.SearchTwitterWithArgsFunc == nil {
+		panic("clientmock: TwitterSearcher.SearchTwitterWithArgs called without SearchTwitterWithArgsFunc")
+	}
+	return m.SearchTwitterWithArgsFunc(args)
+}
+
+// SearchTwitterWithArgsContext calls SearchTwitterWithArgsContextFunc
+func (m *TwitterSearcher) SearchTwitterWithArgsContext(ctx context.Context, args twitter.SearchArguments) ([]types.Document, error) {
+	m.calls.record("SearchTwitterWithArgsContext", ctx, args)
+	if m.SearchTwitterWithArgsContextFunc == nil {
+		panic("clientmock: TwitterSearcher.SearchTwitterWithArgsContext called without SearchTwitterWithArgsContextFunc")
+	}
+	return m.SearchTwitterWithArgsContextFunc(ctx, args)
+}
+
+// SearchTwitterWithArgsAsync calls SearchTwitterWithArgsAsyncFunc
+func (m *TwitterSearcher) SearchTwitterWithArgsAsync(args twitter.SearchArguments) (*types.ResultResponse, error) {
+	m.calls.record("SearchTwitterWithArgsAsync", args)
+	if m.SearchTwitterWithArgsAsyncFunc == nil {
+		panic("clientmock: TwitterSearcher.SearchTwitterWithArgsAsync called without SearchTwitterWithArgsAsyncFunc")
+	}
+	return m.SearchTwitterWithArgsAsyncFunc(args)
+}
+
+// SearchTwitterWithArgsAsyncContext calls SearchTwitterWithArgsAsyncContextFunc
+func (m *TwitterSearcher) SearchTwitterWithArgsAsyncContext(ctx context.Context, args twitter.SearchArguments) (*types.ResultResponse, error) {
+	m.calls.record("SearchTwitterWithArgsAsyncContext", ctx, args)
+	if m.SearchTwitterWithArgsAsyncContextFunc == nil {
+		panic("clientmock: TwitterSearcher.SearchTwitterWithArgsAsyncContext called without SearchTwitterWithArgsAsyncContextFunc")
+	}
+	return m.SearchTwitterWithArgsAsyncContextFunc(ctx, args)
+}
+
+// Calls returns the arguments of every call of the named method, in order
+func (m *TwitterSearcher) Calls(method string) [][]any {
+	return m.calls.get(method)
+}
+
+// RedditSearcher is a mock of client.RedditSearcher. Each method calls the function of its Func field, which must be set.
+type RedditSearcher struct {
+	ScrapeRedditURLFunc                     func(url string) ([]types.Document, error)
+	ScrapeRedditURLContextFunc              func(ctx context.Context, url string) ([]types.Document, error)
+	ScrapeRedditURLAsyncFunc                func(url string) (*types.ResultResponse, error)
+	ScrapeRedditURLAsyncContextFunc         func(ctx context.Context, url string) (*types.ResultResponse, error)
+	SearchRedditPostsFunc                   func(query string) ([]types.Document, error)
+	SearchRedditPostsContextFunc            func(ctx context.Context, query string) ([]types.Document, error)
+	SearchRedditPostsAsyncFunc              func(query string) (*types.ResultResponse, error)
+	SearchRedditPostsAsyncContextFunc       func(ctx context.Context, query string) (*types.ResultResponse, error)
+	SearchRedditUsersFunc                   func(query string) ([]types.Document, error)
+	SearchRedditUsersContextFunc            func(ctx context.Context, query string) ([]types.Document, error)
+	SearchRedditUsersAsyncFunc              func(query string) (*types.ResultResponse, error)
+	SearchRedditUsersAsyncContextFunc       func(ctx context.Context, query string) (*types.ResultResponse, error)
+	SearchRedditCommunitiesFunc             func(query string) ([]types.Document, error)
+	SearchRedditCommunitiesContextFunc      func(ctx context.Context, query string) ([]types.Document, error)
+	SearchRedditCommunitiesAsyncFunc        func(query string) (*types.ResultResponse, error)
+	SearchRedditCommunitiesAsyncContextFunc func(ctx context.Context, query string) (*types.ResultResponse, error)
+	SearchRedditWithArgsFunc                func(args reddit.SearchArguments) ([]types.Document, error)
+	SearchRedditWithArgsContextFunc         func(ctx context.Context, args reddit.SearchArguments) ([]types.Document, error)
+	SearchRedditWithArgsAsyncFunc           func(args reddit.SearchArguments) (*types.ResultResponse, error)
+	SearchRedditWithArgsAsyncContextFunc    func(ctx context.Context, args reddit.SearchArguments) (*types.ResultResponse, error)
+
+	calls recorder
+}
+
+var _ client.RedditSearcher = (*RedditSearcher)(nil)
+
+// ScrapeRedditURL calls ScrapeRedditURLFunc
+func (m *RedditSearcher) ScrapeRedditURL(url string) ([]types.Document, error) {
+	m.calls.record("ScrapeRedditURL", url)
+	if m.ScrapeRedditURLFunc == nil {
+		panic("clientmock: RedditSearcher.ScrapeRedditURL called without ScrapeRedditURLFunc")
+	}
+	return m.ScrapeRedditURLFunc(url)
+}
+
+// ScrapeRedditURLContext calls ScrapeRedditURLContextFunc
+func (m *RedditSearcher) ScrapeRedditURLContext(ctx context.Context, url string) ([]types.Document, error) {
+	m.calls.record("ScrapeRedditURLContext", ctx, url)
+	if m.ScrapeRedditURLContextFunc == nil {
+		panic("clientmock: RedditSearcher.ScrapeRedditURLContext called without ScrapeRedditURLContextFunc")
+	}
+	return m.ScrapeRedditURLContextFunc(ctx, url)
+}
+
+// ScrapeRedditURLAsync calls ScrapeRedditURLAsyncFunc
+func (m *RedditSearcher) ScrapeRedditURLAsync(url string) (*types.ResultResponse, error) {
+	m.calls.record("ScrapeRedditURLAsync", url)
+	if m.ScrapeRedditURLAsyncFunc == nil {
+		panic("clientmock: RedditSearcher.ScrapeRedditURLAsync called without ScrapeRedditURLAsyncFunc")
+	}
+	return m.ScrapeRedditURLAsyncFunc(url)
+}
+
+// ScrapeRedditURLAsyncContext calls ScrapeRedditURLAsyncContextFunc
+func (m *RedditSearcher) ScrapeRedditURLAsyncContext(ctx context.Context, url string) (*types.ResultResponse, error) {
+	m.calls.record("ScrapeRedditURLAsyncContext", ctx, url)
+	if m.ScrapeRedditURLAsyncContextFunc == nil {
+		panic("clientmock: RedditSearcher.ScrapeRedditURLAsyncContext called without ScrapeRedditURLAsyncContextFunc")
+	}
+	return m.ScrapeRedditURLAsyncContextFunc(ctx, url)
+}
+
+// SearchRedditPosts calls SearchRedditPostsFunc
+func (m *RedditSearcher) SearchRedditPosts(query string) ([]types.Document, error) {
+	m.calls.record("SearchRedditPosts", query)
+	if m.SearchRedditPostsFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditPosts called without SearchRedditPostsFunc")
+	}
+	return m.SearchRedditPostsFunc(query)
+}
+
+// SearchRedditPostsContext calls SearchRedditPostsContextFunc
+func (m *RedditSearcher) SearchRedditPostsContext(ctx context.Context, query string) ([]types.Document, error) {
+	m.calls.record("SearchRedditPostsContext", ctx, query)
+	if m.SearchRedditPostsContextFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditPostsContext called without SearchRedditPostsContextFunc")
+	}
+	return m.SearchRedditPostsContextFunc(ctx, query)
+}
+
+// SearchRedditPostsAsync calls SearchRedditPostsAsyncFunc
+func (m *RedditSearcher) SearchRedditPostsAsync(query string) (*types.ResultResponse, error) {
+	m.calls.record("SearchRedditPostsAsync", query)
+	if m.SearchRedditPostsAsyncFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditPostsAsync called without SearchRedditPostsAsyncFunc")
+	}
+	return m.SearchRedditPostsAsyncFunc(query)
+}
+
+// SearchRedditPostsAsyncContext calls SearchRedditPostsAsyncContextFunc
+func (m *RedditSearcher) SearchRedditPostsAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error) {
+	m.calls.record("SearchRedditPostsAsyncContext", ctx, query)
+	if m.SearchRedditPostsAsyncContextFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditPostsAsyncContext called without SearchRedditPostsAsyncContextFunc")
+	}
+	return m.SearchRedditPostsAsyncContextFunc(ctx, query)
+}
+
+// SearchRedditUsers calls SearchRedditUsersFunc
+func (m *RedditSearcher) SearchRedditUsers(query string) ([]types.Document, error) {
+	m.calls.record("SearchRedditUsers", query)
+	if m.SearchRedditUsersFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditUsers called without SearchRedditUsersFunc")
+	}
+	return m.SearchRedditUsersFunc(query)
+}
+
+// SearchRedditUsersContext calls SearchRedditUsersContextFunc
+func (m *RedditSearcher) SearchRedditUsersContext(ctx context.Context, query string) ([]types.Document, error) {
+	m.calls.record("SearchRedditUsersContext", ctx, query)
+	if m.SearchRedditUsersContextFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditUsersContext called without SearchRedditUsersContextFunc")
+	}
+	return m.SearchRedditUsersContextFunc(ctx, query)
+}
+
+// SearchRedditUsersAsync calls SearchRedditUsersAsyncFunc
+func (m *RedditSearcher) SearchRedditUsersAsync(query string) (*types.ResultResponse, error) {
+	m.calls.record("SearchRedditUsersAsync", query)
+	if m.SearchRedditUsersAsyncFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditUsersAsync called without SearchRedditUsersAsyncFunc")
+	}
+	return m.SearchRedditUsersAsyncFunc(query)
+}
+
+// SearchRedditUsersAsyncContext calls SearchRedditUsersAsyncContextFunc
+func (m *RedditSearcher) SearchRedditUsersAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error) {
+	m.calls.record("SearchRedditUsersAsyncContext", ctx, query)
+	if m.SearchRedditUsersAsyncContextFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditUsersAsyncContext called without SearchRedditUsersAsyncContextFunc")
+	}
+	return m.SearchRedditUsersAsyncContextFunc(ctx, query)
+}
+
+// SearchRedditCommunities calls SearchRedditCommunitiesFunc
+func (m *RedditSearcher) SearchRedditCommunities(query string) ([]types.Document, error) {
+	m.calls.record("SearchRedditCommunities", query)
+	if m.SearchRedditCommunitiesFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditCommunities called without SearchRedditCommunitiesFunc")
+	}
+	return m.SearchRedditCommunitiesFunc(query)
+}
+
+// SearchRedditCommunitiesContext calls SearchRedditCommunitiesContextFunc
+func (m *RedditSearcher) SearchRedditCommunitiesContext(ctx context.Context, query string) ([]types.Document, error) {
+	m.calls.record("SearchRedditCommunitiesContext", ctx, query)
+	if m.SearchRedditCommunitiesContextFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditCommunitiesContext called without SearchRedditCommunitiesContextFunc")
+	}
+	return m.SearchRedditCommunitiesContextFunc(ctx, query)
+}
+
+// SearchRedditCommunitiesAsync calls SearchRedditCommunitiesAsyncFunc
+func (m *RedditSearcher) SearchRedditCommunitiesAsync(query string) (*types.ResultResponse, error) {
+	m.calls.record("SearchRedditCommunitiesAsync", query)
+	if m.SearchRedditCommunitiesAsyncFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditCommunitiesAsync called without SearchRedditCommunitiesAsyncFunc")
+	}
+	return m.SearchRedditCommunitiesAsyncFunc(query)
+}
+
+// SearchRedditCommunitiesAsyncContext calls SearchRedditCommunitiesAsyncContextFunc
+func (m *RedditSearcher) SearchRedditCommunitiesAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error) {
+	m.calls.record("SearchRedditCommunitiesAsyncContext", ctx, query)
+	if m.SearchRedditCommunitiesAsyncContextFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditCommunitiesAsyncContext called without SearchRedditCommunitiesAsyncContextFunc")
+	}
+	return m.SearchRedditCommunitiesAsyncContextFunc(ctx, query)
+}
+
+// SearchRedditWithArgs calls SearchRedditWithArgsFunc
+func (m *RedditSearcher) SearchRedditWithArgs(args reddit.SearchArguments) ([]types.Document, error) {
+	m.calls.record("SearchRedditWithArgs", args)
+	if m.SearchRedditWithArgsFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditWithArgs called without SearchRedditWithArgsFunc")
+	}
+	return m.SearchRedditWithArgsFunc(args)
+}
+
+// SearchRedditWithArgsContext calls SearchRedditWithArgsContextFunc
+func (m *RedditSearcher) SearchRedditWithArgsContext(ctx context.Context, args reddit.SearchArguments) ([]types.Document, error) {
+	m.calls.record("SearchRedditWithArgsContext", ctx, args)
+	if m.SearchRedditWithArgsContextFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditWithArgsContext called without SearchRedditWithArgsContextFunc")
+	}
+	return m.SearchRedditWithArgsContextFunc(ctx, args)
+}
+
+// SearchRedditWithArgsAsync calls SearchRedditWithArgsAsyncFunc
+func (m *RedditSearcher) SearchRedditWithArgsAsync(args reddit.SearchArguments) (*types.ResultResponse, error) {
+	m.calls.record("SearchRedditWithArgsAsync", args)
+	if m.SearchRedditWithArgsAsyncFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditWithArgsAsync called without SearchRedditWithArgsAsyncFunc")
+	}
+	return m.SearchRedditWithArgsAsyncFunc(args)
+}
+
+// SearchRedditWithArgsAsyncContext calls SearchRedditWithArgsAsyncContextFunc
+func (m *RedditSearcher) SearchRedditWithArgsAsyncContext(ctx context.Context, args reddit.SearchArguments) (*types.ResultResponse, error) {
+	m.calls.record("SearchRedditWithArgsAsyncContext", ctx, args)
+	if m.SearchRedditWithArgsAsyncContextFunc == nil {
+		panic("clientmock: RedditSearcher.SearchRedditWithArgsAsyncContext called without SearchRedditWithArgsAsyncContextFunc")
+	}
+	return m.SearchRedditWithArgsAsyncContextFunc(ctx, args)
+}
+
+// Calls returns the arguments of every call of the named method, in order
+func (m *RedditSearcher) Calls(method string) [][]any {
+	return m.calls.get(method)
+}
+
+// TikTokClient is a mock of client.TikTokClient. Each method calls the function of its Func field, which must be set.
+type TikTokClient struct {
+	TranscribeTikTokFunc                         func(url string) ([]types.Document, error)
+	TranscribeTikTokContextFunc                  func(ctx context.Context, url string) ([]types.Document, error)
+	TranscribeTikTokAsyncFunc                    func(url string) (*types.ResultResponse, error)
+	TranscribeTikTokAsyncContextFunc             func(ctx context.Context, url string) (*types.ResultResponse, error)
+	TranscribeTikTokWithArgsFunc                 func(args tiktok.TranscriptionArguments) ([]types.Document, error)
+	TranscribeTikTokWithArgsContextFunc          func(ctx context.Context, args tiktok.TranscriptionArguments) ([]types.Document, error)
+	TranscribeTikTokWithArgsAsyncFunc            func(args tiktok.TranscriptionArguments) (*types.ResultResponse, error)
+	TranscribeTikTokWithArgsAsyncContextFunc     func(ctx context.Context, args tiktok.TranscriptionArguments) (*types.ResultResponse, error)
+	SearchTikTokFunc                             func(query string) ([]types.Document, error)
+	SearchTikTokContextFunc                      func(ctx context.Context, query string) ([]types.Document, error)
+	SearchTikTokAsyncFunc                        func(query string) (*types.ResultResponse, error)
+	SearchTikTokAsyncContextFunc                 func(ctx context.Context, query string) (*types.ResultResponse, error)
+	SearchTikTokWithArgsFunc                     func(args tiktok.QueryArguments) ([]types.Document, error)
+	SearchTikTokWithArgsContextFunc              func(ctx context.Context, args tiktok.QueryArguments) ([]types.Document, error)
+	SearchTikTokWithArgsAsyncFunc                func(args tiktok.QueryArguments) (*types.ResultResponse, error)
+	SearchTikTokWithArgsAsyncContextFunc         func(ctx context.Context, args tiktok.QueryArguments) (*types.ResultResponse, error)
+	SearchTikTokTrendingFunc                     func(sortBy string) ([]types.Document, error)
+	SearchTikTokTrendingContextFunc              func(ctx context.Context, sortBy string) ([]types.Document, error)
+	SearchTikTokTrendingAsyncFunc                func(sortBy string) (*types.ResultResponse, error)
+	SearchTikTokTrendingAsyncContextFunc         func(ctx context.Context, sortBy string) (*types.ResultResponse, error)
+	SearchTikTokTrendingWithArgsFunc             func(args tiktok.TrendingArguments) ([]types.Document, error)
+	SearchTikTokTrendingWithArgsContextFunc      func(ctx context.Context, args tiktok.TrendingArguments) ([]types.Document, error)
+	SearchTikTokTrendingWithArgsAsyncFunc        func(args tiktok.TrendingArguments) (*types.ResultResponse, error)
+	SearchTikTokTrendingWithArgsAsyncContextFunc func(ctx context.Context, args tiktok.TrendingArguments) (*types.ResultResponse, error)
+
+	calls recorder
+}
+
+var _ client.TikTokClient = (*TikTokClient)(nil)
+
+// TranscribeTikTok calls TranscribeTikTokFunc
+func (m *TikTokClient) TranscribeTikTok(url string) ([]types.Document, error) {
+	m.calls.record("TranscribeTikTok", url)
+	if m.TranscribeTikTokFunc == nil {
+		panic("clientmock: TikTokClient.TranscribeTikTok called without TranscribeTikTokFunc")
+	}
+	return m.TranscribeTikTokFunc(url)
+}
+
+// TranscribeTikTokContext calls TranscribeTikTokContextFunc
+func (m *TikTokClient) TranscribeTikTokContext(ctx context.Context, url string) ([]types.Document, error) {
+	m.calls.record("TranscribeTikTokContext", ctx, url)
+	if m.TranscribeTikTokContextFunc == nil {
+		panic("clientmock: TikTokClient.TranscribeTikTokContext called without TranscribeTikTokContextFunc")
+	}
+	return m.TranscribeTikTokContextFunc(ctx, url)
+}
+
+// TranscribeTikTokAsync calls TranscribeTikTokAsyncFunc
+func (m *TikTokClient) TranscribeTikTokAsync(url string) (*types.ResultResponse, error) {
+	m.calls.record("TranscribeTikTokAsync", url)
+	if m.TranscribeTikTokAsyncFunc == nil {
+		panic("clientmock: TikTokClient.TranscribeTikTokAsync called without TranscribeTikTokAsyncFunc")
+	}
+	return m.TranscribeTikTokAsyncFunc(url)
+}
+
+// TranscribeTikTokAsyncContext calls TranscribeTikTokAsyncContextFunc
+func (m *TikTokClient) TranscribeTikTokAsyncContext(ctx context.Context, url string) (*types.ResultResponse, error) {
+	m.calls.record("TranscribeTikTokAsyncContext", ctx, url)
+	if m.TranscribeTikTokAsyncContextFunc == nil {
+		panic("clientmock: TikTokClient.TranscribeTikTokAsyncContext called without TranscribeTikTokAsyncContextFunc")
+	}
+	return m.TranscribeTikTokAsyncContextFunc(ctx, url)
+}
+
+// TranscribeTikTokWithArgs calls TranscribeTikTokWithArgsFunc
+func (m *TikTokClient) TranscribeTikTokWithArgs(args tiktok.TranscriptionArguments) ([]types.Document, error) {
+	m.calls.record("TranscribeTikTokWithArgs", args)
+	if m.TranscribeTikTokWithArgsFunc == nil {
+		panic("clientmock: TikTokClient.TranscribeTikTokWithArgs called without TranscribeTikTokWithArgsFunc")
+	}
+	return m.TranscribeTikTokWithArgsFunc(args)
+}
+
+// TranscribeTikTokWithArgsContext calls TranscribeTikTokWithArgsContextFunc
+func (m *TikTokClient) TranscribeTikTokWithArgsContext(ctx context.Context, args tiktok.TranscriptionArguments) ([]types.Document, error) {
+	m.calls.record("TranscribeTikTokWithArgsContext", ctx, args)
+	if m.TranscribeTikTokWithArgsContextFunc == nil {
+		panic("clientmock: TikTokClient.TranscribeTikTokWithArgsContext called without TranscribeTikTokWithArgsContextFunc")
+	}
+	return m.TranscribeTikTokWithArgsContextFunc(ctx, args)
+}
+
+// TranscribeTikTokWithArgsAsync calls TranscribeTikTokWithArgsAsyncFunc
+func (m *TikTokClient) TranscribeTikTokWithArgsAsync(args tiktok.TranscriptionArguments) (*types.ResultResponse, error) {
+	m.calls.record("TranscribeTikTokWithArgsAsync", args)
+	if m.TranscribeTikTokWithArgsAsyncFunc == nil {
+		panic("clientmock: TikTokClient.TranscribeTikTokWithArgsAsync called without TranscribeTikTokWithArgsAsyncFunc")
+	}
+	return m.TranscribeTikTokWithArgsAsyncFunc(args)
+}
+
+// TranscribeTikTokWithArgsAsyncContext calls TranscribeTikTokWithArgsAsyncContextFunc
+func (m *TikTokClient) TranscribeTikTokWithArgsAsyncContext(ctx context.Context, args tiktok.TranscriptionArguments) (*types.ResultResponse, error) {
+	m.calls.record("TranscribeTikTokWithArgsAsyncContext", ctx, args)
+	if m.TranscribeTikTokWithArgsAsyncContextFunc == nil {
+		panic("clientmock: TikTokClient.TranscribeTikTokWithArgsAsyncContext called without TranscribeTikTokWithArgsAsyncContextFunc")
+	}
+	return m.TranscribeTikTokWithArgsAsyncContextFunc(ctx, args)
+}
+
+// SearchTikTok calls SearchTikTokFunc
+func (m *TikTokClient) SearchTikTok(query string) ([]types.Document, error) {
+	m.calls.record("SearchTikTok", query)
+	if m.SearchTikTokFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTok called without SearchTikTokFunc")
+	}
+	return m.SearchTikTokFunc(query)
+}
+
+// SearchTikTokContext calls SearchTikTokContextFunc
+func (m *TikTokClient) SearchTikTokContext(ctx context.Context, query string) ([]types.Document, error) {
+	m.calls.record("SearchTikTokContext", ctx, query)
+	if m.SearchTikTokContextFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokContext called without SearchTikTokContextFunc")
+	}
+	return m.SearchTikTokContextFunc(ctx, query)
+}
+
+// SearchTikTokAsync calls SearchTikTokAsyncFunc
+func (m *TikTokClient) SearchTikTokAsync(query string) (*types.ResultResponse, error) {
+	m.calls.record("SearchTikTokAsync", query)
+	if m.SearchTikTokAsyncFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokAsync called without SearchTikTokAsyncFunc")
+	}
+	return m.SearchTikTokAsyncFunc(query)
+}
+
+// SearchTikTokAsyncContext calls SearchTikTokAsyncContextFunc
+func (m *TikTokClient) SearchTikTokAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error) {
+	m.calls.record("SearchTikTokAsyncContext", ctx, query)
+	if m.SearchTikTokAsyncContextFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokAsyncContext called without SearchTikTokAsyncContextFunc")
+	}
+	return m.SearchTikTokAsyncContextFunc(ctx, query)
+}
+
+// SearchTikTokWithArgs calls SearchTikTokWithArgsFunc
+func (m *TikTokClient) SearchTikTokWithArgs(args tiktok.QueryArguments) ([]types.Document, error) {
+	m.calls.record("SearchTikTokWithArgs", args)
+	if m.SearchTikTokWithArgsFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokWithArgs called without SearchTikTokWithArgsFunc")
+	}
+	return m.SearchTikTokWithArgsFunc(args)
+}
+
+// SearchTikTokWithArgsContext calls SearchTikTokWithArgsContextFunc
+func (m *TikTokClient) SearchTikTokWithArgsContext(ctx context.Context, args tiktok.QueryArguments) ([]types.Document, error) {
+	m.calls.record("SearchTikTokWithArgsContext", ctx, args)
+	if m.SearchTikTokWithArgsContextFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokWithArgsContext called without SearchTikTokWithArgsContextFunc")
+	}
+	return m.SearchTikTokWithArgsContextFunc(ctx, args)
+}
+
+// SearchTikTokWithArgsAsync calls SearchTikTokWithArgsAsyncFunc
+func (m *TikTokClient) SearchTikTokWithArgsAsync(args tiktok.QueryArguments) (*types.ResultResponse, error) {
+	m.calls.record("SearchTikTokWithArgsAsync", args)
+	if m.SearchTikTokWithArgsAsyncFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokWithArgsAsync called without SearchTikTokWithArgsAsyncFunc")
+	}
+	return m.SearchTikTokWithArgsAsyncFunc(args)
+}
+
+// SearchTikTokWithArgsAsyncContext calls SearchTikTokWithArgsAsyncContextFunc
+func (m *TikTokClient) SearchTikTokWithArgsAsyncContext(ctx context.Context, args tiktok.QueryArguments) (*types.ResultResponse, error) {
+	m.calls.record("SearchTikTokWithArgsAsyncContext", ctx, args)
+	if m.SearchTikTokWithArgsAsyncContextFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokWithArgsAsyncContext called without SearchTikTokWithArgsAsyncContextFunc")
+	}
+	return m.SearchTikTokWithArgsAsyncContextFunc(ctx, args)
+}
+
+// SearchTikTokTrending calls SearchTikTokTrendingFunc
+func (m *TikTokClient) SearchTikTokTrending(sortBy string) ([]types.Document, error) {
+	m.calls.record("SearchTikTokTrending", sortBy)
+	if m.SearchTikTokTrendingFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokTrending called without SearchTikTokTrendingFunc")
+	}
+	return m.SearchTikTokTrendingFunc(sortBy)
+}
+
+// SearchTikTokTrendingContext calls SearchTikTokTrendingContextFunc
+func (m *TikTokClient) SearchTikTokTrendingContext(ctx context.Context, sortBy string) ([]types.Document, error) {
+	m.calls.record("SearchTikTokTrendingContext", ctx, sortBy)
+	if m.SearchTikTokTrendingContextFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokTrendingContext called without SearchTikTokTrendingContextFunc")
+	}
+	return m.SearchTikTokTrendingContextFunc(ctx, sortBy)
+}
+
+// SearchTikTokTrendingAsync calls SearchTikTokTrendingAsyncFunc
+func (m *TikTokClient) SearchTikTokTrendingAsync(sortBy string) (*types.ResultResponse, error) {
+	m.calls.record("SearchTikTokTrendingAsync", sortBy)
+	if m.SearchTikTokTrendingAsyncFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokTrendingAsync called without SearchTikTokTrendingAsyncFunc")
+	}
+	return m.SearchTikTokTrendingAsyncFunc(sortBy)
+}
+
+// SearchTikTokTrendingAsyncContext calls SearchTikTokTrendingAsyncContextFunc
+func (m *TikTokClient) SearchTikTokTrendingAsyncContext(ctx context.Context, sortBy string) (*types.ResultResponse, error) {
+	m.calls.record("SearchTikTokTrendingAsyncContext", ctx, sortBy)
+	if m.SearchTikTokTrendingAsyncContextFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokTrendingAsyncContext called without SearchTikTokTrendingAsyncContextFunc")
+	}
+	return m.SearchTikTokTrendingAsyncContextFunc(ctx, sortBy)
+}
+
+// SearchTikTokTrendingWithArgs calls SearchTikTokTrendingWithArgsFunc
+func (m *TikTokClient) SearchTikTokTrendingWithArgs(args tiktok.TrendingArguments) ([]types.Document, error) {
+	m.calls.record("SearchTikTokTrendingWithArgs", args)
+	if m.SearchTikTokTrendingWithArgsFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokTrendingWithArgs called without SearchTikTokTrendingWithArgsFunc")
+	}
+	return m.SearchTikTokTrendingWithArgsFunc(args)
+}
+
+// SearchTikTokTrendingWithArgsContext calls SearchTikTokTrendingWithArgsContextFunc
+func (m *TikTokClient) SearchTikTokTrendingWithArgsContext(ctx context.Context, args tiktok.TrendingArguments) ([]types.Document, error) {
+	m.calls.record("SearchTikTokTrendingWithArgsContext", ctx, args)
+	if m.SearchTikTokTrendingWithArgsContextFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokTrendingWithArgsContext called without SearchTikTokTrendingWithArgsContextFunc")
+	}
+	return m.SearchTikTokTrendingWithArgsContextFunc(ctx, args)
+}
+
+// SearchTikTokTrendingWithArgsAsync calls SearchTikTokTrendingWithArgsAsyncFunc
+func (m *TikTokClient) SearchTikTokTrendingWithArgsAsync(args tiktok.TrendingArguments) (*types.ResultResponse, error) {
+	m.calls.record("SearchTikTokTrendingWithArgsAsync", args)
+	if m.SearchTikTokTrendingWithArgsAsyncFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokTrendingWithArgsAsync called without SearchTikTokTrendingWithArgsAsyncFunc")
+	}
+	return m.SearchTikTokTrendingWithArgsAsyncFunc(args)
+}
+
+// SearchTikTokTrendingWithArgsAsyncContext calls SearchTikTokTrendingWithArgsAsyncContextFunc
+func (m *TikTokClient) SearchTikTokTrendingWithArgsAsyncContext(ctx context.Context, args tiktok.TrendingArguments) (*types.ResultResponse, error) {
+	m.calls.record("SearchTikTokTrendingWithArgsAsyncContext", ctx, args)
+	if m.SearchTikTokTrendingWithArgsAsyncContextFunc == nil {
+		panic("clientmock: TikTokClient.SearchTikTokTrendingWithArgsAsyncContext called without SearchTikTokTrendingWithArgsAsyncContextFunc")
+	}
+	return m.SearchTikTokTrendingWithArgsAsyncContextFunc(ctx, args)
+}
+
+// Calls returns the arguments of every call of the named method, in order
+func (m *TikTokClient) Calls(method string) [][]any {
+	return m.calls.get(method)
+}
+
+// LinkedInSearcher is a mock of client.LinkedInSearcher. Each method calls the function of its Func field, which must be set.
+type LinkedInSearcher struct {
+	SearchLinkedInFunc                     func(query string) ([]types.Document, error)
+	SearchLinkedInContextFunc              func(ctx context.Context, query string) ([]types.Document, error)
+	SearchLinkedInAsyncFunc                func(query string) (*types.ResultResponse, error)
+	SearchLinkedInAsyncContextFunc         func(ctx context.Context, query string) (*types.ResultResponse, error)
+	SearchLinkedInWithArgsFunc             func(args linkedin.ProfileArguments) ([]types.Document, error)
+	SearchLinkedInWithArgsContextFunc      func(ctx context.Context, args linkedin.ProfileArguments) ([]types.Document, error)
+	SearchLinkedInWithArgsAsyncFunc        func(args linkedin.ProfileArguments) (*types.ResultResponse, error)
+	SearchLinkedInWithArgsAsyncContextFunc func(ctx context.Context, args linkedin.ProfileArguments) (*types.ResultResponse, error)
+
+	calls recorder
+}
+
+var _ client.LinkedInSearcher = (*LinkedInSearcher)(nil)
+
+// SearchLinkedIn calls SearchLinkedInFunc
+func (m *LinkedInSearcher) SearchLinkedIn(query string) ([]types.Document, error) {
+	m.calls.record("SearchLinkedIn", query)
+	if m.SearchLinkedInFunc == nil {
+		panic("clientmock: LinkedInSearcher.SearchLinkedIn called without SearchLinkedInFunc")
+	}
+	return m.SearchLinkedInFunc(query)
+}
+
+// SearchLinkedInContext calls SearchLinkedInContextFunc
+func (m *LinkedInSearcher) SearchLinkedInContext(ctx context.Context, query string) ([]types.Document, error) {
+	m.calls.record("SearchLinkedInContext", ctx, query)
+	if m.SearchLinkedInContextFunc == nil {
+		panic("clientmock: LinkedInSearcher.SearchLinkedInContext called without SearchLinkedInContextFunc")
+	}
+	return m.SearchLinkedInContextFunc(ctx, query)
+}
+
+// SearchLinkedInAsync calls SearchLinkedInAsyncFunc
+func (m *LinkedInSearcher) SearchLinkedInAsync(query string) (*types.ResultResponse, error) {
+	m.calls.record("SearchLinkedInAsync", query)
+	if m.SearchLinkedInAsyncFunc == nil {
+		panic("clientmock: LinkedInSearcher.SearchLinkedInAsync called without SearchLinkedInAsyncFunc")
+	}
+	return m.SearchLinkedInAsyncFunc(query)
+}
+
+// SearchLinkedInAsyncContext calls SearchLinkedInAsyncContextFunc
+func (m *LinkedInSearcher) SearchLinkedInAsyncContext(ctx context.Context, query string) (*types.ResultResponse, error) {
+	m.calls.record("SearchLinkedInAsyncContext", ctx, query)
+	if m.SearchLinkedInAsyncContextFunc == nil {
+		panic("clientmock: LinkedInSearcher.SearchLinkedInAsyncContext called without SearchLinkedInAsyncContextFunc")
+	}
+	return m.SearchLinkedInAsyncContextFunc(ctx, query)
+}
+
+// SearchLinkedInWithArgs calls SearchLinkedInWithArgsFunc
+func (m *LinkedInSearcher) SearchLinkedInWithArgs(args linkedin.ProfileArguments) ([]types.Document, error) {
+	m.calls.record("SearchLinkedInWithArgs", args)
+	if m.SearchLinkedInWithArgsFunc == nil {
+		panic("clientmock: LinkedInSearcher.SearchLinkedInWithArgs called without SearchLinkedInWithArgsFunc")
+	}
+	return m.SearchLinkedInWithArgsFunc(args)
+}
+
+// SearchLinkedInWithArgsContext calls SearchLinkedInWithArgsContextFunc
+func (m *LinkedInSearcher) SearchLinkedInWithArgsContext(ctx context.Context, args linkedin.ProfileArguments) ([]types.Document, error) {
+	m.calls.record("SearchLinkedInWithArgsContext", ctx, args)
+	if m.SearchLinkedInWithArgsContextFunc == nil {
+		panic("clientmock: LinkedInSearcher.SearchLinkedInWithArgsContext called without SearchLinkedInWithArgsContextFunc")
+	}
+	return m.SearchLinkedInWithArgsContextFunc(ctx, args)
+}
+
+// SearchLinkedInWithArgsAsync calls SearchLinkedInWithArgsAsyncFunc
+func (m *LinkedInSearcher) SearchLinkedInWithArgsAsync(args linkedin.ProfileArguments) (*types.ResultResponse, error) {
+	m.calls.record("SearchLinkedInWithArgsAsync", args)
+	if m.SearchLinkedInWithArgsAsyncFunc == nil {
+		panic("clientmock: LinkedInSearcher.SearchLinkedInWithArgsAsync called without SearchLinkedInWithArgsAsyncFunc")
+	}
+	return m.SearchLinkedInWithArgsAsyncFunc(args)
+}
+
+// SearchLinkedInWithArgsAsyncContext calls SearchLinkedInWithArgsAsyncContextFunc
+func (m *LinkedInSearcher) SearchLinkedInWithArgsAsyncContext(ctx context.Context, args linkedin.ProfileArguments) (*types.ResultResponse, error) {
+	m.calls.record("SearchLinkedInWithArgsAsyncContext", ctx, args)
+	if m.SearchLinkedInWithArgsAsyncContextFunc == nil {
+		panic("clientmock: LinkedInSearcher.SearchLinkedInWithArgsAsyncContext called without SearchLinkedInWithArgsAsyncContextFunc")
+	}
+	return m.SearchLinkedInWithArgsAsyncContextFunc(ctx, args)
+}
+
+// Calls returns the arguments of every call of the named method, in order
+func (m *LinkedInSearcher) Calls(method string) [][]any {
+	return m.calls.get(method)
+}
+
+// IndexSearcher is a mock of client.IndexSearcher. Each method calls the function of its Func field, which must be set.
+type IndexSearcher struct {
+	SearchSimilarityFunc        func(query string, sources []types.Source, keywords []string, operator string, maxResults int) ([]types.Document, error)
+	SearchSimilarityContextFunc func(ctx context.Context, query string, sources []types.Source, keywords []string, operator string, maxResults int) ([]types.Document, error)
+	SearchHybridFunc            func(query string, sources []types.Source, text string, queryWeight float64, textWeight float64, keywords []string, operator string, maxResults int) ([]types.Document, error)
+	SearchHybridContextFunc     func(ctx context.Context, query string, sources []types.Source, text string, queryWeight float64, textWeight float64, keywords []string, operator string, maxResults int) ([]types.Document, error)
+
+	calls recorder
+}
+
+var _ client.IndexSearcher = (*IndexSearcher)(nil)
+
+// SearchSimilarity calls SearchSimilarityFunc
+func (m *IndexSearcher) SearchSimilarity(query string, sources []types.Source, keywords []string, operator string, maxResults int) ([]types.Document, error) {
+	m.calls.record("SearchSimilarity", query, sources, keywords, operator, maxResults)
+	if m.SearchSimilarityFunc == nil {
+		panic("clientmock: IndexSearcher.SearchSimilarity called without SearchSimilarityFunc")
+	}
+	return m.SearchSimilarityFunc(query, sources, keywords, operator, maxResults)
+}
+
+// SearchSimilarityContext calls SearchSimilarityContextFunc
+func (m *IndexSearcher) SearchSimilarityContext(ctx context.Context, query string, sources []types.Source, keywords []string, operator string, maxResults int) ([]types.Document, error) {
+	m.calls.record("SearchSimilarityContext", ctx, query, sources, keywords, operator, maxResults)
+	if m.SearchSimilarityContextFunc == nil {
+		panic("clientmock: IndexSearcher.SearchSimilarityContext called without SearchSimilarityContextFunc")
+	}
+	return m.SearchSimilarityContextFunc(ctx, query, sources, keywords, operator, maxResults)
+}
+
+// SearchHybrid calls SearchHybridFunc
+func (m *IndexSearcher) SearchHybrid(query string, sources []types.Source, text string, queryWeight float64, textWeight float64, keywords []string, operator string, maxResults int) ([]types.Document, error) {
+	m.calls.record("SearchHybrid", query, sources, text, queryWeight, textWeight, keywords, operator, maxResults)
+	if m.SearchHybridFunc == nil {
+		panic("clientmock: IndexSearcher.SearchHybrid called without SearchHybridFunc")
+	}
+	return m.SearchHybridFunc(query, sources, text, queryWeight, textWeight, keywords, operator, maxResults)
+}
+
+// SearchHybridContext calls SearchHybridContextFunc
+func (m *IndexSearcher) SearchHybridContext(ctx context.Context, query string, sources []types.Source, text string, queryWeight float64, textWeight float64, keywords []string, operator string, maxResults int) ([]types.Document, error) {
+	m.calls.record("SearchHybridContext", ctx, query, sources, text, queryWeight, textWeight, keywords, operator, maxResults)
+	if m.SearchHybridContextFunc == nil {
+		panic("clientmock: IndexSearcher.SearchHybridContext called without SearchHybridContextFunc")
+	}
+	return m.SearchHybridContextFunc(ctx, query, sources, text, queryWeight, textWeight, keywords, operator, maxResults)
+}
+
+// Calls returns the arguments of every call of the named method, in order
+func (m *IndexSearcher) Calls(method string) [][]any {
+	return m.calls.get(method)
+}
+
+// Analyzer is a mock of client.Analyzer. Each method calls the function of its Func field, which must be set.
+type Analyzer struct {
+	AnalyzeDataFunc                func(data []string, prompt string) (*gophertypes.AnalysisResponse, error)
+	AnalyzeDataContextFunc         func(ctx context.Context, data []string, prompt string) (*gophertypes.AnalysisResponse, error)
+	AnalyzeDataWithArgsFunc        func(data []string, prompt string, model string, app bool, chatHistory []gophertypes.ChatHistoryItem, currentQuery string) (*gophertypes.AnalysisResponse, error)
+	AnalyzeDataWithArgsContextFunc func(ctx context.Context, data []string, prompt string, model string, app bool, chatHistory []gophertypes.ChatHistoryItem, currentQuery string) (*gophertypes.AnalysisResponse, error)
+	AnalyzeDataChunkedFunc         func(data []string, prompt string, opts client.ChunkedAnalysisOptions) (*gophertypes.AnalysisResponse, error)
+	AnalyzeDataChunkedContextFunc  func(ctx context.Context, data []string, prompt string, opts client.ChunkedAnalysisOptions) (*gophertypes.AnalysisResponse, error)
+	ExtractSearchTermsFunc         func(userInput string, maxTerms int) (*gophertypes.ExtractionResponse, error)
+	ExtractSearchTermsContextFunc  func(ctx context.Context, userInput string, maxTerms int) (*gophertypes.ExtractionResponse, error)
+	ContextualizeQueryFunc         func(currentQuery string, chatHistory []gophertypes.ChatHistoryItem, maxHistoryItems int) (*gophertypes.ContextualizeResponse, error)
+	ContextualizeQueryContextFunc  func(ctx context.Context, currentQuery string, chatHistory []gophertypes.ChatHistoryItem, maxHistoryItems int) (*gophertypes.ContextualizeResponse, error)
+	GetModelsFunc                  func() ([]gophertypes.Model, error)
+	GetModelsContextFunc           func(ctx context.Context) ([]gophertypes.Model, error)
+	GetAvailableModelsFunc         func() ([]string, error)
+	GetAvailableModelsContextFunc  func(ctx context.Context) ([]string, error)
+
+	calls recorder
+}
+
+var _ client.Analyzer = (*Analyzer)(nil)
+
+// AnalyzeData calls AnalyzeDataFunc
+func (m *Analyzer) AnalyzeData(data []string, prompt string) (*gophertypes.AnalysisResponse, error) {
+	m.calls.record("AnalyzeData", data, prompt)
+	if m.AnalyzeDataFunc == nil {
+		panic("clientmock: Analyzer.AnalyzeData called without AnalyzeDataFunc")
+	}
+	return m.AnalyzeDataFunc(data, prompt)
+}
+
+// AnalyzeDataContext calls AnalyzeDataContextFunc
+func (m *Analyzer) AnalyzeDataContext(ctx context.Context, data []string, prompt string) (*gophertypes.AnalysisResponse, error) {
+	m.calls.record("AnalyzeDataContext", ctx, data, prompt)
+	if m.AnalyzeDataContextFunc == nil {
+		panic("clientmock: Analyzer.AnalyzeDataContext called without AnalyzeDataContextFunc")
+	}
+	return m.AnalyzeDataContextFunc(ctx, data, prompt)
+}
+
+// AnalyzeDataWithArgs calls AnalyzeDataWithArgsFunc
+func (m *Analyzer) AnalyzeDataWithArgs(data []string, prompt string, model string, app bool, chatHistory []gophertypes.ChatHistoryItem, currentQuery string) (*gophertypes.AnalysisResponse, error) {
+	m.calls.record("AnalyzeDataWithArgs", data, prompt, model, app, chatHistory, currentQuery)
+	if m.AnalyzeDataWithArgsFunc == nil {
+		panic("clientmock: Analyzer.AnalyzeDataWithArgs called without AnalyzeDataWithArgsFunc")
+	}
+	return m.AnalyzeDataWithArgsFunc(data, prompt, model, app, chatHistory, currentQuery)
+}
+
+// AnalyzeDataWithArgsContext calls AnalyzeDataWithArgsContextFunc
+func (m *Analyzer) AnalyzeDataWithArgsContext(ctx context.Context, data []string, prompt string, model string, app bool, chatHistory []gophertypes.ChatHistoryItem, currentQuery string) (*gophertypes.AnalysisResponse, error) {
+	m.calls.record("AnalyzeDataWithArgsContext", ctx, data, prompt, model, app, chatHistory, currentQuery)
+	if m.AnalyzeDataWithArgsContextFunc == nil {
+		panic("clientmock: Analyzer.AnalyzeDataWithArgsContext called without AnalyzeDataWithArgsContextFunc")
+	}
+	return m.AnalyzeDataWithArgsContextFunc(ctx, data, prompt, model, app, chatHistory, currentQuery)
+}
+
+// AnalyzeDataChunked calls AnalyzeDataChunkedFunc
+func (m *Analyzer) AnalyzeDataChunked(data []string, prompt string, opts client.ChunkedAnalysisOptions) (*gophertypes.AnalysisResponse, error) {
+	m.calls.record("AnalyzeDataChunked", data, prompt, opts)
+	if m.AnalyzeDataChunkedFunc == nil {
+		panic("clientmock: Analyzer.AnalyzeDataChunked called without AnalyzeDataChunkedFunc")
+	}
+	return m.AnalyzeDataChunkedFunc(data, prompt, opts)
+}
+
+// AnalyzeDataChunkedContext calls AnalyzeDataChunkedContextFunc
+func (m *Analyzer) AnalyzeDataChunkedContext(ctx context.Context, data []string, prompt string, opts client.ChunkedAnalysisOptions) (*gophertypes.AnalysisResponse, error) {
+	m.calls.record("AnalyzeDataChunkedContext", ctx, data, prompt, opts)
+	if m.AnalyzeDataChunkedContextFunc == nil {
+		panic("clientmock: Analyzer.AnalyzeDataChunkedContext called without AnalyzeDataChunkedContextFunc")
+	}
+	return m.AnalyzeDataChunkedContextFunc(ctx, data, prompt, opts)
+}
+
+// ExtractSearchTerms calls ExtractSearchTermsFunc
+func (m *Analyzer) ExtractSearchTerms(userInput string, maxTerms int) (*gophertypes.ExtractionResponse, error) {
+	m.calls.record("ExtractSearchTerms", userInput, maxTerms)
+	if m.ExtractSearchTermsFunc == nil {
+		panic("clientmock: Analyzer.ExtractSearchTerms called without ExtractSearchTermsFunc")
+	}
+	return m.ExtractSearchTermsFunc(userInput, maxTerms)
+}
+
+// ExtractSearchTermsContext calls ExtractSearchTermsContextFunc
+func (m *Analyzer) ExtractSearchTermsContext(ctx context.Context, userInput string, maxTerms int) (*gophertypes.ExtractionResponse, error) {
+	m.calls.record("ExtractSearchTermsContext", ctx, userInput, maxTerms)
+	if m.ExtractSearchTermsContextFunc == nil {
+		panic("clientmock: Analyzer.ExtractSearchTermsContext called without ExtractSearchTermsContextFunc")
+	}
+	return m.ExtractSearchTermsContextFunc(ctx, userInput, maxTerms)
+}
+
+// ContextualizeQuery calls ContextualizeQueryFunc
+func (m *Analyzer) ContextualizeQuery(currentQuery string, chatHistory []gophertypes.ChatHistoryItem, maxHistoryItems int) (*gophertypes.ContextualizeResponse, error) {
+	m.calls.record("ContextualizeQuery", currentQuery, chatHistory, maxHistoryItems)
+	if m.ContextualizeQueryFunc == nil {
+		panic("clientmock: Analyzer.ContextualizeQuery called without ContextualizeQueryFunc")
+	}
+	return m.ContextualizeQueryFunc(currentQuery, chatHistory, maxHistoryItems)
+}
+
+// ContextualizeQueryContext calls ContextualizeQueryContextFunc
+func (m *Analyzer) ContextualizeQueryContext(ctx context.Context, currentQuery string, chatHistory []gophertypes.ChatHistoryItem, maxHistoryItems int) (*gophertypes.ContextualizeResponse, error) {
+	m.calls.record("ContextualizeQueryContext", ctx, currentQuery, chatHistory, maxHistoryItems)
+	if m.ContextualizeQueryContextFunc == nil {
+		panic("clientmock: Analyzer.ContextualizeQueryContext called without ContextualizeQueryContextFunc")
+	}
+	return m.ContextualizeQueryContextFunc(ctx, currentQuery, chatHistory, maxHistoryItems)
+}
+
+// GetModels calls GetModelsFunc
+func (m *Analyzer) GetModels() ([]gophertypes.Model, error) {
+	m.calls.record("GetModels")
+	if m.GetModelsFunc == nil {
+		panic("clientmock: Analyzer.GetModels called without GetModelsFunc")
+	}
+	return m.GetModelsFunc()
+}
+
+// GetModelsContext calls GetModelsContextFunc
+func (m *Analyzer) GetModelsContext(ctx context.Context) ([]gophertypes.Model, error) {
+	m.calls.record("GetModelsContext", ctx)
+	if m.GetModelsContextFunc == nil {
+		panic("clientmock: Analyzer.GetModelsContext called without GetModelsContextFunc")
+	}
+	return m.GetModelsContextFunc(ctx)
+}
+
+// GetAvailableModels calls GetAvailableModelsFunc
+func (m *Analyzer) GetAvailableModels() ([]string, error) {
+	m.calls.record("GetAvailableModels")
+	if m.GetAvailableModelsFunc == nil {
+		panic("clientmock: Analyzer.GetAvailableModels called without GetAvailableModelsFunc")
+	}
+	return m.GetAvailableModelsFunc()
+}
+
+// GetAvailableModelsContext calls GetAvailableModelsContextFunc
+func (m *Analyzer) GetAvailableModelsContext(ctx context.Context) ([]string, error) {
+	m.calls.record("GetAvailableModelsContext", ctx)
+	if m.GetAvailableModelsContextFunc == nil {
+		panic("clientmock: Analyzer.GetAvailableModelsContext called without GetAvailableModelsContextFunc")
+	}
+	return m.GetAvailableModelsContextFunc(ctx)
+}
+
+// Calls returns the arguments of every call of the named method, in order
+func (m *Analyzer) Calls(method string) [][]any {
+	return m.calls.get(method)
+}
+
+// JobTracker is a mock of client.JobTracker. Each method calls the function of its Func field, which must be set.
+type JobTracker struct {
+	GetJobStatusFunc                func(jobID string) (*types.IndexerJobResult, error)
+	GetJobStatusContextFunc         func(ctx context.Context, jobID string) (*types.IndexerJobResult, error)
+	GetResultFunc                   func(jobID string, receiver any) error
+	GetResultContextFunc            func(ctx context.Context, jobID string, receiver any) error
+	GetVerifiedResultFunc           func(jobID string) (*client.VerifiedResult, error)
+	GetVerifiedResultContextFunc    func(ctx context.Context, jobID string) (*client.VerifiedResult, error)
+	WaitForJobCompletionFunc        func(jobID string) ([]types.Document, error)
+	WaitForJobCompletionContextFunc func(ctx context.Context, jobID string) ([]types.Document, error)
+
+	calls recorder
+}
+
+var _ client.JobTracker = (*JobTracker)(nil)
+
+// GetJobStatus calls GetJobStatusFunc
+func (m *JobTracker) GetJobStatus(jobID string) (*types.IndexerJobResult, error) {
+	m.calls.record("GetJobStatus", jobID)
+	if m.GetJobStatusFunc == nil {
+		panic("clientmock: JobTracker.GetJobStatus called without GetJobStatusFunc")
+	}
+	return m.GetJobStatusFunc(jobID)
+}
+
+// GetJobStatusContext calls GetJobStatusContextFunc
+func (m *JobTracker) GetJobStatusContext(ctx context.Context, jobID string) (*types.IndexerJobResult, error) {
+	m.calls.record("GetJobStatusContext", ctx, jobID)
+	if m.GetJobStatusContextFunc == nil {
+		panic("clientmock: JobTracker.GetJobStatusContext called without GetJobStatusContextFunc")
+	}
+	return m.GetJobStatusContextFunc(ctx, jobID)
+}
+
+// GetResult calls GetResultFunc
+func (m *JobTracker) GetResult(jobID string, receiver any) error {
+	m.calls.record("GetResult", jobID, receiver)
+	if m.GetResultFunc == nil {
+		panic("clientmock: JobTracker.GetResult called without GetResultFunc")
+	}
+	return m.GetResultFunc(jobID, receiver)
+}
+
+// GetResultContext calls GetResultContextFunc
+func (m *JobTracker) GetResultContext(ctx context.Context, jobID string, receiver any) error {
+	m.calls.record("GetResultContext", ctx, jobID, receiver)
+	if m.GetResultContextFunc == nil {
+		panic("clientmock: JobTracker.GetResultContext called without GetResultContextFunc")
+	}
+	return m.GetResultContextFunc(ctx, jobID, receiver)
+}
+
+// GetVerifiedResult calls GetVerifiedResultFunc
+func (m *JobTracker) GetVerifiedResult(jobID string) (*client.VerifiedResult, error) {
+	m.calls.record("GetVerifiedResult", jobID)
+	if m.GetVerifiedResultFunc == nil {
+		panic("clientmock: JobTracker.GetVerifiedResult called without GetVerifiedResultFunc")
+	}
+	return m.GetVerifiedResultFunc(jobID)
+}
+
+// GetVerifiedResultContext calls GetVerifiedResultContextFunc
+func (m *JobTracker) GetVerifiedResultContext(ctx context.Context, jobID string) (*client.VerifiedResult, error) {
+	m.calls.record("GetVerifiedResultContext", ctx, jobID)
+	if m.GetVerifiedResultContextFunc == nil {
+		panic("clientmock: JobTracker.GetVerifiedResultContext called without GetVerifiedResultContextFunc")
+	}
+	return m.GetVerifiedResultContextFunc(ctx, jobID)
+}
+
+// WaitForJobCompletion calls WaitForJobCompletionFunc
+func (m *JobTracker) WaitForJobCompletion(jobID string) ([]types.Document, error) {
+	m.calls.record("WaitForJobCompletion", jobID)
+	if m.WaitForJobCompletionFunc == nil {
+		panic("clientmock: JobTracker.WaitForJobCompletion called without WaitForJobCompletionFunc")
+	}
+	return m.WaitForJobCompletionFunc(jobID)
+}
+
+// WaitForJobCompletionContext calls WaitForJobCompletionContextFunc
+func (m *JobTracker) WaitForJobCompletionContext(ctx context.Context, jobID string) ([]types.Document, error) {
+	m.calls.record("WaitForJobCompletionContext", ctx, jobID)
+	if m.WaitForJobCompletionContextFunc == nil {
+		panic("clientmock: JobTracker.WaitForJobCompletionContext called without WaitForJobCompletionContextFunc")
+	}
+	return m.WaitForJobCompletionContextFunc(ctx, jobID)
+}
+
+// Calls returns the arguments of every call of the named method, in order
+func (m *JobTracker) Calls(method string) [][]any {
+	return m.calls.get(method)
+}
+
+// MetricsReader is a mock of client.MetricsReader. Each method calls the function of its Func field, which must be set.
+type MetricsReader struct {
+	GetMetricsFunc           func(source string, refresh bool) (*types.CollectionStats, error)
+	GetMetricsContextFunc    func(ctx context.Context, source string, refresh bool) (*types.CollectionStats, error)
+	GetAllMetricsFunc        func(refresh bool) ([]types.CollectionStats, error)
+	GetAllMetricsContextFunc func(ctx context.Context, refresh bool) ([]types.CollectionStats, error)
+
+	calls recorder
+}
+
+var _ client.MetricsReader = (*MetricsReader)(nil)
+
+// GetMetrics calls GetMetricsFunc
+func (m *MetricsReader) GetMetrics(source string, refresh bool) (*types.CollectionStats, error) {
+	m.calls.record("GetMetrics", source, refresh)
+	if m.GetMetricsFunc == nil {
+		panic("clientmock: MetricsReader.GetMetrics called without GetMetricsFunc")
+	}
+	return m.GetMetricsFunc(source, refresh)
+}
+
+// GetMetricsContext calls GetMetricsContextFunc
+func (m *MetricsReader) GetMetricsContext(ctx context.Context, source string, refresh bool) (*types.CollectionStats, error) {
+	m.calls.record("GetMetricsContext", ctx, source, refresh)
+	if m.GetMetricsContextFunc == nil {
+		panic("clientmock: MetricsReader.GetMetricsContext called without GetMetricsContextFunc")
+	}
+	return m.GetMetricsContextFunc(ctx, source, refresh)
+}
+
+// GetAllMetrics calls GetAllMetricsFunc
+func (m *MetricsReader) GetAllMetrics(refresh bool) ([]types.CollectionStats, error) {
+	m.calls.record("GetAllMetrics", refresh)
+	if m.GetAllMetricsFunc == nil {
+		panic("clientmock: MetricsReader.GetAllMetrics called without GetAllMetricsFunc")
+	}
+	return m.GetAllMetricsFunc(refresh)
+}
+
+// GetAllMetricsContext calls GetAllMetricsContextFunc
+func (m *MetricsReader) GetAllMetricsContext(ctx context.Context, refresh bool) ([]types.CollectionStats, error) {
+	m.calls.record("GetAllMetricsContext", ctx, refresh)
+	if m.GetAllMetricsContextFunc == nil {
+		panic("clientmock: MetricsReader.GetAllMetricsContext called without GetAllMetricsContextFunc")
+	}
+	return m.GetAllMetricsContextFunc(ctx, refresh)
+}
+
+// Calls returns the arguments of every call of the named method, in order
+func (m *MetricsReader) Calls(method string) [][]any {
+	return m.calls.get(method)
+}
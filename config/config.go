@@ -1,27 +1,81 @@
 package config
 
 import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
 
 	"github.com/gopher-lab/gopher-client/log"
 
 	"time"
 
 	"github.com/joho/godotenv"
-	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
-	BaseUrl string        `envconfig:"GOPHER_CLIENT_URL" default:"https://data.gopher-ai.com/api"`
-	Timeout time.Duration `envconfig:"GOPHER_CLIENT_TIMEOUT" default:"60s"`
-	Token   string        `envconfig:"GOPHER_CLIENT_TOKEN"`
+	BaseUrl string        `envconfig:"GOPHER_CLIENT_URL" default:"https://data.gopher-ai.com/api" yaml:"url"`
+	Timeout time.Duration `envconfig:"GOPHER_CLIENT_TIMEOUT" default:"60s" yaml:"timeout"`
+	Token   string        `envconfig:"GOPHER_CLIENT_TOKEN" yaml:"token"`
+
+	// Rotating credentials, taking precedence over Token. TokenCommand is split into arguments like a shell
+	// command line, with single and double quotes and backslash escapes, but without expansions.
+	TokenFile    string `envconfig:"GOPHER_CLIENT_TOKEN_FILE" yaml:"token_file"`
+	TokenCommand string `envconfig:"GOPHER_CLIENT_TOKEN_COMMAND" yaml:"token_command"`
+
+	// JSON file recording submitted jobs to resume them after a restart
+	JobStoreFile string `envconfig:"GOPHER_CLIENT_JOB_STORE_FILE" yaml:"job_store_file"`
+
+	// Connection pool
+	MaxConnsPerHost     int           `envconfig:"GOPHER_CLIENT_MAX_CONNS_PER_HOST" default:"100" yaml:"max_conns_per_host"`
+	MaxIdleConnsPerHost int           `envconfig:"GOPHER_CLIENT_MAX_IDLE_CONNS_PER_HOST" default:"10" yaml:"max_idle_conns_per_host"`
+	MaxIdleConns        int           `envconfig:"GOPHER_CLIENT_MAX_IDLE_CONNS" default:"100" yaml:"max_idle_conns"`
+	IdleConnTimeout     time.Duration `envconfig:"GOPHER_CLIENT_IDLE_CONN_TIMEOUT" default:"2m" yaml:"idle_conn_timeout"`
+	IgnoreTLSCert       bool          `envconfig:"GOPHER_CLIENT_IGNORE_TLS_CERT" yaml:"ignore_tls_cert"`
+
+	// TLS, proxy and dialing
+	CACertFile       string                   `envconfig:"GOPHER_CLIENT_CA_CERT_FILE" yaml:"ca_cert_file"`
+	ClientCertFile   string                   `envconfig:"GOPHER_CLIENT_CLIENT_CERT_FILE" yaml:"client_cert_file"`
+	ClientKeyFile    string                   `envconfig:"GOPHER_CLIENT_CLIENT_KEY_FILE" yaml:"client_key_file"`
+	MinTLSVersion    string                   `envconfig:"GOPHER_CLIENT_MIN_TLS_VERSION" yaml:"min_tls_version"`
+	Proxy            string                   `envconfig:"GOPHER_CLIENT_PROXY" yaml:"proxy"`
+	DialTimeout      time.Duration            `envconfig:"GOPHER_CLIENT_DIAL_TIMEOUT" default:"30s" yaml:"dial_timeout"`
+	HostDialTimeouts map[string]time.Duration `envconfig:"GOPHER_CLIENT_HOST_DIAL_TIMEOUTS" yaml:"host_dial_timeouts"`
+
+	// Response size limit, 0 disables it, and request compression
+	MaxResponseSize  int64 `envconfig:"GOPHER_CLIENT_MAX_RESPONSE_SIZE" default:"67108864" yaml:"max_response_size"`
+	CompressRequests bool  `envconfig:"GOPHER_CLIENT_COMPRESS_REQUESTS" yaml:"compress_requests"`
+
+	// Observability
+	Tracing          bool     `envconfig:"GOPHER_CLIENT_TRACING" yaml:"tracing"`
+	DisableRedaction bool     `envconfig:"GOPHER_CLIENT_DISABLE_REDACTION" yaml:"disable_redaction"`
+	RedactKeys       []string `envconfig:"GOPHER_CLIENT_REDACT_KEYS" yaml:"redact_keys"`
+	HashKeys         []string `envconfig:"GOPHER_CLIENT_HASH_KEYS" yaml:"hash_keys"`
+	MaxBodyLength    int      `envconfig:"GOPHER_CLIENT_MAX_BODY_LENGTH" yaml:"max_body_length"`
+
+	// Profile is the name of the config file profile the Config was loaded from, if any
+	Profile string `ignored:"true" yaml:"-"`
 }
 
 // LoadConfig loads the Config from environment variables.
 // It first attempts to load from a .env file, then falls back to system environment variables.
 // The .env file loading is optional - if the file doesn't exist, it will continue without error.
+// If a config file exists, the profile selected by GOPHER_CLIENT_PROFILE or the file's default profile
+// provides the values of unset environment variables, see LoadProfile.
 func LoadConfig() (*Config, error) {
+	loadEnvFile()
+	return load("")
+}
+
+// loadEnvFile loads the nearest .env file into the environment, if any
+func loadEnvFile() {
 	// Try to load .env file (optional)
 	// Look for .env file in current directory and parent directories
 	envFiles := []string{".env", "../.env", "../../.env"}
@@ -55,13 +109,6 @@ func LoadConfig() (*Config, error) {
 	if !loaded {
 		log.Warn("Could not find .env file in current or parent directories")
 	}
-
-	var config Config
-	if err := envconfig.Process("", &config); err != nil {
-		return nil, err
-	}
-
-	return &config, nil
 }
 
 // MustLoadConfig loads the Config and panics if there's an error.
@@ -73,3 +120,175 @@ func MustLoadConfig() *Config {
 	}
 	return config
 }
+
+// Validate checks the values of the Config, reporting every invalid value with its config file key and environment variable
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(field string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s %s", describeField(field), fmt.Sprintf(format, args...)))
+	}
+
+	if c.BaseUrl == "" {
+		invalid("BaseUrl", "is required")
+	} else if u, err := url.Parse(c.BaseUrl); err != nil {
+		invalid("BaseUrl", "is not a valid URL: %v", err)
+	} else if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		invalid("BaseUrl", "must be an http(s) URL with a host, got %q", c.BaseUrl)
+	}
+
+	if c.TokenFile != "" && c.TokenCommand != "" {
+		invalid("TokenCommand", "must not be set together with %s", describeField("TokenFile"))
+	}
+	if _, err := SplitCommand(c.TokenCommand); err != nil {
+		invalid("TokenCommand", "%v", err)
+	}
+
+	if c.Timeout <= 0 {
+		invalid("Timeout", "must be positive, got %v", c.Timeout)
+	}
+	if c.IdleConnTimeout < 0 {
+		invalid("IdleConnTimeout", "must not be negative, got %v", c.IdleConnTimeout)
+	}
+	for field, conns := range map[string]int{
+		"MaxConnsPerHost":     c.MaxConnsPerHost,
+		"MaxIdleConnsPerHost": c.MaxIdleConnsPerHost,
+		"MaxIdleConns":        c.MaxIdleConns,
+	} {
+		if conns <= 0 {
+			invalid(field, "must be positive, got %d", conns)
+		}
+	}
+	if c.MaxIdleConnsPerHost > c.MaxIdleConns && c.MaxIdleConns > 0 {
+		invalid("MaxIdleConnsPerHost", "must not exceed max_idle_conns (%d), got %d", c.MaxIdleConns, c.MaxIdleConnsPerHost)
+	}
+
+	if (c.ClientCertFile == "") != (c.ClientKeyFile == "") {
+		invalid("ClientCertFile", "and %s must be set together", describeField("ClientKeyFile"))
+	}
+	if _, err := TLSVersion(c.MinTLSVersion); err != nil {
+		invalid("MinTLSVersion", "%v", err)
+	}
+	if c.Proxy != "" {
+		if u, err := url.Parse(c.Proxy); err != nil {
+			invalid("Proxy", "is not a valid URL: %v", err)
+		} else if !proxySchemes[u.Scheme] || u.Host == "" {
+			invalid("Proxy", "must be an http, https, socks5 or socks5h URL with a host, got %q", c.Proxy)
+		}
+	}
+	if c.DialTimeout < 0 {
+		invalid("DialTimeout", "must not be negative, got %v", c.DialTimeout)
+	}
+	for host, timeout := range c.HostDialTimeouts {
+		if timeout <= 0 {
+			invalid("HostDialTimeouts", "of %s must be positive, got %v", host, timeout)
+		}
+	}
+
+	if c.MaxResponseSize < 0 {
+		invalid("MaxResponseSize", "must not be negative, got %d", c.MaxResponseSize)
+	}
+	if c.MaxBodyLength < 0 {
+		invalid("MaxBodyLength", "must not be negative, got %d", c.MaxBodyLength)
+	}
+	for field, patterns := range map[string][]string{"RedactKeys": c.RedactKeys, "HashKeys": c.HashKeys} {
+		for _, pattern := range patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				invalid(field, "contains an invalid regular expression %q: %v", pattern, err)
+			}
+		}
+	}
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+var proxySchemes = map[string]bool{"http": true, "https": true, "socks5": true, "socks5h": true}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSVersion returns the tls.VersionTLS* constant of a version like "1.2", or 0 for an empty version
+func TLSVersion(version string) (uint16, error) {
+	if version == "" {
+		return 0, nil
+	}
+	v, ok := tlsVersions[version]
+	if !ok {
+		return 0, fmt.Errorf("must be one of 1.0, 1.1, 1.2 or 1.3, got %q", version)
+	}
+	return v, nil
+}
+
+// SplitCommand splits a command line into its arguments like a shell, without expansions. Arguments are
+// separated by whitespace, quoted with single quotes, taken literally, or double quotes, where a backslash
+// escapes ", \, $ and `. Outside quotes a backslash escapes any character.
+func SplitCommand(command string) ([]string, error) {
+	var (
+		args    []string
+		arg     strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range command {
+		switch {
+		case escaped:
+			if quote == '"' && !strings.ContainsRune("\"\\$`", r) {
+				arg.WriteRune('\\')
+			}
+			arg.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inArg = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	switch {
+	case escaped:
+		return nil, errors.New("must not end with an unescaped backslash")
+	case quote != 0:
+		return nil, fmt.Errorf("has an unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// describeField returns the config file key and environment variable of a Config field, e.g. "timeout (GOPHER_CLIENT_TIMEOUT)"
+func describeField(name string) string {
+	field, ok := reflect.TypeOf(Config{}).FieldByName(name)
+	if !ok {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", field.Tag.Get("yaml"), field.Tag.Get("envconfig"))
+}
@@ -2,6 +2,9 @@ package config_test
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/gopher-lab/gopher-client/config"
 	. "github.com/onsi/ginkgo/v2"
@@ -145,6 +148,121 @@ GOPHER_CLIENT_TOKEN=staging-token-456`
 	})
 })
 
+var _ = Describe("Config validation", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			BaseUrl:             "https://api.example.com",
+			Timeout:             time.Minute,
+			MaxConnsPerHost:     100,
+			MaxIdleConnsPerHost: 10,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     2 * time.Minute,
+		}
+	})
+
+	It("should accept a valid config", func() {
+		Expect(cfg.Validate()).To(Succeed())
+	})
+
+	It("should accept the defaults", func() {
+		os.Unsetenv("GOPHER_CLIENT_URL")
+		GinkgoT().Setenv(config.ConfigFileEnv, filepath.Join(GinkgoT().TempDir(), "missing.yaml"))
+
+		cfg, err := config.LoadConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validate()).To(Succeed())
+	})
+
+	It("should reject negative durations", func() {
+		cfg.Timeout = -time.Second
+		cfg.IdleConnTimeout = -time.Second
+
+		err := cfg.Validate()
+		Expect(err).To(MatchError(ContainSubstring("timeout (GOPHER_CLIENT_TIMEOUT) must be positive, got -1s")))
+		Expect(err).To(MatchError(ContainSubstring("idle_conn_timeout (GOPHER_CLIENT_IDLE_CONN_TIMEOUT) must not be negative")))
+	})
+
+	It("should reject zero connections", func() {
+		cfg.MaxConnsPerHost = 0
+
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("max_conns_per_host (GOPHER_CLIENT_MAX_CONNS_PER_HOST) must be positive, got 0")))
+	})
+
+	It("should reject more idle connections per host than in total", func() {
+		cfg.MaxIdleConnsPerHost = 200
+
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("must not exceed max_idle_conns (100), got 200")))
+	})
+
+	It("should reject malformed URLs", func() {
+		for _, baseUrl := range []string{"", "://api", "api.example.com", "ftp://api.example.com"} {
+			cfg.BaseUrl = baseUrl
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("url (GOPHER_CLIENT_URL)")), baseUrl)
+		}
+	})
+
+	It("should reject invalid key patterns", func() {
+		cfg.RedactKeys = []string{"(token"}
+
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring(`redact_keys (GOPHER_CLIENT_REDACT_KEYS) contains an invalid regular expression "(token"`)))
+	})
+
+	It("should reject invalid TLS and proxy settings", func() {
+		cfg.ClientCertFile = "client.pem"
+		cfg.MinTLSVersion = "1.4"
+		cfg.Proxy = "ftp://proxy.example.com"
+		cfg.HostDialTimeouts = map[string]time.Duration{"api.example.com": -time.Second}
+
+		err := cfg.Validate()
+		Expect(err).To(MatchError(ContainSubstring("client_cert_file (GOPHER_CLIENT_CLIENT_CERT_FILE) and client_key_file (GOPHER_CLIENT_CLIENT_KEY_FILE) must be set together")))
+		Expect(err).To(MatchError(ContainSubstring(`min_tls_version (GOPHER_CLIENT_MIN_TLS_VERSION) must be one of 1.0, 1.1, 1.2 or 1.3, got "1.4"`)))
+		Expect(err).To(MatchError(ContainSubstring("proxy (GOPHER_CLIENT_PROXY) must be an http, https, socks5 or socks5h URL")))
+		Expect(err).To(MatchError(ContainSubstring("host_dial_timeouts (GOPHER_CLIENT_HOST_DIAL_TIMEOUTS) of api.example.com must be positive")))
+	})
+
+	It("should load per-host dial timeouts from the environment", func() {
+		GinkgoT().Setenv("GOPHER_CLIENT_HOST_DIAL_TIMEOUTS", "slow.example.com:1m,fast.example.com:2s")
+
+		cfg, err := config.LoadConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.HostDialTimeouts).To(Equal(map[string]time.Duration{"slow.example.com": time.Minute, "fast.example.com": 2 * time.Second}))
+		Expect(cfg.DialTimeout).To(Equal(30 * time.Second))
+	})
+
+	It("should reject more than one token source", func() {
+		cfg.TokenFile = "/run/secrets/token"
+		cfg.TokenCommand = "vault read -field=token secret/gopher"
+
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("token_command (GOPHER_CLIENT_TOKEN_COMMAND) must not be set together with token_file (GOPHER_CLIENT_TOKEN_FILE)")))
+	})
+
+	It("should split token commands like a shell", func() {
+		args, err := config.SplitCommand(`vault kv get -field=token "secret/my app" 'it''s' a\ b "say \"hi\" \n"`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(Equal([]string{"vault", "kv", "get", "-field=token", "secret/my app", "its", "a b", `say "hi" \n`}))
+
+		args, err = config.SplitCommand(`  echo  "" `)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args).To(Equal([]string{"echo", ""}))
+
+		cfg.TokenCommand = `vault read "secret/gopher`
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring(`token_command (GOPHER_CLIENT_TOKEN_COMMAND) has an unterminated " quote`)))
+	})
+
+	It("should report every invalid value", func() {
+		cfg.Timeout = 0
+		cfg.MaxIdleConns = -1
+		cfg.MaxBodyLength = -1
+		cfg.MaxResponseSize = -1
+
+		err := cfg.Validate()
+		Expect(err).To(MatchError(HavePrefix("invalid config: ")))
+		Expect(strings.Split(err.Error(), "\n")).To(HaveLen(4))
+	})
+})
+
 var _ = Describe("Config Performance", func() {
 	BeforeEach(func() {
 		os.Setenv("GOPHER_CLIENT_URL", "https://performance.example.com")
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// ConfigFileEnv overrides the path of the config file
+	ConfigFileEnv = "GOPHER_CLIENT_CONFIG"
+	// ProfileEnv selects the profile of the config file
+	ProfileEnv = "GOPHER_CLIENT_PROFILE"
+)
+
+// File is the config file with named profiles, e.g.
+//
+//	default_profile: dev
+//	profiles:
+//	  dev:
+//	    url: http://localhost:8080/api
+//	  prod:
+//	    url: https://data.gopher-ai.com/api
+//	    token: ...
+//	    timeout: 2m
+type File struct {
+	DefaultProfile string             `yaml:"default_profile"`
+	Profiles       map[string]*Config `yaml:"profiles"`
+}
+
+// Flags holds configuration values from command line flags, which take precedence over the environment
+// and the profile. Zero values are not applied.
+type Flags struct {
+	Profile string
+	BaseUrl string
+	Token   string
+	Timeout time.Duration
+}
+
+// FilePath returns the path of the config file: $GOPHER_CLIENT_CONFIG if set, otherwise
+// $XDG_CONFIG_HOME/gopher/config.yaml, falling back to ~/.config/gopher/config.yaml
+func FilePath() (string, error) {
+	if path := os.Getenv(ConfigFileEnv); path != "" {
+		return path, nil
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gopher", "config.yaml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config file: %w", err)
+	}
+	return filepath.Join(home, ".config", "gopher", "config.yaml"), nil
+}
+
+// LoadFile reads and parses the config file at path
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// ProfileNames returns the names of the profiles in alphabetical order
+func (f *File) ProfileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadProfile loads the Config of the named profile from the config file, with environment variables
+// taking precedence over the profile and the profile over the defaults. An empty name selects the profile
+// of GOPHER_CLIENT_PROFILE, or the default profile of the config file.
+func LoadProfile(name string) (*Config, error) {
+	loadEnvFile()
+	return load(name)
+}
+
+// RegisterFlags registers the -profile, -url, -token and -timeout flags on fs
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{}
+	fs.StringVar(&f.Profile, "profile", "", "config file profile (overrides "+ProfileEnv+")")
+	fs.StringVar(&f.BaseUrl, "url", "", "API base URL (overrides GOPHER_CLIENT_URL and the profile)")
+	fs.StringVar(&f.Token, "token", "", "API token (overrides GOPHER_CLIENT_TOKEN and the profile)")
+	fs.DurationVar(&f.Timeout, "timeout", 0, "request timeout (overrides GOPHER_CLIENT_TIMEOUT and the profile)")
+	return f
+}
+
+// Load loads the Config of the selected profile and applies the flags that were set
+func (f *Flags) Load() (*Config, error) {
+	cfg, err := LoadProfile(f.Profile)
+	if err != nil {
+		return nil, err
+	}
+	if f.BaseUrl != "" {
+		cfg.BaseUrl = f.BaseUrl
+	}
+	if f.Token != "" {
+		cfg.Token = f.Token
+	}
+	if f.Timeout != 0 {
+		cfg.Timeout = f.Timeout
+	}
+	return cfg, nil
+}
+
+// load applies the environment and the defaults to the selected profile
+func load(name string) (*Config, error) {
+	profile, profileName, err := selectProfile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := envconfig.Process("", &config); err != nil {
+		return nil, err
+	}
+	if profile != nil {
+		overlayProfile(&config, profile)
+	}
+	config.Profile = profileName
+	return &config, nil
+}
+
+// selectProfile returns the profile named name, GOPHER_CLIENT_PROFILE or the default profile.
+// Without a config file no profile is used, unless one was asked for explicitly.
+func selectProfile(name string) (*Config, string, error) {
+	if name == "" {
+		name = os.Getenv(ProfileEnv)
+	}
+
+	path, err := FilePath()
+	if err != nil {
+		return nil, "", err
+	}
+	file, err := LoadFile(path)
+	if errors.Is(err, os.ErrNotExist) && name == "" {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if name == "" {
+		name = file.DefaultProfile
+	}
+	if name == "" {
+		return nil, "", nil
+	}
+	profile, ok := file.Profiles[name]
+	if !ok || profile == nil {
+		return nil, "", fmt.Errorf("profile %q not found in %s, available profiles: %v", name, path, file.ProfileNames())
+	}
+	return profile, name, nil
+}
+
+// overlayProfile copies the fields set in the profile whose environment variable is not set
+func overlayProfile(config *Config, profile *Config) {
+	target := reflect.ValueOf(config).Elem()
+	source := reflect.ValueOf(profile).Elem()
+	for i := range target.NumField() {
+		env := target.Type().Field(i).Tag.Get("envconfig")
+		if env == "" || source.Field(i).IsZero() {
+			continue
+		}
+		if _, ok := os.LookupEnv(env); ok {
+			continue
+		}
+		target.Field(i).Set(source.Field(i))
+	}
+}
@@ -0,0 +1,123 @@
+package config_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Profiles", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(path, []byte(`default_profile: dev
+profiles:
+  dev:
+    url: http://localhost:8080/api
+    token: dev-token
+  prod:
+    url: https://prod.example.com/api
+    token: prod-token
+    timeout: 2m
+    max_conns_per_host: 20
+    idle_conn_timeout: 30s
+    tracing: true
+    redact_keys:
+      - (?i)session
+`), 0600)).To(Succeed())
+
+		GinkgoT().Setenv(config.ConfigFileEnv, path)
+		os.Unsetenv("GOPHER_CLIENT_URL")
+		os.Unsetenv("GOPHER_CLIENT_TOKEN")
+		os.Unsetenv("GOPHER_CLIENT_TIMEOUT")
+		os.Unsetenv("GOPHER_CLIENT_MAX_CONNS_PER_HOST")
+		os.Unsetenv(config.ProfileEnv)
+	})
+
+	It("should load the default profile", func() {
+		cfg, err := config.LoadConfig()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cfg.Profile).To(Equal("dev"))
+		Expect(cfg.BaseUrl).To(Equal("http://localhost:8080/api"))
+		Expect(cfg.Token).To(Equal("dev-token"))
+		Expect(cfg.Timeout).To(Equal(60 * time.Second))
+	})
+
+	It("should load a named profile", func() {
+		cfg, err := config.LoadProfile("prod")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cfg.Profile).To(Equal("prod"))
+		Expect(cfg.BaseUrl).To(Equal("https://prod.example.com/api"))
+		Expect(cfg.Timeout).To(Equal(2 * time.Minute))
+		Expect(cfg.MaxConnsPerHost).To(Equal(20))
+		Expect(cfg.MaxIdleConns).To(Equal(100))
+		Expect(cfg.IdleConnTimeout).To(Equal(30 * time.Second))
+		Expect(cfg.Tracing).To(BeTrue())
+		Expect(cfg.RedactKeys).To(Equal([]string{"(?i)session"}))
+	})
+
+	It("should select the profile from the environment", func() {
+		GinkgoT().Setenv(config.ProfileEnv, "prod")
+
+		cfg, err := config.LoadConfig()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cfg.Token).To(Equal("prod-token"))
+	})
+
+	It("should let environment variables override the profile", func() {
+		GinkgoT().Setenv("GOPHER_CLIENT_TOKEN", "env-token")
+
+		cfg, err := config.LoadProfile("prod")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cfg.Token).To(Equal("env-token"))
+		Expect(cfg.BaseUrl).To(Equal("https://prod.example.com/api"))
+
+		GinkgoT().Setenv("GOPHER_CLIENT_MAX_CONNS_PER_HOST", "5")
+
+		cfg, err = config.LoadProfile("prod")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cfg.MaxConnsPerHost).To(Equal(5))
+	})
+
+	It("should let flags override the environment", func() {
+		GinkgoT().Setenv("GOPHER_CLIENT_TOKEN", "env-token")
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags := config.RegisterFlags(fs)
+		Expect(fs.Parse([]string{"-profile", "prod", "-token", "flag-token", "-timeout", "5s"})).To(Succeed())
+
+		cfg, err := flags.Load()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cfg.Token).To(Equal("flag-token"))
+		Expect(cfg.Timeout).To(Equal(5 * time.Second))
+		Expect(cfg.BaseUrl).To(Equal("https://prod.example.com/api"))
+	})
+
+	It("should report unknown profiles with the available ones", func() {
+		_, err := config.LoadProfile("staging")
+		Expect(err).To(MatchError(ContainSubstring(`profile "staging" not found`)))
+		Expect(err).To(MatchError(ContainSubstring("[dev prod]")))
+	})
+
+	It("should use the environment and defaults without a config file", func() {
+		GinkgoT().Setenv(config.ConfigFileEnv, filepath.Join(GinkgoT().TempDir(), "missing.yaml"))
+
+		cfg, err := config.LoadConfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Profile).To(BeEmpty())
+
+		_, err = config.LoadProfile("prod")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -8,33 +8,64 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/gopher-lab/gopher-client/redact"
 )
 
 var (
-	logger  *slog.Logger
-	leveler *slog.LevelVar
+	leveler = new(slog.LevelVar)
+
+	mu       sync.RWMutex // Guards logger, enabled and redactor
+	logger   = slog.New(slog.DiscardHandler)
+	enabled  bool
+	redactor = redact.Default()
 )
 
+// init keeps the global logger silent unless LOG_LEVEL is set, in which case it logs JSON to stdout
 func init() {
-	leveler = new(slog.LevelVar)
-	level, err := ParseLevel(os.Getenv("LOG_LEVEL"))
+	lvl := os.Getenv("LOG_LEVEL")
+	if lvl == "" {
+		return
+	}
+	level, err := ParseLevel(lvl)
 	if err != nil {
 		level = slog.LevelDebug
 	}
-	leveler.Set(level)
-
-	var opts = &slog.HandlerOptions{
-		Level: leveler,
-	}
-	handler := slog.NewJSONHandler(os.Stdout, opts)
-	logger = slog.New(handler)
+	SetLevel(level)
 }
 
+// SetLevel sets the level of the global logger, enabling JSON logging to stdout if no logger is set
 func SetLevel(level slog.Level) {
 	leveler.Set(level)
+	mu.Lock()
+	if !enabled {
+		logger = slog.New(redactor.Handler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: leveler})))
+		enabled = true
+	}
+	mu.Unlock()
 	Warn("Log level set", "level", leveler.Level().String())
 }
 
+// SetLogger replaces the global logger, e.g. to route the logs of this module into the application's logger.
+// Attributes are redacted with the redactor of SetRedactor. A nil logger silences the global logger again.
+func SetLogger(l *slog.Logger) {
+	mu.Lock()
+	defer mu.Unlock()
+	if l == nil {
+		logger, enabled = slog.New(slog.DiscardHandler), false
+		return
+	}
+	logger, enabled = slog.New(redactor.Handler(l.Handler())), true
+}
+
+// SetRedactor sets how the attributes of loggers set afterwards are redacted. The default is redact.Default().
+func SetRedactor(r *redact.Redactor) {
+	mu.Lock()
+	defer mu.Unlock()
+	redactor = r
+}
+
 func ParseLevel(lvl string) (slog.Level, error) {
 	l, err := strconv.Atoi(lvl)
 	if err == nil {
@@ -54,6 +85,12 @@ func ParseLevel(lvl string) (slog.Level, error) {
 }
 
 func _log(level slog.Level, msg string, args ...any) {
+	mu.RLock()
+	logger := logger
+	mu.RUnlock()
+	if !logger.Enabled(context.Background(), level) {
+		return
+	}
 	_, f, l, _ := runtime.Caller(2)
 	group := slog.Group(
 		"source",
@@ -0,0 +1,277 @@
+// Package promexport exposes gopher-client instrumentation and collection statistics as Prometheus metrics.
+//
+// Create an Exporter, pass it to the client with client.Instrument and serve its Handler:
+//
+//	exporter := promexport.New()
+//	c, err := client.NewClientWithOptions(baseURL, token, client.Instrument(exporter))
+//	exporter.TrackCollections(c, false)
+//	http.Handle("/metrics", exporter.Handler())
+package promexport
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const defaultNamespace = "gopher_client"
+
+const (
+	// maxPolledJobs bounds the number of jobs whose polls are counted until they finish
+	maxPolledJobs = 10000
+	// polledJobTTL is how long the polls of a job that is no longer polled are kept
+	polledJobTTL = time.Hour
+)
+
+// Exporter implements client.Observer and prometheus.Collector
+type Exporter struct {
+	requests      *prometheus.CounterVec
+	latency       *prometheus.HistogramVec
+	jobsSubmitted *prometheus.CounterVec
+	jobsFinished  *prometheus.CounterVec
+	jobDuration   *prometheus.HistogramVec
+	jobPolls      *prometheus.CounterVec
+	pollsPerJob   *prometheus.HistogramVec
+
+	collectionRows *prometheus.Desc
+	collectionsUp  *prometheus.Desc
+
+	mu          sync.Mutex
+	polls       map[string]polledJob
+	collections *client.Client
+	refresh     bool
+}
+
+// polledJob counts the polls of a job until it finishes
+type polledJob struct {
+	polls    int
+	lastPoll time.Time
+}
+
+type options struct {
+	namespace      string
+	latencyBuckets []float64
+	jobBuckets     []float64
+}
+
+// Option configures an Exporter
+type Option func(*options)
+
+// Namespace sets the metric name prefix. The default is "gopher_client".
+func Namespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// LatencyBuckets sets the histogram buckets in seconds of the request latency. The default is prometheus.DefBuckets.
+func LatencyBuckets(buckets []float64) Option {
+	return func(o *options) {
+		o.latencyBuckets = buckets
+	}
+}
+
+// JobDurationBuckets sets the histogram buckets in seconds of the job duration. The default ranges from 1s to ~17min.
+func JobDurationBuckets(buckets []float64) Option {
+	return func(o *options) {
+		o.jobBuckets = buckets
+	}
+}
+
+// New creates an Exporter
+func New(opts ...Option) *Exporter {
+	o := &options{
+		namespace:      defaultNamespace,
+		latencyBuckets: prometheus.DefBuckets,
+		jobBuckets:     prometheus.ExponentialBuckets(1, 2, 11),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &Exporter{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests made to the API by endpoint, method and status code (0 for transport errors).",
+		}, []string{"endpoint", "method", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: o.namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP requests made to the API by endpoint and method.",
+			Buckets:   o.latencyBuckets,
+		}, []string{"endpoint", "method"}),
+		jobsSubmitted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "jobs_submitted_total",
+			Help:      "Number of job submissions by job type and result (success or error).",
+		}, []string{"job_type", "result"}),
+		jobsFinished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "jobs_finished_total",
+			Help:      "Number of jobs waited for by job type and outcome (done, error or timeout).",
+		}, []string{"job_type", "outcome"}),
+		jobDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: o.namespace,
+			Name:      "job_duration_seconds",
+			Help:      "Time from job submission until the job finished by job type and outcome.",
+			Buckets:   o.jobBuckets,
+		}, []string{"job_type", "outcome"}),
+		jobPolls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "job_polls_total",
+			Help:      "Number of job status polls by job type and reported status.",
+		}, []string{"job_type", "status"}),
+		pollsPerJob: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: o.namespace,
+			Name:      "job_polls",
+			Help:      "Number of status polls per finished job by job type.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+		}, []string{"job_type"}),
+		collectionRows: prometheus.NewDesc(
+			prometheus.BuildFQName(o.namespace, "collection", "rows"),
+			"Number of rows per collection as reported by the metrics endpoint.",
+			[]string{"collection"}, nil,
+		),
+		collectionsUp: prometheus.NewDesc(
+			prometheus.BuildFQName(o.namespace, "collection", "metrics_up"),
+			"Whether the last collection metrics request succeeded.",
+			nil, nil,
+		),
+		polls: make(map[string]polledJob),
+	}
+}
+
+// TrackCollections exports the collection statistics of c, fetched with GetAllMetrics on every scrape
+func (e *Exporter) TrackCollections(c *client.Client, refresh bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.collections = c
+	e.refresh = refresh
+}
+
+// Handler returns an http.Handler serving the exporter's metrics in the Prometheus exposition format.
+// Use Register to add the metrics to an existing registry instead.
+func (e *Exporter) Handler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(e)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Register registers the exporter with a Prometheus registerer
+func (e *Exporter) Register(registerer prometheus.Registerer) error {
+	return registerer.Register(e)
+}
+
+// RequestDone implements client.Observer
+func (e *Exporter) RequestDone(endpoint string, method string, status int, duration time.Duration) {
+	e.requests.WithLabelValues(endpoint, method, strconv.Itoa(status)).Inc()
+	e.latency.WithLabelValues(endpoint, method).Observe(duration.Seconds())
+}
+
+// JobSubmitted implements client.Observer
+func (e *Exporter) JobSubmitted(jobType string, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	e.jobsSubmitted.WithLabelValues(jobType, result).Inc()
+}
+
+// JobPolled implements client.Observer
+func (e *Exporter) JobPolled(jobType string, jobID string, status string) {
+	e.jobPolls.WithLabelValues(jobType, status).Inc()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := time.Now()
+	job, ok := e.polls[jobID]
+	if !ok && len(e.polls) >= maxPolledJobs {
+		e.evictPolls(now.Add(-polledJobTTL))
+	}
+	e.polls[jobID] = polledJob{polls: job.polls + 1, lastPoll: now}
+}
+
+// evictPolls forgets the jobs last polled before cutoff, which are never waited for, and then the least recently
+// polled jobs until a tenth of maxPolledJobs is free. Callers must hold e.mu.
+func (e *Exporter) evictPolls(cutoff time.Time) {
+	for id, job := range e.polls {
+		if job.lastPoll.Before(cutoff) {
+			delete(e.polls, id)
+		}
+	}
+	excess := len(e.polls) - (maxPolledJobs - maxPolledJobs/10)
+	if excess <= 0 {
+		return
+	}
+	ids := slices.Collect(maps.Keys(e.polls))
+	slices.SortFunc(ids, func(a, b string) int {
+		return e.polls[a].lastPoll.Compare(e.polls[b].lastPoll)
+	})
+	for _, id := range ids[:excess] {
+		delete(e.polls, id)
+	}
+}
+
+// JobFinished implements client.Observer
+func (e *Exporter) JobFinished(jobType string, jobID string, outcome string, duration time.Duration) {
+	e.jobsFinished.WithLabelValues(jobType, outcome).Inc()
+	e.jobDuration.WithLabelValues(jobType, outcome).Observe(duration.Seconds())
+
+	e.mu.Lock()
+	job := e.polls[jobID]
+	delete(e.polls, jobID)
+	e.mu.Unlock()
+	e.pollsPerJob.WithLabelValues(jobType).Observe(float64(job.polls))
+}
+
+// Describe implements prometheus.Collector
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	e.requests.Describe(ch)
+	e.latency.Describe(ch)
+	e.jobsSubmitted.Describe(ch)
+	e.jobsFinished.Describe(ch)
+	e.jobDuration.Describe(ch)
+	e.jobPolls.Describe(ch)
+	e.pollsPerJob.Describe(ch)
+	ch <- e.collectionRows
+	ch <- e.collectionsUp
+}
+
+// Collect implements prometheus.Collector
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.requests.Collect(ch)
+	e.latency.Collect(ch)
+	e.jobsSubmitted.Collect(ch)
+	e.jobsFinished.Collect(ch)
+	e.jobDuration.Collect(ch)
+	e.jobPolls.Collect(ch)
+	e.pollsPerJob.Collect(ch)
+
+	e.mu.Lock()
+	c, refresh := e.collections, e.refresh
+	e.mu.Unlock()
+	if c == nil {
+		return
+	}
+
+	stats, err := c.GetAllMetrics(refresh)
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(e.collectionsUp, prometheus.GaugeValue, 0)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(e.collectionsUp, prometheus.GaugeValue, 1)
+	for _, stat := range stats {
+		ch <- prometheus.MustNewConstMetric(e.collectionRows, prometheus.GaugeValue, float64(stat.RowCount), stat.CollectionName)
+	}
+}
+
+var _ client.Observer = (*Exporter)(nil)
+var _ prometheus.Collector = (*Exporter)(nil)
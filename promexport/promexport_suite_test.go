@@ -0,0 +1,13 @@
+package promexport_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPromexport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Promexport Suite")
+}
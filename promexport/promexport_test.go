@@ -0,0 +1,131 @@
+package promexport_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/client"
+	"github.com/gopher-lab/gopher-client/promexport"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Exporter", func() {
+	var (
+		server   *httptest.Server
+		exporter *promexport.Exporter
+		c        *client.Client
+	)
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/v1/search/live", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"uuid": "job-1"}`))
+		})
+		mux.HandleFunc("GET /api/v1/search/live/status/job-1", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"status": "done"}`))
+		})
+		mux.HandleFunc("GET /api/v1/search/live/result/job-1", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`[{"id": "1", "content": "hello"}]`))
+		})
+		mux.HandleFunc("GET /api/v1/metrics", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`[{"collection_name": "web", "row_count": 42}]`))
+		})
+		server = httptest.NewServer(mux)
+
+		exporter = promexport.New()
+		var err error
+		c, err = client.NewClientWithOptions(server.URL+"/api", "test-token", client.Instrument(exporter))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should count requests, job submissions, polls and outcomes", func() {
+		_, err := c.SearchTwitter("golang")
+		Expect(err).NotTo(HaveOccurred())
+
+		expected := `
+# HELP gopher_client_jobs_finished_total Number of jobs waited for by job type and outcome (done, error or timeout).
+# TYPE gopher_client_jobs_finished_total counter
+gopher_client_jobs_finished_total{job_type="twitter",outcome="done"} 1
+# HELP gopher_client_jobs_submitted_total Number of job submissions by job type and result (success or error).
+# TYPE gopher_client_jobs_submitted_total counter
+gopher_client_jobs_submitted_total{job_type="twitter",result="success"} 1
+# HELP gopher_client_job_polls_total Number of job status polls by job type and reported status.
+# TYPE gopher_client_job_polls_total counter
+gopher_client_job_polls_total{job_type="twitter",status="done"} 1
+# HELP gopher_client_requests_total Number of HTTP requests made to the API by endpoint, method and status code (0 for transport errors).
+# TYPE gopher_client_requests_total counter
+gopher_client_requests_total{endpoint="/v1/search/live",method="POST",status="200"} 1
+gopher_client_requests_total{endpoint="/v1/search/live/result",method="GET",status="200"} 1
+gopher_client_requests_total{endpoint="/v1/search/live/status",method="GET",status="200"} 1
+`
+		Expect(testutil.CollectAndCompare(exporter, strings.NewReader(expected),
+			"gopher_client_jobs_finished_total",
+			"gopher_client_jobs_submitted_total",
+			"gopher_client_job_polls_total",
+			"gopher_client_requests_total",
+		)).To(Succeed())
+	})
+
+	It("should forget the polls of the least recently polled jobs once over the bound", func() {
+		exporter.JobPolled("web", "job-old", "pending")
+		for i := range 10000 {
+			exporter.JobPolled("twitter", fmt.Sprintf("job-%d", i), "pending")
+		}
+		exporter.JobFinished("web", "job-old", client.JobOutcomeDone, time.Second)
+
+		expected := `
+# HELP gopher_client_job_polls Number of status polls per finished job by job type.
+# TYPE gopher_client_job_polls histogram
+gopher_client_job_polls_bucket{job_type="web",le="1"} 1
+gopher_client_job_polls_bucket{job_type="web",le="2"} 1
+gopher_client_job_polls_bucket{job_type="web",le="4"} 1
+gopher_client_job_polls_bucket{job_type="web",le="8"} 1
+gopher_client_job_polls_bucket{job_type="web",le="16"} 1
+gopher_client_job_polls_bucket{job_type="web",le="32"} 1
+gopher_client_job_polls_bucket{job_type="web",le="64"} 1
+gopher_client_job_polls_bucket{job_type="web",le="128"} 1
+gopher_client_job_polls_bucket{job_type="web",le="256"} 1
+gopher_client_job_polls_bucket{job_type="web",le="512"} 1
+gopher_client_job_polls_bucket{job_type="web",le="+Inf"} 1
+gopher_client_job_polls_sum{job_type="web"} 0
+gopher_client_job_polls_count{job_type="web"} 1
+`
+		Expect(testutil.CollectAndCompare(exporter, strings.NewReader(expected), "gopher_client_job_polls")).To(Succeed())
+	})
+
+	It("should count failed submissions", func() {
+		server.Close()
+
+		_, err := c.SearchRedditPostsAsync("golang")
+		Expect(err).To(HaveOccurred())
+
+		expected := `
+# HELP gopher_client_jobs_submitted_total Number of job submissions by job type and result (success or error).
+# TYPE gopher_client_jobs_submitted_total counter
+gopher_client_jobs_submitted_total{job_type="reddit",result="error"} 1
+`
+		Expect(testutil.CollectAndCompare(exporter, strings.NewReader(expected), "gopher_client_jobs_submitted_total")).To(Succeed())
+	})
+
+	It("should serve collection gauges through the handler", func() {
+		exporter.TrackCollections(c, false)
+		recorder := httptest.NewRecorder()
+
+		exporter.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		body, err := io.ReadAll(recorder.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(ContainSubstring(`gopher_client_collection_rows{collection="web"} 42`))
+		Expect(string(body)).To(ContainSubstring(`gopher_client_collection_metrics_up 1`))
+	})
+})
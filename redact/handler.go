@@ -0,0 +1,83 @@
+package redact
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// Handler returns a slog.Handler that redacts the attributes of every record before passing it to next
+func (r *Redactor) Handler(next slog.Handler) slog.Handler {
+	if r == nil {
+		return next
+	}
+	return &handler{redactor: r, next: next}
+}
+
+// Attr returns the attribute with its value redacted or hashed if its key matches, groups are redacted recursively
+func (r *Redactor) Attr(attr slog.Attr) slog.Attr {
+	if r == nil {
+		return attr
+	}
+
+	value := attr.Value.Resolve()
+	switch {
+	case value.Kind() == slog.KindGroup:
+		attrs := value.Group()
+		redacted := make([]slog.Attr, len(attrs))
+		for i, a := range attrs {
+			redacted[i] = r.Attr(a)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redacted...)}
+	case r.isRedacted(attr.Key):
+		return slog.String(attr.Key, Placeholder)
+	case r.isHashed(attr.Key):
+		return slog.Attr{Key: attr.Key, Value: hashValue(value)}
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
+
+// hashValue hashes a string value, or every element of a string slice
+func hashValue(value slog.Value) slog.Value {
+	if value.Kind() == slog.KindString {
+		return slog.StringValue(Hash(value.String()))
+	}
+	if values, ok := value.Any().([]string); ok {
+		hashed := make([]string, len(values))
+		for i, v := range values {
+			hashed[i] = Hash(v)
+		}
+		return slog.AnyValue(hashed)
+	}
+	return slog.StringValue(Hash(fmt.Sprint(value.Any())))
+}
+
+type handler struct {
+	redactor *Redactor
+	next     slog.Handler
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redactor.Attr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = h.redactor.Attr(attr)
+	}
+	return &handler{redactor: h.redactor, next: h.next.WithAttrs(redacted)}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{redactor: h.redactor, next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,221 @@
+// Package redact removes secrets and user content from error messages and log attributes.
+//
+// A Redactor replaces the values of sensitive keys (tokens, passwords, ...) with a placeholder,
+// replaces the values of query-like keys with a short hash so that equal queries remain correlatable,
+// and truncates long bodies. Keys are matched in JSON bodies and in slog attributes, both as given and in
+// lower case without '_' and '-', so that "userInput", "user_input" and "UserInput" match the same patterns.
+package redact
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Placeholder replaces the values of redacted keys
+const Placeholder = "[REDACTED]"
+
+// DefaultMaxBodyLength is the number of bytes of a body kept by default
+const DefaultMaxBodyLength = 512
+
+var (
+	// DefaultRedactKeys match the keys of secrets
+	DefaultRedactKeys = []string{`(?i)(token|secret|password|passwd|api[-_]?key|authorization|cookie|credential)`}
+	// DefaultHashKeys match the keys of user queries, prompts, chat histories and analyzed data
+	DefaultHashKeys = []string{`(?i)^(query|queries|search|text|keywords|userinput|currentquery|contextualizedquery|originalquery|prompt|chathistory|tweets)$`}
+)
+
+// Redactor redacts bodies and log attributes. A nil Redactor leaves everything unchanged.
+type Redactor struct {
+	redactKeys    []*regexp.Regexp
+	hashKeys      []*regexp.Regexp
+	maxBodyLength int
+}
+
+type options struct {
+	redactKeys    []string
+	hashKeys      []string
+	maxBodyLength int
+}
+
+// Option configures a Redactor
+type Option func(*options)
+
+// RedactKeys sets the regular expressions of keys whose values are replaced with Placeholder.
+// The default is DefaultRedactKeys, no patterns redact nothing.
+func RedactKeys(patterns ...string) Option {
+	return func(o *options) {
+		o.redactKeys = patterns
+	}
+}
+
+// HashKeys sets the regular expressions of keys whose values are replaced with a hash.
+// The default is DefaultHashKeys, no patterns hash nothing.
+func HashKeys(patterns ...string) Option {
+	return func(o *options) {
+		o.hashKeys = patterns
+	}
+}
+
+// MaxBodyLength sets the number of bytes of a body kept in error messages, 0 keeps the whole body.
+// The default is DefaultMaxBodyLength.
+func MaxBodyLength(length int) Option {
+	return func(o *options) {
+		o.maxBodyLength = max(length, 0)
+	}
+}
+
+// New creates a Redactor, returning an error if a key pattern is not a valid regular expression
+func New(opts ...Option) (*Redactor, error) {
+	o := &options{
+		redactKeys:    DefaultRedactKeys,
+		hashKeys:      DefaultHashKeys,
+		maxBodyLength: DefaultMaxBodyLength,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	r := &Redactor{maxBodyLength: o.maxBodyLength}
+	for _, pattern := range o.redactKeys {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact key pattern %q: %w", pattern, err)
+		}
+		r.redactKeys = append(r.redactKeys, re)
+	}
+	for _, pattern := range o.hashKeys {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hash key pattern %q: %w", pattern, err)
+		}
+		r.hashKeys = append(r.hashKeys, re)
+	}
+	return r, nil
+}
+
+var defaultRedactor, _ = New()
+
+// Default returns the Redactor with the default key patterns and body length
+func Default() *Redactor {
+	return defaultRedactor
+}
+
+// None returns a Redactor that leaves everything unchanged
+func None() *Redactor {
+	return &Redactor{}
+}
+
+// Hash returns a short, stable hash of s, e.g. "sha256:2c26b46b68ff"
+func Hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return "sha256:" + hex.EncodeToString(sum[:6])
+}
+
+// Body returns a body for an error message, with the values of sensitive JSON keys redacted or hashed and
+// truncated to the maximum body length. Bodies that are not JSON are only truncated.
+func (r *Redactor) Body(body []byte) string {
+	if r == nil {
+		return string(body)
+	}
+
+	if len(r.redactKeys) > 0 || len(r.hashKeys) > 0 {
+		var value any
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err == nil && r.redactJSON(value) {
+			if redacted, err := json.Marshal(value); err == nil {
+				body = redacted
+			}
+		}
+	}
+	return r.truncate(string(body))
+}
+
+// Value returns the value logged for key: Placeholder for sensitive keys, a hash for query keys and
+// the value itself otherwise
+func (r *Redactor) Value(key string, value string) string {
+	switch {
+	case r == nil:
+		return value
+	case r.isRedacted(key):
+		return Placeholder
+	case r.isHashed(key):
+		return Hash(value)
+	}
+	return value
+}
+
+// truncate shortens s to the maximum body length, noting how many bytes were removed
+func (r *Redactor) truncate(s string) string {
+	if r.maxBodyLength == 0 || len(s) <= r.maxBodyLength {
+		return s
+	}
+	return fmt.Sprintf("%s...[%d bytes truncated]", s[:r.maxBodyLength], len(s)-r.maxBodyLength)
+}
+
+func (r *Redactor) isRedacted(key string) bool {
+	return matchKey(r.redactKeys, key)
+}
+
+func (r *Redactor) isHashed(key string) bool {
+	return matchKey(r.hashKeys, key)
+}
+
+// keyNormalizer removes the word separators of keys
+var keyNormalizer = strings.NewReplacer("_", "", "-", "")
+
+// matchKey reports whether a pattern matches the key as given or in lower case without word separators
+func matchKey(patterns []*regexp.Regexp, key string) bool {
+	normalized := strings.ToLower(keyNormalizer.Replace(key))
+	for _, re := range patterns {
+		if re.MatchString(key) || re.MatchString(normalized) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactJSON redacts the values of matching keys in a decoded JSON value in place, reporting whether any matched
+func (r *Redactor) redactJSON(value any) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			switch {
+			case r.isRedacted(key):
+				v[key], changed = Placeholder, true
+			case r.isHashed(key):
+				v[key], changed = hashJSON(item), true
+			default:
+				changed = r.redactJSON(item) || changed
+			}
+		}
+	case []any:
+		for _, item := range v {
+			changed = r.redactJSON(item) || changed
+		}
+	}
+	return changed
+}
+
+// hashJSON hashes strings, and the strings in lists, of a decoded JSON value
+func hashJSON(value any) any {
+	switch v := value.(type) {
+	case string:
+		return Hash(v)
+	case []any:
+		for i, item := range v {
+			v[i] = hashJSON(item)
+		}
+		return v
+	case nil:
+		return nil
+	}
+	data, _ := json.Marshal(value)
+	return Hash(string(data))
+}
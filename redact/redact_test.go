@@ -0,0 +1,85 @@
+package redact_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"github.com/gopher-lab/gopher-client/redact"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Redactor", func() {
+	Describe("Body", func() {
+		It("should redact secrets and hash queries in JSON bodies", func() {
+			body := redact.Default().Body([]byte(`{"type":"twitter","arguments":{"query":"my secret plan","api_key":"abc"},"keywords":["a","b"]}`))
+
+			var decoded map[string]any
+			Expect(json.Unmarshal([]byte(body), &decoded)).To(Succeed())
+			Expect(decoded["type"]).To(Equal("twitter"))
+			Expect(decoded["arguments"]).To(HaveKeyWithValue("query", redact.Hash("my secret plan")))
+			Expect(decoded["arguments"]).To(HaveKeyWithValue("api_key", redact.Placeholder))
+			Expect(decoded["keywords"]).To(Equal([]any{redact.Hash("a"), redact.Hash("b")}))
+		})
+
+		It("should match keys regardless of case, '_' and '-'", func() {
+			body := redact.Default().Body([]byte(`{"userInput":"my secret plan","Current-Query":"q","chatHistory":[{"query":"earlier","timestamp":"now"}],"tweets":["t"],"maxTerms":4}`))
+
+			var decoded map[string]any
+			Expect(json.Unmarshal([]byte(body), &decoded)).To(Succeed())
+			Expect(decoded).To(HaveKeyWithValue("userInput", redact.Hash("my secret plan")))
+			Expect(decoded).To(HaveKeyWithValue("Current-Query", redact.Hash("q")))
+			Expect(decoded).To(HaveKeyWithValue("chatHistory", []any{redact.Hash(`{"query":"earlier","timestamp":"now"}`)}))
+			Expect(decoded).To(HaveKeyWithValue("tweets", []any{redact.Hash("t")}))
+			Expect(decoded).To(HaveKeyWithValue("maxTerms", float64(4)))
+		})
+
+		It("should leave bodies without matching keys unchanged", func() {
+			body := `{"error":  "Invalid input"}`
+			Expect(redact.Default().Body([]byte(body))).To(Equal(body))
+		})
+
+		It("should truncate long bodies", func() {
+			r, err := redact.New(redact.MaxBodyLength(10))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r.Body([]byte(strings.Repeat("x", 25)))).To(Equal("xxxxxxxxxx...[15 bytes truncated]"))
+		})
+
+		It("should use custom key patterns", func() {
+			r, err := redact.New(redact.RedactKeys(`^ssn$`), redact.HashKeys(), redact.MaxBodyLength(0))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(r.Body([]byte(`{"query":"q","ssn":"123"}`))).To(Equal(`{"query":"q","ssn":"[REDACTED]"}`))
+		})
+
+		It("should reject invalid patterns", func() {
+			_, err := redact.New(redact.RedactKeys(`(`))
+			Expect(err).To(MatchError(ContainSubstring("invalid redact key pattern")))
+		})
+
+		It("should not change anything when disabled", func() {
+			body := `{"token":"abc"}`
+			Expect(redact.None().Body([]byte(body))).To(Equal(body))
+		})
+	})
+
+	Describe("Handler", func() {
+		It("should redact and hash log attributes", func() {
+			var output bytes.Buffer
+			logger := slog.New(redact.Default().Handler(slog.NewJSONHandler(&output, nil)))
+
+			logger.With("token", "abc").WithGroup("search").Info("Searching",
+				"query", "my secret plan", "keywords", []string{"a"}, "max_results", 10)
+
+			var record map[string]any
+			Expect(json.Unmarshal(output.Bytes(), &record)).To(Succeed())
+			Expect(record).To(HaveKeyWithValue("token", redact.Placeholder))
+			Expect(record["search"]).To(HaveKeyWithValue("query", redact.Hash("my secret plan")))
+			Expect(record["search"]).To(HaveKeyWithValue("keywords", []any{redact.Hash("a")}))
+			Expect(record["search"]).To(HaveKeyWithValue("max_results", float64(10)))
+		})
+	})
+})
@@ -0,0 +1,362 @@
+// Package scheduler runs recurring searches with the client, on cron expressions or intervals, and delivers
+// their results to sinks.
+//
+//	s, err := scheduler.New(c, scheduler.DefaultSink(scheduler.SinkFunc(handle)))
+//	err = s.Add(scheduler.Schedule{
+//		Name:   "golang-tweets",
+//		Every:  15 * time.Minute,
+//		Jitter: time.Minute,
+//		Task:   scheduler.Job(types.TwitterJob, args),
+//	})
+//	err = s.Run(ctx) // until ctx is canceled
+//
+// A schedule never overlaps itself: a run due while the previous one is still running is skipped.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/client"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	"github.com/robfig/cron/v3"
+)
+
+// DefaultHistorySize is the number of runs remembered per schedule
+const DefaultHistorySize = 100
+
+// Task runs a search with the client and returns the documents found
+type Task func(ctx context.Context, c *client.Client) ([]types.Document, error)
+
+// Job returns a Task running a job of jobType with args and waiting for its results, see client.Run
+func Job[A any](jobType types.JobType, args A) Task {
+	return func(ctx context.Context, c *client.Client) ([]types.Document, error) {
+		return client.Run(ctx, c, jobType, args)
+	}
+}
+
+// Schedule is a recurring search. Exactly one of Cron and Every must be set.
+type Schedule struct {
+	Name    string
+	Cron    string        // Standard cron expression, e.g. "*/15 * * * *", or descriptor, e.g. "@hourly"
+	Every   time.Duration // Interval between runs, the first run is one interval after the scheduler starts
+	Jitter  time.Duration // Maximum random delay added to every run, spreading the load of schedules due together
+	Timeout time.Duration // Maximum duration of a run, optional
+	Task    Task
+	Sink    Sink // Receives the results, the scheduler's default sink if nil
+
+	cron cron.Schedule
+}
+
+// validate checks the schedule and parses its cron expression
+func (s *Schedule) validate() error {
+	switch {
+	case s.Name == "":
+		return errors.New("schedule has no name")
+	case s.Task == nil:
+		return fmt.Errorf("schedule %s has no task", s.Name)
+	case s.Cron != "" && s.Every != 0:
+		return fmt.Errorf("schedule %s has both a cron expression and an interval", s.Name)
+	case s.Cron == "" && s.Every <= 0:
+		return fmt.Errorf("schedule %s needs a cron expression or a positive interval", s.Name)
+	case s.Jitter < 0:
+		return fmt.Errorf("schedule %s has a negative jitter", s.Name)
+	case s.Timeout < 0:
+		return fmt.Errorf("schedule %s has a negative timeout", s.Name)
+	}
+	if s.Cron != "" {
+		schedule, err := cron.ParseStandard(s.Cron)
+		if err != nil {
+			return fmt.Errorf("schedule %s has an invalid cron expression %q: %w", s.Name, s.Cron, err)
+		}
+		s.cron = schedule
+	}
+	return nil
+}
+
+// Next returns the time of the next run after t, without jitter, or the zero time if the cron expression is invalid
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.Cron == "" {
+		return t.Add(s.Every)
+	}
+	if s.cron == nil {
+		schedule, err := cron.ParseStandard(s.Cron)
+		if err != nil {
+			return time.Time{}
+		}
+		s.cron = schedule
+	}
+	return s.cron.Next(t)
+}
+
+// Run is a run of a schedule
+type Run struct {
+	Started   time.Time
+	Duration  time.Duration
+	Documents int
+	Err       error // Error of the task or of delivering its results
+}
+
+// Status is the state and run history of a schedule
+type Status struct {
+	Name        string
+	Running     bool
+	NextRun     time.Time // Zero if the scheduler is not running
+	Runs        int       // Number of finished runs
+	Skipped     int       // Number of runs skipped because the previous run was still running
+	LastError   error     // Error of the last failed run
+	LastErrorAt time.Time
+	History     []Run // Last runs, oldest first
+}
+
+// Scheduler runs schedules with a client
+type Scheduler struct {
+	client      *client.Client
+	sink        Sink
+	historySize int
+	logger      *slog.Logger
+
+	mu        sync.Mutex
+	schedules map[string]*entry
+	order     []string
+	ctx       context.Context // Context of Run, nil if not running
+	loops     sync.WaitGroup
+	runs      sync.WaitGroup
+}
+
+// entry is a schedule with its state
+type entry struct {
+	schedule Schedule
+	status   Status
+}
+
+// Option configures a Scheduler
+type Option func(*Scheduler) error
+
+// DefaultSink sets the sink of the schedules without their own
+func DefaultSink(sink Sink) Option {
+	return func(s *Scheduler) error {
+		s.sink = sink
+		return nil
+	}
+}
+
+// HistorySize sets the number of runs remembered per schedule, DefaultHistorySize by default
+func HistorySize(runs int) Option {
+	return func(s *Scheduler) error {
+		if runs <= 0 {
+			return fmt.Errorf("history size must be positive, got %d", runs)
+		}
+		s.historySize = runs
+		return nil
+	}
+}
+
+// Logger sets the logger of the scheduler, nothing is logged by default
+func Logger(logger *slog.Logger) Option {
+	return func(s *Scheduler) error {
+		if logger != nil {
+			s.logger = logger
+		}
+		return nil
+	}
+}
+
+// New creates a Scheduler running its schedules with the client
+func New(c *client.Client, opts ...Option) (*Scheduler, error) {
+	if c == nil {
+		return nil, errors.New("client must not be nil")
+	}
+	s := &Scheduler{
+		client:      c,
+		historySize: DefaultHistorySize,
+		logger:      slog.New(slog.DiscardHandler),
+		schedules:   make(map[string]*entry),
+	}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Add adds a schedule, which starts right away if the scheduler is running
+func (s *Scheduler) Add(schedule Schedule) error {
+	if err := schedule.validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.schedules[schedule.Name]; ok {
+		return fmt.Errorf("schedule %s already exists", schedule.Name)
+	}
+	e := &entry{schedule: schedule, status: Status{Name: schedule.Name}}
+	s.schedules[schedule.Name] = e
+	s.order = append(s.order, schedule.Name)
+	if s.ctx != nil && s.ctx.Err() == nil {
+		s.start(s.ctx, e)
+	}
+	return nil
+}
+
+// Run runs the schedules until ctx is canceled, then waits for the running tasks to return
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.ctx != nil {
+		s.mu.Unlock()
+		return errors.New("scheduler is already running")
+	}
+	s.ctx = ctx
+	for _, name := range s.order {
+		s.start(ctx, s.schedules[name])
+	}
+	s.mu.Unlock()
+
+	<-ctx.Done()
+	s.loops.Wait()
+	s.runs.Wait()
+
+	s.mu.Lock()
+	s.ctx = nil
+	for _, e := range s.schedules {
+		e.status.NextRun = time.Time{}
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// Status returns the status of the named schedule
+func (s *Scheduler) Status(name string) (Status, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.schedules[name]
+	if !ok {
+		return Status{}, false
+	}
+	return e.snapshot(), true
+}
+
+// Statuses returns the status of every schedule, in the order they were added
+func (s *Scheduler) Statuses() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]Status, 0, len(s.order))
+	for _, name := range s.order {
+		statuses = append(statuses, s.schedules[name].snapshot())
+	}
+	return statuses
+}
+
+func (e *entry) snapshot() Status {
+	status := e.status
+	status.History = append([]Run(nil), e.status.History...)
+	return status
+}
+
+// start starts the loop of a schedule, s.mu must be held
+func (s *Scheduler) start(ctx context.Context, e *entry) {
+	s.loops.Add(1)
+	go func() {
+		defer s.loops.Done()
+		s.loop(ctx, e)
+	}()
+}
+
+// loop waits for the runs of a schedule and starts them unless the previous run is still running
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	next := e.schedule.Next(time.Now())
+	for {
+		at := next
+		if e.schedule.Jitter > 0 {
+			at = at.Add(rand.N(e.schedule.Jitter))
+		}
+		s.mu.Lock()
+		e.status.NextRun = at
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(at))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.mu.Lock()
+		overlapping := e.status.Running
+		if overlapping {
+			e.status.Skipped++
+		} else {
+			e.status.Running = true
+		}
+		s.mu.Unlock()
+
+		if overlapping {
+			s.logger.WarnContext(ctx, "Skipped scheduled run, the previous run is still running", "schedule", e.schedule.Name)
+		} else {
+			s.runs.Add(1)
+			go func() {
+				defer s.runs.Done()
+				s.run(ctx, e)
+			}()
+		}
+		// The next run is computed from the due time, so that jitter and slow runs do not shift the schedule
+		next = e.schedule.Next(next)
+		if now := time.Now(); next.Before(now) {
+			next = e.schedule.Next(now)
+		}
+	}
+}
+
+// run runs the task of a schedule, delivers its results and records the run
+func (s *Scheduler) run(ctx context.Context, e *entry) {
+	if e.schedule.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.schedule.Timeout)
+		defer cancel()
+	}
+
+	started := time.Now()
+	docs, err := e.schedule.Task(ctx, s.client)
+	run := Run{Started: started, Duration: time.Since(started), Documents: len(docs), Err: err}
+
+	if sink := s.sinkOf(e); sink != nil {
+		result := Result{Schedule: e.schedule.Name, Started: started, Docs: docs, Err: err}
+		if deliverErr := sink.Deliver(ctx, result); deliverErr != nil {
+			run.Err = errors.Join(err, fmt.Errorf("failed to deliver results: %w", deliverErr))
+		}
+	}
+
+	s.mu.Lock()
+	e.status.Running = false
+	e.status.Runs++
+	e.status.History = append(e.status.History, run)
+	if len(e.status.History) > s.historySize {
+		e.status.History = e.status.History[len(e.status.History)-s.historySize:]
+	}
+	if run.Err != nil {
+		e.status.LastError = run.Err
+		e.status.LastErrorAt = started
+	}
+	s.mu.Unlock()
+
+	if run.Err != nil {
+		s.logger.WarnContext(ctx, "Scheduled run failed", "schedule", e.schedule.Name, "error", run.Err.Error())
+	} else {
+		s.logger.DebugContext(ctx, "Scheduled run done", "schedule", e.schedule.Name, "documents", run.Documents, "duration", run.Duration)
+	}
+}
+
+func (s *Scheduler) sinkOf(e *entry) Sink {
+	if e.schedule.Sink != nil {
+		return e.schedule.Sink
+	}
+	return s.sink
+}
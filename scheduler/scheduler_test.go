@@ -0,0 +1,251 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/client"
+	"github.com/gopher-lab/gopher-client/scheduler"
+	"github.com/masa-finance/tee-worker/v2/api/args/twitter"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// documentStore is a scheduler.DocumentStore in memory
+type documentStore struct {
+	mu   sync.Mutex
+	docs map[string][]types.Document
+}
+
+func (s *documentStore) StoreDocuments(_ context.Context, schedule string, docs []types.Document) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.docs[schedule] = append(s.docs[schedule], docs...)
+	return nil
+}
+
+func (s *documentStore) count(schedule string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.docs[schedule])
+}
+
+var _ = Describe("Scheduler", func() {
+	var (
+		c      *client.Client
+		ctx    context.Context
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+
+	BeforeEach(func() {
+		c = client.NewClient("http://localhost", "test-token")
+		ctx, cancel = context.WithCancel(context.Background())
+		done = make(chan struct{})
+	})
+
+	run := func(s *scheduler.Scheduler) {
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			Expect(s.Run(ctx)).To(Succeed())
+		}()
+		DeferCleanup(func() {
+			cancel()
+			Eventually(done).Should(BeClosed())
+		})
+	}
+
+	docs := func(contents ...string) []types.Document {
+		var docs []types.Document
+		for _, content := range contents {
+			docs = append(docs, types.Document{Content: content})
+		}
+		return docs
+	}
+
+	It("should run schedules on their interval and deliver the results", func() {
+		results := make(chan scheduler.Result, 10)
+		s, err := scheduler.New(c, scheduler.DefaultSink(scheduler.Channel(results)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Add(scheduler.Schedule{
+			Name:  "tweets",
+			Every: 20 * time.Millisecond,
+			Task: func(ctx context.Context, c *client.Client) ([]types.Document, error) {
+				return docs("one", "two"), nil
+			},
+		})).To(Succeed())
+		run(s)
+
+		var result scheduler.Result
+		Eventually(results).Should(Receive(&result))
+		Expect(result.Schedule).To(Equal("tweets"))
+		Expect(result.Docs).To(HaveLen(2))
+		Eventually(results).Should(Receive())
+
+		Eventually(func() int {
+			status, _ := s.Status("tweets")
+			return status.Runs
+		}).Should(BeNumerically(">=", 2))
+		status, ok := s.Status("tweets")
+		Expect(ok).To(BeTrue())
+		Expect(status.NextRun).NotTo(BeZero())
+		Expect(status.LastError).NotTo(HaveOccurred())
+		Expect(status.History[0].Documents).To(Equal(2))
+	})
+
+	It("should skip runs while the previous run is running", func() {
+		release := make(chan struct{})
+		var started atomic.Int32
+		s, err := scheduler.New(c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Add(scheduler.Schedule{
+			Name:  "slow",
+			Every: 10 * time.Millisecond,
+			Task: func(ctx context.Context, c *client.Client) ([]types.Document, error) {
+				started.Add(1)
+				select {
+				case <-release:
+				case <-ctx.Done():
+				}
+				return nil, nil
+			},
+		})).To(Succeed())
+		run(s)
+
+		Eventually(func() int {
+			status, _ := s.Status("slow")
+			return status.Skipped
+		}).Should(BeNumerically(">=", 3))
+		Expect(started.Load()).To(Equal(int32(1)))
+		status, _ := s.Status("slow")
+		Expect(status.Running).To(BeTrue())
+		close(release)
+	})
+
+	It("should record the last error and bound the history", func() {
+		var calls atomic.Int32
+		s, err := scheduler.New(c, scheduler.HistorySize(2))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Add(scheduler.Schedule{
+			Name:   "flaky",
+			Every:  10 * time.Millisecond,
+			Jitter: 5 * time.Millisecond,
+			Task: func(ctx context.Context, c *client.Client) ([]types.Document, error) {
+				if calls.Add(1) == 1 {
+					return nil, errors.New("rate limited")
+				}
+				return docs("one"), nil
+			},
+		})).To(Succeed())
+		run(s)
+
+		Eventually(func() int {
+			status, _ := s.Status("flaky")
+			return status.Runs
+		}).Should(BeNumerically(">=", 4))
+		status, _ := s.Status("flaky")
+		Expect(status.LastError).To(MatchError("rate limited"))
+		Expect(status.LastErrorAt).NotTo(BeZero())
+		Expect(status.History).To(HaveLen(2))
+		Expect(status.History[1].Err).NotTo(HaveOccurred())
+	})
+
+	It("should store the documents of successful runs and report delivery errors", func() {
+		store := &documentStore{docs: make(map[string][]types.Document)}
+		failing := scheduler.SinkFunc(func(ctx context.Context, result scheduler.Result) error {
+			return errors.New("sink unavailable")
+		})
+		s, err := scheduler.New(c, scheduler.DefaultSink(scheduler.Store(store)))
+		Expect(err).NotTo(HaveOccurred())
+		task := func(ctx context.Context, c *client.Client) ([]types.Document, error) {
+			return docs("one"), nil
+		}
+		Expect(s.Add(scheduler.Schedule{Name: "stored", Every: 10 * time.Millisecond, Task: task})).To(Succeed())
+		Expect(s.Add(scheduler.Schedule{Name: "failing", Every: 10 * time.Millisecond, Task: task, Sink: failing})).To(Succeed())
+		run(s)
+
+		Eventually(func() int { return store.count("stored") }).Should(BeNumerically(">=", 2))
+		Eventually(func() error {
+			status, _ := s.Status("failing")
+			return status.LastError
+		}).Should(MatchError(ContainSubstring("failed to deliver results: sink unavailable")))
+		Expect(store.count("failing")).To(BeZero())
+		Expect(s.Statuses()).To(HaveLen(2))
+	})
+
+	It("should run jobs with the client", func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /v1/search/live", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"uuid": "job-1"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/status/job-1", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"status": "done"}`))
+		})
+		mux.HandleFunc("GET /v1/search/live/result/job-1", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`[{"id": "1", "content": "tweet"}]`))
+		})
+		server := httptest.NewServer(mux)
+		DeferCleanup(server.Close)
+
+		results := make(chan scheduler.Result, 1)
+		s, err := scheduler.New(client.NewClient(server.URL, "test-token"))
+		Expect(err).NotTo(HaveOccurred())
+		args := twitter.NewSearchArguments()
+		args.Query = "golang"
+		Expect(s.Add(scheduler.Schedule{
+			Name:  "tweets",
+			Every: time.Hour,
+			Task:  scheduler.Job(types.TwitterJob, args),
+			Sink:  scheduler.Channel(results),
+		})).To(Succeed())
+		Expect(s.Add(scheduler.Schedule{
+			Name:  "now",
+			Every: 10 * time.Millisecond,
+			Task:  scheduler.Job(types.TwitterJob, args),
+			Sink:  scheduler.Channel(results),
+		})).To(Succeed())
+		run(s)
+
+		var result scheduler.Result
+		Eventually(results, 5*time.Second).Should(Receive(&result))
+		Expect(result.Schedule).To(Equal("now"))
+		Expect(result.Err).NotTo(HaveOccurred())
+		Expect(result.Docs).To(HaveLen(1))
+	})
+
+	It("should compute the runs of cron expressions", func() {
+		schedule := scheduler.Schedule{Name: "hourly", Cron: "*/15 * * * *", Task: func(ctx context.Context, c *client.Client) ([]types.Document, error) {
+			return nil, nil
+		}}
+		s, err := scheduler.New(c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Add(schedule)).To(Succeed())
+
+		start := time.Date(2025, 1, 1, 10, 7, 0, 0, time.UTC)
+		Expect(schedule.Next(start)).To(Equal(time.Date(2025, 1, 1, 10, 15, 0, 0, time.UTC)))
+	})
+
+	It("should reject invalid schedules", func() {
+		s, err := scheduler.New(c)
+		Expect(err).NotTo(HaveOccurred())
+		task := func(ctx context.Context, c *client.Client) ([]types.Document, error) { return nil, nil }
+
+		Expect(s.Add(scheduler.Schedule{Every: time.Minute, Task: task})).To(MatchError("schedule has no name"))
+		Expect(s.Add(scheduler.Schedule{Name: "a", Every: time.Minute})).To(MatchError(ContainSubstring("has no task")))
+		Expect(s.Add(scheduler.Schedule{Name: "a", Task: task})).To(MatchError(ContainSubstring("needs a cron expression or a positive interval")))
+		Expect(s.Add(scheduler.Schedule{Name: "a", Every: time.Minute, Cron: "@hourly", Task: task})).To(MatchError(ContainSubstring("both")))
+		Expect(s.Add(scheduler.Schedule{Name: "a", Cron: "every monday", Task: task})).To(MatchError(ContainSubstring("invalid cron expression")))
+		Expect(s.Add(scheduler.Schedule{Name: "a", Every: time.Minute, Task: task})).To(Succeed())
+		Expect(s.Add(scheduler.Schedule{Name: "a", Every: time.Minute, Task: task})).To(MatchError(ContainSubstring("already exists")))
+
+		_, err = scheduler.New(c, scheduler.HistorySize(0))
+		Expect(err).To(HaveOccurred())
+	})
+})
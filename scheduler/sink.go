@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/masa-finance/tee-worker/v2/api/types"
+)
+
+// Result is the outcome of a scheduled run delivered to a sink
+type Result struct {
+	Schedule string
+	Started  time.Time
+	Docs     []types.Document
+	Err      error // Error of the task, Docs may be empty
+}
+
+// Sink receives the results of scheduled runs. Deliver is called from the goroutine of the run, concurrently
+// for different schedules.
+type Sink interface {
+	Deliver(ctx context.Context, result Result) error
+}
+
+// SinkFunc is a Sink calling a function with every result
+type SinkFunc func(ctx context.Context, result Result) error
+
+// Deliver calls f
+func (f SinkFunc) Deliver(ctx context.Context, result Result) error {
+	return f(ctx, result)
+}
+
+// Channel returns a Sink sending every result to ch, blocking until it is received or the run is canceled
+func Channel(ch chan<- Result) Sink {
+	return SinkFunc(func(ctx context.Context, result Result) error {
+		select {
+		case ch <- result:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// DocumentStore stores the documents found by scheduled searches, e.g. in a database or search index
+type DocumentStore interface {
+	StoreDocuments(ctx context.Context, schedule string, docs []types.Document) error
+}
+
+// Store returns a Sink storing the documents of the successful runs in store. Failed runs are only recorded
+// in the run history.
+func Store(store DocumentStore) Sink {
+	return SinkFunc(func(ctx context.Context, result Result) error {
+		if result.Err != nil || len(result.Docs) == 0 {
+			return nil
+		}
+		return store.StoreDocuments(ctx, result.Schedule, result.Docs)
+	})
+}
+
+// Multi returns a Sink delivering every result to all sinks, in order, and returning their errors
+func Multi(sinks ...Sink) Sink {
+	return SinkFunc(func(ctx context.Context, result Result) error {
+		var errs []error
+		for _, sink := range sinks {
+			if err := sink.Deliver(ctx, result); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
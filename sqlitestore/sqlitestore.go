@@ -0,0 +1,161 @@
+// Package sqlitestore implements client.JobStore with SQLite, for collectors tracking many jobs.
+//
+//	store, err := sqlitestore.Open("jobs.db")
+//	c, err := client.NewClientWithOptions(baseURL, token, client.PersistJobs(store))
+//	resumed, err := c.ResumePending(ctx)
+//
+// The driver github.com/mattn/go-sqlite3 requires cgo.
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/client"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const schema = `CREATE TABLE IF NOT EXISTS jobs (
+	uuid         TEXT PRIMARY KEY,
+	type         TEXT NOT NULL,
+	arguments    TEXT,
+	status       TEXT NOT NULL,
+	error        TEXT NOT NULL DEFAULT '',
+	finished     INTEGER NOT NULL DEFAULT 0,
+	submitted_at INTEGER NOT NULL,
+	updated_at   INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS jobs_pending ON jobs (finished, submitted_at);`
+
+// Store is a client.JobStore backed by a SQLite database
+type Store struct {
+	db *sql.DB
+}
+
+var _ client.JobStore = (*Store)(nil)
+
+// Open opens or creates the SQLite database at path
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open job store %s: %w", path, err)
+	}
+	store, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open job store %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// New creates a Store in an open SQLite database, creating its table if needed
+func New(db *sql.DB) (*Store, error) {
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to create jobs table: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SaveJob inserts or replaces the job
+func (s *Store) SaveJob(ctx context.Context, job client.StoredJob) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO jobs (uuid, type, arguments, status, error, finished, submitted_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (uuid) DO UPDATE SET type = excluded.type, arguments = excluded.arguments, status = excluded.status,
+			error = excluded.error, finished = excluded.finished, submitted_at = excluded.submitted_at, updated_at = excluded.updated_at`,
+		job.UUID, string(job.Type), nullString(job.Arguments), string(job.Status), job.Error, job.Finished(),
+		job.SubmittedAt.UnixNano(), job.UpdatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to save job %s: %w", job.UUID, err)
+	}
+	return nil
+}
+
+// Job returns the job with the UUID
+func (s *Store) Job(ctx context.Context, uuid string) (client.StoredJob, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT uuid, type, arguments, status, error, submitted_at, updated_at FROM jobs WHERE uuid = ?`, uuid)
+	job, err := scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return client.StoredJob{}, fmt.Errorf("%w: %s", client.ErrJobNotFound, uuid)
+	}
+	if err != nil {
+		return client.StoredJob{}, fmt.Errorf("failed to get job %s: %w", uuid, err)
+	}
+	return job, nil
+}
+
+// PendingJobs returns the unfinished jobs in submission order
+func (s *Store) PendingJobs(ctx context.Context) ([]client.StoredJob, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT uuid, type, arguments, status, error, submitted_at, updated_at FROM jobs
+		WHERE finished = 0 ORDER BY submitted_at, uuid`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []client.StoredJob
+	for rows.Next() {
+		job, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pending jobs: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get pending jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// DeleteJob removes the job
+func (s *Store) DeleteJob(ctx context.Context, uuid string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM jobs WHERE uuid = ?`, uuid); err != nil {
+		return fmt.Errorf("failed to delete job %s: %w", uuid, err)
+	}
+	return nil
+}
+
+// Prune removes the finished jobs last updated before the time
+func (s *Store) Prune(ctx context.Context, before time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM jobs WHERE finished = 1 AND updated_at < ?`, before.UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune jobs: %w", err)
+	}
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune jobs: %w", err)
+	}
+	return int(pruned), nil
+}
+
+func scan(row interface{ Scan(dest ...any) error }) (client.StoredJob, error) {
+	var (
+		job                    client.StoredJob
+		jobType, status        string
+		arguments              sql.NullString
+		submittedAt, updatedAt int64
+	)
+	if err := row.Scan(&job.UUID, &jobType, &arguments, &status, &job.Error, &submittedAt, &updatedAt); err != nil {
+		return job, err
+	}
+	job.Type = types.JobType(jobType)
+	job.Status = types.JobStatus(status)
+	if arguments.Valid {
+		job.Arguments = []byte(arguments.String)
+	}
+	job.SubmittedAt = time.Unix(0, submittedAt)
+	job.UpdatedAt = time.Unix(0, updatedAt)
+	return job, nil
+}
+
+func nullString(data []byte) sql.NullString {
+	return sql.NullString{String: string(data), Valid: data != nil}
+}
@@ -0,0 +1,13 @@
+package sqlitestore_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSqlitestore(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Sqlitestore Suite")
+}
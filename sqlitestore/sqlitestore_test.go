@@ -0,0 +1,103 @@
+package sqlitestore_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/client"
+	"github.com/gopher-lab/gopher-client/sqlitestore"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Store", func() {
+	var (
+		ctx   context.Context
+		path  string
+		store *sqlitestore.Store
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		path = filepath.Join(GinkgoT().TempDir(), "jobs.db")
+		var err error
+		store, err = sqlitestore.Open(path)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(store.Close)
+	})
+
+	It("should save and update jobs", func() {
+		submitted := time.Now().Truncate(time.Millisecond)
+		job := client.StoredJob{
+			UUID:        "job-1",
+			Type:        types.TwitterJob,
+			Arguments:   json.RawMessage(`{"query":"golang"}`),
+			Status:      types.JobStatusReceived,
+			SubmittedAt: submitted,
+			UpdatedAt:   submitted,
+		}
+		Expect(store.SaveJob(ctx, job)).To(Succeed())
+
+		job.Status = types.JobStatusError
+		job.Error = "rate limited"
+		Expect(store.SaveJob(ctx, job)).To(Succeed())
+
+		stored, err := store.Job(ctx, "job-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Type).To(Equal(types.TwitterJob))
+		Expect(string(stored.Arguments)).To(Equal(`{"query":"golang"}`))
+		Expect(stored.Status).To(Equal(types.JobStatusError))
+		Expect(stored.Error).To(Equal("rate limited"))
+		Expect(stored.SubmittedAt.Equal(submitted)).To(BeTrue())
+	})
+
+	It("should return the pending jobs in submission order after reopening", func() {
+		now := time.Now()
+		Expect(store.SaveJob(ctx, client.StoredJob{UUID: "b", Type: types.WebJob, Status: types.JobStatusActive, SubmittedAt: now})).To(Succeed())
+		Expect(store.SaveJob(ctx, client.StoredJob{UUID: "a", Type: types.WebJob, Status: types.JobStatusReceived, SubmittedAt: now.Add(time.Second)})).To(Succeed())
+		Expect(store.SaveJob(ctx, client.StoredJob{UUID: "c", Type: types.WebJob, Status: types.JobStatusDone, SubmittedAt: now})).To(Succeed())
+		Expect(store.Close()).To(Succeed())
+
+		reopened, err := sqlitestore.Open(path)
+		Expect(err).NotTo(HaveOccurred())
+		defer reopened.Close()
+
+		pending, err := reopened.PendingJobs(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(HaveLen(2))
+		Expect(pending[0].UUID).To(Equal("b"))
+		Expect(pending[1].UUID).To(Equal("a"))
+	})
+
+	It("should delete jobs and prune finished jobs", func() {
+		now := time.Now()
+		old := now.Add(-2 * time.Hour)
+		Expect(store.SaveJob(ctx, client.StoredJob{UUID: "done", Type: types.WebJob, Status: types.JobStatusDone, SubmittedAt: old, UpdatedAt: old})).To(Succeed())
+		Expect(store.SaveJob(ctx, client.StoredJob{UUID: "recent", Type: types.WebJob, Status: types.JobStatusDone, SubmittedAt: old, UpdatedAt: now})).To(Succeed())
+		Expect(store.SaveJob(ctx, client.StoredJob{UUID: "pending", Type: types.WebJob, Status: types.JobStatusActive, SubmittedAt: old, UpdatedAt: old})).To(Succeed())
+		Expect(store.SaveJob(ctx, client.StoredJob{UUID: "deleted", Type: types.WebJob, Status: types.JobStatusActive, SubmittedAt: now, UpdatedAt: now})).To(Succeed())
+
+		Expect(store.DeleteJob(ctx, "deleted")).To(Succeed())
+		Expect(store.DeleteJob(ctx, "unknown")).To(Succeed())
+		pruned, err := store.Prune(ctx, now.Add(-time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(Equal(1))
+
+		for uuid, kept := range map[string]bool{"done": false, "recent": true, "pending": true, "deleted": false} {
+			_, err := store.Job(ctx, uuid)
+			if kept {
+				Expect(err).NotTo(HaveOccurred(), uuid)
+			} else {
+				Expect(err).To(MatchError(client.ErrJobNotFound), uuid)
+			}
+		}
+	})
+
+	It("should report unknown jobs", func() {
+		_, err := store.Job(ctx, "unknown")
+		Expect(err).To(MatchError(client.ErrJobNotFound))
+	})
+})
@@ -23,4 +23,6 @@ type AnalysisResponse struct {
 	ModelUsed  string `json:"model_used"`  // AI model used for analysis
 	TokensUsed int    `json:"tokens_used"` // Number of tokens consumed
 	JobUUID    string `json:"job_uuid"`    // Unique identifier for this analysis request
+
+	ChunkJobUUIDs []string `json:"chunk_job_uuids,omitempty"` // Job UUIDs of the per-chunk analyses when the data was analyzed in chunks
 }
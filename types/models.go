@@ -0,0 +1,99 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Model describes an AI model available for analysis
+type Model struct {
+	ID            string        `json:"id"`                       // Full model name as accepted by the analysis endpoint, e.g. "openai/gpt-4o-mini"
+	Provider      string        `json:"provider"`                 // Model provider, e.g. "openai"
+	Name          string        `json:"name"`                     // Model name without the provider, e.g. "gpt-4o-mini"
+	ContextWindow int           `json:"context_window,omitempty"` // Context window in tokens, 0 if unknown
+	Pricing       *ModelPricing `json:"pricing,omitempty"`        // Pricing, nil if unknown
+	Capabilities  []string      `json:"capabilities,omitempty"`   // Capabilities such as "tools" or "vision", if known
+}
+
+// ModelPricing holds the price of a model in USD per token
+type ModelPricing struct {
+	Prompt     float64 `json:"prompt"`     // Price per prompt token
+	Completion float64 `json:"completion"` // Price per completion token
+}
+
+// ModelFromID creates a Model from a full model name, splitting off the provider
+func ModelFromID(id string) Model {
+	model := Model{ID: id, Name: id}
+	if provider, name, ok := strings.Cut(id, "/"); ok {
+		model.Provider = provider
+		model.Name = name
+	}
+	return model
+}
+
+// UnmarshalJSON accepts either a bare model name or an object with model metadata.
+// Context windows may be given as "context_window" or "context_length", prices as numbers or numeric strings.
+func (m *Model) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		var id string
+		if err := json.Unmarshal(data, &id); err != nil {
+			return err
+		}
+		*m = ModelFromID(id)
+		return nil
+	}
+
+	var aux struct {
+		ID            string   `json:"id"`
+		Provider      string   `json:"provider"`
+		Name          string   `json:"name"`
+		ContextWindow int      `json:"context_window"`
+		ContextLength int      `json:"context_length"`
+		Capabilities  []string `json:"capabilities"`
+		Pricing       *struct {
+			Prompt     json.RawMessage `json:"prompt"`
+			Completion json.RawMessage `json:"completion"`
+		} `json:"pricing"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*m = ModelFromID(aux.ID)
+	if aux.Provider != "" {
+		m.Provider = aux.Provider
+	}
+	if aux.Name != "" && !strings.Contains(aux.Name, " ") {
+		// Display names such as "OpenAI: GPT-4o-mini" are ignored in favor of the name derived from the ID
+		m.Name = aux.Name
+	}
+	m.ContextWindow = max(aux.ContextWindow, aux.ContextLength)
+	m.Capabilities = aux.Capabilities
+	if aux.Pricing != nil {
+		prompt, err := parsePrice(aux.Pricing.Prompt)
+		if err != nil {
+			return err
+		}
+		completion, err := parsePrice(aux.Pricing.Completion)
+		if err != nil {
+			return err
+		}
+		m.Pricing = &ModelPricing{Prompt: prompt, Completion: completion}
+	}
+	return nil
+}
+
+func parsePrice(raw json.RawMessage) (float64, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return strconv.ParseFloat(s, 64)
+	}
+	var f float64
+	err := json.Unmarshal(raw, &f)
+	return f, err
+}
@@ -0,0 +1,399 @@
+package watchlist
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/masa-finance/tee-worker/v2/api/types"
+)
+
+// DefaultEngagementKeys are the metadata keys summed by MinEngagement without keys: Twitter likes, retweets and
+// replies, Reddit score and comments, TikTok likes, comments and shares
+var DefaultEngagementKeys = []string{"likes", "retweets", "replies", "score", "num_comments", "diggCount", "commentCount", "shareCount"}
+
+// Rule decides whether a document matches a watch
+type Rule interface {
+	Match(doc types.Document) bool
+}
+
+// RuleFunc is a Rule calling a function
+type RuleFunc func(doc types.Document) bool
+
+// Match calls f
+func (f RuleFunc) Match(doc types.Document) bool {
+	return f(doc)
+}
+
+// Keywords matches documents whose content contains any of the keywords or phrases as whole words, ignoring case
+func Keywords(keywords ...string) Rule {
+	return RuleFunc(func(doc types.Document) bool {
+		content := strings.ToLower(doc.Content)
+		for _, keyword := range keywords {
+			if containsWord(content, strings.ToLower(keyword)) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// All matches documents matching every rule
+func All(rules ...Rule) Rule {
+	return RuleFunc(func(doc types.Document) bool {
+		for _, rule := range rules {
+			if !rule.Match(doc) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Any matches documents matching at least one rule
+func Any(rules ...Rule) Rule {
+	return RuleFunc(func(doc types.Document) bool {
+		for _, rule := range rules {
+			if rule.Match(doc) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Not matches documents not matching rule
+func Not(rule Rule) Rule {
+	return RuleFunc(func(doc types.Document) bool {
+		return !rule.Match(doc)
+	})
+}
+
+// MinEngagement matches documents whose numeric metadata values at keys sum to at least min. Keys are dotted
+// paths into the metadata, e.g. "public_metrics.like_count", DefaultEngagementKeys if none are given.
+func MinEngagement(min float64, keys ...string) Rule {
+	if len(keys) == 0 {
+		keys = DefaultEngagementKeys
+	}
+	return RuleFunc(func(doc types.Document) bool {
+		return engagement(doc, keys) >= min
+	})
+}
+
+// Parse parses a boolean match expression of keywords, "quoted phrases" and metadata comparisons combined with
+// AND, OR, NOT and parentheses, e.g.
+//
+//	golang AND ("release candidate" OR generics) AND NOT rumor AND likes >= 100
+//
+// Adjacent terms are combined with AND. Comparisons (>=, >, <=, <, =) compare the numeric metadata value at a
+// dotted path, e.g. public_metrics.like_count > 10, or the sum of DefaultEngagementKeys for "engagement".
+func Parse(expr string) (Rule, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", expr, err)
+	}
+	p := &parser{tokens: tokens}
+	rule, err := p.or()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %s", p.tokens[p.pos])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", expr, err)
+	}
+	return rule, nil
+}
+
+// MustParse is Parse panicking on invalid expressions
+func MustParse(expr string) Rule {
+	rule, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return rule
+}
+
+// containsWord reports whether s contains word, not preceded or followed by a letter or digit
+func containsWord(s string, word string) bool {
+	if word == "" {
+		return false
+	}
+	for offset := 0; ; {
+		i := strings.Index(s[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(word)
+		if !wordRuneBefore(s, start) && !wordRuneAt(s, end) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func wordRuneBefore(s string, i int) bool {
+	r, size := utf8.DecodeLastRuneInString(s[:i])
+	return size > 0 && isWordRune(r)
+}
+
+func wordRuneAt(s string, i int) bool {
+	r, size := utf8.DecodeRuneInString(s[i:])
+	return size > 0 && isWordRune(r)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// engagement returns the sum of the numeric metadata values at keys
+func engagement(doc types.Document, keys []string) float64 {
+	var sum float64
+	for _, key := range keys {
+		if value, ok := metadataNumber(doc.Metadata, key); ok {
+			sum += value
+		}
+	}
+	return sum
+}
+
+// metadataNumber returns the numeric metadata value at a dotted path
+func metadataNumber(metadata map[string]any, path string) (float64, bool) {
+	var value any = metadata
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return 0, false
+		}
+		if value, ok = m[key]; !ok {
+			return 0, false
+		}
+	}
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// token is a token of a match expression
+type token struct {
+	kind  tokenKind
+	text  string
+	op    string  // Operator of a comparison of the metadata key text
+	value float64 // Number of a comparison
+}
+
+type tokenKind int
+
+const (
+	tokenTerm tokenKind = iota
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+	tokenComparison
+)
+
+func (t token) String() string {
+	return fmt.Sprintf("%q", t.text)
+}
+
+var comparisonOperators = []string{">=", "<=", ">", "<", "="}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	s := expr
+	for {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		if s == "" {
+			return tokens, nil
+		}
+		switch {
+		case s[0] == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "("})
+			s = s[1:]
+			continue
+		case s[0] == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")"})
+			s = s[1:]
+			continue
+		case s[0] == '"':
+			end := strings.IndexByte(s[1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated phrase %s", s)
+			}
+			tokens = append(tokens, token{kind: tokenTerm, text: s[1 : end+1]})
+			s = s[end+2:]
+			continue
+		case strings.ContainsRune("<>=", rune(s[0])):
+			if len(tokens) == 0 || tokens[len(tokens)-1].kind != tokenTerm {
+				return nil, fmt.Errorf("comparison without a metadata key at %s", s)
+			}
+			op := ""
+			for _, candidate := range comparisonOperators {
+				if strings.HasPrefix(s, candidate) {
+					op = candidate
+					break
+				}
+			}
+			s = strings.TrimLeftFunc(s[len(op):], unicode.IsSpace)
+			word := s[:wordEnd(s)]
+			value, err := strconv.ParseFloat(word, 64)
+			if err != nil {
+				return nil, fmt.Errorf("comparison with a non-numeric value %q", word)
+			}
+			key := &tokens[len(tokens)-1]
+			*key = token{kind: tokenComparison, text: key.text, op: op, value: value}
+			s = s[len(word):]
+			continue
+		}
+
+		word := s[:wordEnd(s)]
+		s = s[len(word):]
+		switch word {
+		case "AND":
+			tokens = append(tokens, token{kind: tokenAnd, text: word})
+		case "OR":
+			tokens = append(tokens, token{kind: tokenOr, text: word})
+		case "NOT":
+			tokens = append(tokens, token{kind: tokenNot, text: word})
+		default:
+			tokens = append(tokens, token{kind: tokenTerm, text: word})
+		}
+	}
+}
+
+// wordEnd returns the end of the word at the start of s
+func wordEnd(s string) int {
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`()"<>=`, r)
+	})
+	if i < 0 {
+		return len(s)
+	}
+	return i
+}
+
+// parser is a recursive descent parser of match expressions, NOT binding tighter than AND, and AND than OR
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) or() (Rule, error) {
+	var rules []Rule
+	for {
+		rule, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+		if t, ok := p.peek(); !ok || t.kind != tokenOr {
+			break
+		}
+		p.pos++
+	}
+	if len(rules) == 1 {
+		return rules[0], nil
+	}
+	return Any(rules...), nil
+}
+
+func (p *parser) and() (Rule, error) {
+	var rules []Rule
+	for {
+		rule, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+
+		t, ok := p.peek()
+		if !ok || t.kind == tokenOr || t.kind == tokenClose {
+			break
+		}
+		if t.kind == tokenAnd {
+			p.pos++
+		}
+	}
+	if len(rules) == 1 {
+		return rules[0], nil
+	}
+	return All(rules...), nil
+}
+
+func (p *parser) not() (Rule, error) {
+	if t, ok := p.peek(); ok && t.kind == tokenNot {
+		p.pos++
+		rule, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return Not(rule), nil
+	}
+	return p.operand()
+}
+
+func (p *parser) operand() (Rule, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, errors.New("unexpected end")
+	}
+	p.pos++
+	switch t.kind {
+	case tokenTerm:
+		return Keywords(t.text), nil
+	case tokenComparison:
+		return comparison(t), nil
+	case tokenOpen:
+		rule, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.peek(); !ok || t.kind != tokenClose {
+			return nil, errors.New("missing )")
+		}
+		p.pos++
+		return rule, nil
+	}
+	return nil, fmt.Errorf("unexpected %s", t)
+}
+
+// comparison returns the rule of a comparison token
+func comparison(t token) Rule {
+	compare := map[string]func(float64) bool{
+		">=": func(v float64) bool { return v >= t.value },
+		">":  func(v float64) bool { return v > t.value },
+		"<=": func(v float64) bool { return v <= t.value },
+		"<":  func(v float64) bool { return v < t.value },
+		"=":  func(v float64) bool { return v == t.value },
+	}[t.op]
+	if t.text == "engagement" {
+		return RuleFunc(func(doc types.Document) bool {
+			return compare(engagement(doc, DefaultEngagementKeys))
+		})
+	}
+	return RuleFunc(func(doc types.Document) bool {
+		value, ok := metadataNumber(doc.Metadata, t.text)
+		return ok && compare(value)
+	})
+}
@@ -0,0 +1,72 @@
+package watchlist_test
+
+import (
+	"github.com/gopher-lab/gopher-client/watchlist"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rules", func() {
+	doc := func(content string, metadata map[string]any) types.Document {
+		return types.Document{Id: "1", Content: content, Metadata: metadata}
+	}
+
+	It("should match keywords as whole words ignoring case", func() {
+		rule := watchlist.Keywords("Go", "data breach")
+		Expect(rule.Match(doc("Learning go today", nil))).To(BeTrue())
+		Expect(rule.Match(doc("Going home", nil))).To(BeFalse())
+		Expect(rule.Match(doc("Reported a Data  breach", nil))).To(BeFalse())
+		Expect(rule.Match(doc("A data breach, again", nil))).To(BeTrue())
+	})
+
+	It("should match the engagement of the metadata", func() {
+		rule := watchlist.MinEngagement(100)
+		Expect(rule.Match(doc("", map[string]any{"likes": 80.0, "retweets": 30.0}))).To(BeTrue())
+		Expect(rule.Match(doc("", map[string]any{"likes": 80.0}))).To(BeFalse())
+
+		nested := watchlist.MinEngagement(10, "public_metrics.like_count")
+		Expect(nested.Match(doc("", map[string]any{"public_metrics": map[string]any{"like_count": 12.0}}))).To(BeTrue())
+		Expect(nested.Match(doc("", nil))).To(BeFalse())
+	})
+
+	It("should combine rules", func() {
+		rule := watchlist.All(watchlist.Keywords("golang"), watchlist.Not(watchlist.Any(watchlist.Keywords("rumor"), watchlist.Keywords("spam"))))
+		Expect(rule.Match(doc("golang release", nil))).To(BeTrue())
+		Expect(rule.Match(doc("golang rumor", nil))).To(BeFalse())
+	})
+
+	DescribeTable("should evaluate boolean expressions",
+		func(expr string, content string, metadata map[string]any, match bool) {
+			rule, err := watchlist.Parse(expr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rule.Match(doc(content, metadata))).To(Equal(match))
+		},
+		Entry("keyword", "golang", "I like Golang", nil, true),
+		Entry("implicit AND", "golang release", "golang is great", nil, false),
+		Entry("OR", "rust OR golang", "golang is great", nil, true),
+		Entry("NOT", "golang NOT rumor", "golang rumor", nil, false),
+		Entry("phrase", `"release candidate"`, "the release candidate is out", nil, true),
+		Entry("precedence", "a OR b AND c", "a", nil, true),
+		Entry("parentheses", "(a OR b) AND c", "a", nil, false),
+		Entry("comparison", "golang AND likes >= 100", "golang", map[string]any{"likes": 150.0}, true),
+		Entry("failed comparison", "golang AND likes>=100", "golang", map[string]any{"likes": 50.0}, false),
+		Entry("missing metadata", "likes < 10", "golang", nil, false),
+		Entry("nested metadata", "public_metrics.like_count > 5", "", map[string]any{"public_metrics": map[string]any{"like_count": 6.0}}, true),
+		Entry("engagement", "engagement >= 10", "", map[string]any{"likes": 4.0, "replies": 6.0}, true),
+	)
+
+	DescribeTable("should reject invalid expressions",
+		func(expr string, message string) {
+			_, err := watchlist.Parse(expr)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("empty", "", "unexpected end"),
+		Entry("dangling operator", "golang AND", "unexpected end"),
+		Entry("unbalanced parentheses", "(golang OR rust", "missing )"),
+		Entry("extra parenthesis", "golang)", `unexpected ")"`),
+		Entry("unterminated phrase", `"golang`, "unterminated phrase"),
+		Entry("non-numeric comparison", "likes > many", "non-numeric"),
+		Entry("comparison without key", ">= 5", "without a metadata key"),
+	)
+})
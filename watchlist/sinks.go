@@ -0,0 +1,187 @@
+package watchlist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWebhookTimeout is the timeout of webhook requests without an HTTP client
+const DefaultWebhookTimeout = 10 * time.Second
+
+// Sink receives the alerts of a watch run. Send is called concurrently for different watches.
+type Sink interface {
+	Send(ctx context.Context, alerts []Alert) error
+}
+
+// SinkFunc is a Sink calling a function
+type SinkFunc func(ctx context.Context, alerts []Alert) error
+
+// Send calls f
+func (f SinkFunc) Send(ctx context.Context, alerts []Alert) error {
+	return f(ctx, alerts)
+}
+
+// webhookPayload is the body POSTed by Webhook
+type webhookPayload struct {
+	Alerts []Alert `json:"alerts"`
+}
+
+// Webhook returns a Sink POSTing the alerts as JSON {"alerts": [...]} to url with the HTTP client, or a client
+// with DefaultWebhookTimeout if nil
+func Webhook(url string, client *http.Client) Sink {
+	return SinkFunc(func(ctx context.Context, alerts []Alert) error {
+		return postJSON(ctx, client, url, webhookPayload{Alerts: alerts})
+	})
+}
+
+// slackMessage is a message of a Slack incoming webhook
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// slackEscaper escapes the control characters of Slack's mrkdwn, so that documents can't mention channels or
+// add links
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Slack returns a Sink posting the alerts as a message to a Slack incoming webhook, or to any webhook
+// accepting the Slack message format, e.g. Mattermost or Discord's /slack endpoint. The documents are escaped,
+// URLs containing | or > are not linked.
+func Slack(webhookURL string, client *http.Client) Sink {
+	return SinkFunc(func(ctx context.Context, alerts []Alert) error {
+		if len(alerts) == 0 {
+			return nil
+		}
+		var text strings.Builder
+		fmt.Fprintf(&text, "*%d new %s for watch %s*", len(alerts), plural(len(alerts), "match", "matches"),
+			slackEscaper.Replace(alerts[0].Watch))
+		for _, alert := range alerts {
+			text.WriteString("\n• ")
+			source := slackEscaper.Replace(string(alert.Document.Source))
+			if url := alert.URL(); url != "" && !strings.ContainsAny(url, "|>") {
+				fmt.Fprintf(&text, "<%s|%s> ", slackEscaper.Replace(url), source)
+			} else {
+				fmt.Fprintf(&text, "%s ", source)
+			}
+			text.WriteString(slackEscaper.Replace(excerpt(alert.Document.Content, 200)))
+		}
+		return postJSON(ctx, client, webhookURL, slackMessage{Text: text.String()})
+	})
+}
+
+// Writer returns a Sink writing every alert as a line of JSON to w
+func Writer(w io.Writer) Sink {
+	var mu sync.Mutex
+	return SinkFunc(func(_ context.Context, alerts []Alert) error {
+		mu.Lock()
+		defer mu.Unlock()
+		encoder := json.NewEncoder(w)
+		for _, alert := range alerts {
+			if err := encoder.Encode(alert); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Stdout returns a Sink writing every alert as a line of JSON to the standard output
+func Stdout() Sink {
+	return Writer(os.Stdout)
+}
+
+// File returns a Sink appending every alert as a line of JSON to the file at path, created if needed
+func File(path string) Sink {
+	var mu sync.Mutex
+	return SinkFunc(func(ctx context.Context, alerts []Alert) error {
+		mu.Lock()
+		defer mu.Unlock()
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open alert file: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := Writer(&buf).Send(ctx, alerts); err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := f.Write(buf.Bytes()); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write alert file: %w", err)
+		}
+		return f.Close()
+	})
+}
+
+// postJSON POSTs body as JSON to rawURL, failing on statuses other than 2xx. Webhook URLs are secrets, errors
+// only include their scheme and host.
+func postJSON(ctx context.Context, client *http.Client, rawURL string, body any) error {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	host := webhookHost(rawURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create POST request to webhook %s: %w", host, withoutURL(err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to do POST request to webhook %s: %w", host, withoutURL(err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook %s returned status code %d: %s", host, resp.StatusCode, respBody)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// webhookHost returns the scheme and host of a webhook URL
+func webhookHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "(invalid URL)"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// withoutURL returns the cause of a *url.Error, which includes the full URL
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+func plural(n int, singular string, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
+
+// excerpt returns the first n characters of s on a single line
+func excerpt(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,239 @@
+// Package watchlist alerts on new documents matching watch rules. Watches run recurring searches with the
+// scheduler package, skip the documents they have seen before, match the others against their Rule and send
+// an alert per match to sinks: webhooks, Slack, files or the standard output.
+//
+//	engine, err := watchlist.New(watchlist.Sinks(watchlist.Slack(slackURL, nil)))
+//	s, err := scheduler.New(c)
+//	err = engine.Schedule(s, watchlist.Watch{
+//		Name:  "brand",
+//		Every: 10 * time.Minute,
+//		Task:  watchlist.TwitterQuery("gopher"),
+//		Rule:  watchlist.MustParse(`(outage OR "data breach") AND likes >= 50`),
+//	})
+//	err = s.Run(ctx)
+//
+// Documents are marked as seen before their alerts are sent, so alerts that fail to send are not retried.
+package watchlist
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/scheduler"
+	"github.com/masa-finance/tee-worker/v2/api/args/reddit"
+	"github.com/masa-finance/tee-worker/v2/api/args/tiktok"
+	"github.com/masa-finance/tee-worker/v2/api/args/twitter"
+	"github.com/masa-finance/tee-worker/v2/api/args/web"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+)
+
+// DefaultSeenCapacity is the number of document IDs remembered by the default SeenStore
+const DefaultSeenCapacity = 100000
+
+// Alert is a new document matching a watch
+type Alert struct {
+	Watch     string         `json:"watch"`
+	Document  types.Document `json:"document"`
+	MatchedAt time.Time      `json:"matched_at"`
+}
+
+// URL returns the URL of the document from its metadata, if any
+func (a Alert) URL() string {
+	for _, key := range []string{"url", "permalink", "URL"} {
+		if url, ok := a.Document.Metadata[key].(string); ok && url != "" {
+			return url
+		}
+	}
+	return ""
+}
+
+// Watch is a recurring search alerting on new matching documents. Exactly one of Cron and Every must be set.
+type Watch struct {
+	Name   string
+	Task   scheduler.Task
+	Rule   Rule // Matches the documents to alert on, every new document if nil
+	Cron   string
+	Every  time.Duration
+	Jitter time.Duration
+	Sinks  []Sink // Receive the alerts instead of the engine's sinks, optional
+}
+
+// SeenStore remembers the documents seen by watches
+type SeenStore interface {
+	// Mark marks the key as seen and reports whether it was seen before
+	Mark(ctx context.Context, key string) (bool, error)
+}
+
+// Engine matches the documents found by watches and dispatches alerts
+type Engine struct {
+	sinks []Sink
+	seen  SeenStore
+}
+
+// Option configures an Engine
+type Option func(*Engine) error
+
+// Sinks adds sinks receiving the alerts of every watch without its own sinks
+func Sinks(sinks ...Sink) Option {
+	return func(e *Engine) error {
+		for _, sink := range sinks {
+			if sink == nil {
+				return errors.New("alert sink must not be nil")
+			}
+		}
+		e.sinks = append(e.sinks, sinks...)
+		return nil
+	}
+}
+
+// Seen sets the store of seen documents, by default the last DefaultSeenCapacity documents are remembered in memory
+func Seen(store SeenStore) Option {
+	return func(e *Engine) error {
+		if store == nil {
+			return errors.New("seen store must not be nil")
+		}
+		e.seen = store
+		return nil
+	}
+}
+
+// New creates an Engine
+func New(opts ...Option) (*Engine, error) {
+	e := &Engine{}
+	for _, opt := range opts {
+		if err := opt(e); err != nil {
+			return nil, err
+		}
+	}
+	if e.seen == nil {
+		e.seen = NewMemorySeen(DefaultSeenCapacity)
+	}
+	return e, nil
+}
+
+// Schedule adds the watch to the scheduler, processing the documents of every run
+func (e *Engine) Schedule(s *scheduler.Scheduler, w Watch) error {
+	if w.Task == nil {
+		return fmt.Errorf("watch %s has no task", w.Name)
+	}
+	if len(w.Sinks) == 0 && len(e.sinks) == 0 {
+		return fmt.Errorf("watch %s has no alert sinks", w.Name)
+	}
+	return s.Add(scheduler.Schedule{
+		Name:   w.Name,
+		Cron:   w.Cron,
+		Every:  w.Every,
+		Jitter: w.Jitter,
+		Task:   w.Task,
+		Sink: scheduler.SinkFunc(func(ctx context.Context, result scheduler.Result) error {
+			_, err := e.Process(ctx, w, result.Docs)
+			return err
+		}),
+	})
+}
+
+// Process skips the documents seen before by the watch, matches the others and sends the alerts of the
+// matching documents to the sinks, returning the alerts
+func (e *Engine) Process(ctx context.Context, w Watch, docs []types.Document) ([]Alert, error) {
+	now := time.Now()
+	var alerts []Alert
+	for _, doc := range docs {
+		seen, err := e.seen.Mark(ctx, seenKey(w.Name, doc))
+		if err != nil {
+			return nil, fmt.Errorf("failed to deduplicate documents of watch %s: %w", w.Name, err)
+		}
+		if seen || (w.Rule != nil && !w.Rule.Match(doc)) {
+			continue
+		}
+		alerts = append(alerts, Alert{Watch: w.Name, Document: doc, MatchedAt: now})
+	}
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+
+	sinks := w.Sinks
+	if len(sinks) == 0 {
+		sinks = e.sinks
+	}
+	var errs []error
+	for _, sink := range sinks {
+		if err := sink.Send(ctx, alerts); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send alerts of watch %s: %w", w.Name, err))
+		}
+	}
+	return alerts, errors.Join(errs...)
+}
+
+// seenKey returns the key of a document seen by a watch, the hash of its content if it has no ID
+func seenKey(watch string, doc types.Document) string {
+	id := doc.Id
+	if id == "" {
+		sum := sha256.Sum256([]byte(doc.Content))
+		id = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return watch + "\x00" + string(doc.Source) + "\x00" + id
+}
+
+// memorySeen is a SeenStore remembering the last keys in memory
+type memorySeen struct {
+	mu       sync.Mutex
+	keys     map[string]struct{}
+	order    []string
+	next     int // Index in order of the oldest key once full
+	capacity int
+}
+
+// NewMemorySeen returns a SeenStore remembering the last capacity keys in memory
+func NewMemorySeen(capacity int) SeenStore {
+	return &memorySeen{keys: make(map[string]struct{}), capacity: max(capacity, 1)}
+}
+
+func (s *memorySeen) Mark(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[key]; ok {
+		return true, nil
+	}
+	if len(s.order) < s.capacity {
+		s.order = append(s.order, key)
+	} else {
+		delete(s.keys, s.order[s.next])
+		s.order[s.next] = key
+		s.next = (s.next + 1) % s.capacity
+	}
+	s.keys[key] = struct{}{}
+	return false, nil
+}
+
+// TwitterQuery returns a Task searching Twitter
+func TwitterQuery(query string) scheduler.Task {
+	args := twitter.NewSearchArguments()
+	args.Query = query
+	return scheduler.Job(types.TwitterJob, args)
+}
+
+// RedditQuery returns a Task searching Reddit posts
+func RedditQuery(query string) scheduler.Task {
+	args := reddit.NewSearchPostsArguments()
+	args.Queries = []string{query}
+	return scheduler.Job(types.RedditJob, args)
+}
+
+// TikTokQuery returns a Task searching TikTok videos
+func TikTokQuery(query string) scheduler.Task {
+	args := tiktok.NewQueryArguments()
+	args.Search = []string{query}
+	return scheduler.Job(types.TiktokJob, args)
+}
+
+// WebPage returns a Task scraping a web page
+func WebPage(url string) scheduler.Task {
+	args := web.NewScraperArguments()
+	args.URL = url
+	return scheduler.Job(types.WebJob, args)
+}
@@ -0,0 +1,13 @@
+package watchlist_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestWatchlist(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Watchlist Suite")
+}
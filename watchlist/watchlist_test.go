@@ -0,0 +1,242 @@
+package watchlist_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gopher-lab/gopher-client/client"
+	"github.com/gopher-lab/gopher-client/scheduler"
+	"github.com/gopher-lab/gopher-client/watchlist"
+	"github.com/masa-finance/tee-worker/v2/api/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// receiver is a local HTTP server recording the JSON bodies POSTed to it
+type receiver struct {
+	*httptest.Server
+	mu     sync.Mutex
+	bodies []map[string]any
+	status int
+}
+
+func newReceiver() *receiver {
+	r := &receiver{status: http.StatusOK}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body map[string]any
+		Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.bodies = append(r.bodies, body)
+		w.WriteHeader(r.status)
+	}))
+	DeferCleanup(r.Close)
+	return r
+}
+
+func (r *receiver) received() []map[string]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]map[string]any(nil), r.bodies...)
+}
+
+var _ = Describe("Engine", func() {
+	var (
+		ctx  context.Context
+		docs []types.Document
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		docs = []types.Document{
+			{Id: "1", Source: types.TwitterSource, Content: "Gopher outage reported", Metadata: map[string]any{"url": "https://x.com/1", "likes": 120.0}},
+			{Id: "2", Source: types.TwitterSource, Content: "Gophers are cute", Metadata: map[string]any{"likes": 500.0}},
+			{Id: "3", Source: types.TwitterSource, Content: "Another outage", Metadata: map[string]any{"likes": 3.0}},
+		}
+	})
+
+	It("should alert on new matching documents only once", func() {
+		var buf bytes.Buffer
+		engine, err := watchlist.New(watchlist.Sinks(watchlist.Writer(&buf)))
+		Expect(err).NotTo(HaveOccurred())
+		watch := watchlist.Watch{Name: "outages", Rule: watchlist.MustParse("outage AND likes >= 100")}
+
+		alerts, err := engine.Process(ctx, watch, docs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(alerts).To(HaveLen(1))
+		Expect(alerts[0].Watch).To(Equal("outages"))
+		Expect(alerts[0].Document.Id).To(Equal("1"))
+		Expect(alerts[0].URL()).To(Equal("https://x.com/1"))
+
+		alerts, err = engine.Process(ctx, watch, docs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(alerts).To(BeEmpty())
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(lines).To(HaveLen(1))
+		var alert watchlist.Alert
+		Expect(json.Unmarshal([]byte(lines[0]), &alert)).To(Succeed())
+		Expect(alert.Document.Content).To(Equal("Gopher outage reported"))
+	})
+
+	It("should deduplicate per watch and by content without IDs", func() {
+		engine, err := watchlist.New(watchlist.Seen(watchlist.NewMemorySeen(10)))
+		Expect(err).NotTo(HaveOccurred())
+		noop := watchlist.SinkFunc(func(ctx context.Context, alerts []watchlist.Alert) error { return nil })
+
+		alerts, err := engine.Process(ctx, watchlist.Watch{Name: "a", Sinks: []watchlist.Sink{noop}}, docs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(alerts).To(HaveLen(3))
+		alerts, err = engine.Process(ctx, watchlist.Watch{Name: "b", Sinks: []watchlist.Sink{noop}}, docs[:1])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(alerts).To(HaveLen(1))
+
+		noID := []types.Document{{Content: "same"}, {Content: "same"}, {Content: "other"}}
+		alerts, err = engine.Process(ctx, watchlist.Watch{Name: "c", Sinks: []watchlist.Sink{noop}}, noID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(alerts).To(HaveLen(2))
+	})
+
+	It("should forget the oldest documents beyond the capacity", func() {
+		seen := watchlist.NewMemorySeen(2)
+		for _, key := range []string{"a", "b", "c"} {
+			Expect(seen.Mark(ctx, key)).To(BeFalse())
+		}
+		Expect(seen.Mark(ctx, "c")).To(BeTrue())
+		Expect(seen.Mark(ctx, "a")).To(BeFalse())
+	})
+
+	It("should POST alerts to webhooks and Slack", func() {
+		webhook := newReceiver()
+		slack := newReceiver()
+		engine, err := watchlist.New(watchlist.Sinks(watchlist.Webhook(webhook.URL, nil), watchlist.Slack(slack.URL, nil)))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = engine.Process(ctx, watchlist.Watch{Name: "outages", Rule: watchlist.Keywords("outage")}, docs)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(webhook.received()).To(HaveLen(1))
+		alerts := webhook.received()[0]["alerts"].([]any)
+		Expect(alerts).To(HaveLen(2))
+		Expect(alerts[0]).To(HaveKeyWithValue("watch", "outages"))
+
+		Expect(slack.received()).To(HaveLen(1))
+		text := slack.received()[0]["text"].(string)
+		Expect(text).To(HavePrefix("*2 new matches for watch outages*"))
+		Expect(text).To(ContainSubstring("<https://x.com/1|twitter> Gopher outage reported"))
+	})
+
+	It("should escape documents in Slack messages", func() {
+		slack := newReceiver()
+		engine, err := watchlist.New(watchlist.Sinks(watchlist.Slack(slack.URL, nil)))
+		Expect(err).NotTo(HaveOccurred())
+
+		docs := []types.Document{
+			{Id: "1", Source: types.TwitterSource, Content: "<!channel> outage & <https://evil.example|click>",
+				Metadata: map[string]any{"url": "https://x.com/1?a=1&b=2"}},
+			{Id: "2", Source: types.TwitterSource, Content: "outage", Metadata: map[string]any{"url": "https://evil.example|x.com"}},
+		}
+		_, err = engine.Process(ctx, watchlist.Watch{Name: "<!here>", Rule: watchlist.Keywords("outage")}, docs)
+		Expect(err).NotTo(HaveOccurred())
+
+		text := slack.received()[0]["text"].(string)
+		Expect(text).To(HavePrefix("*2 new matches for watch &lt;!here&gt;*"))
+		Expect(text).To(ContainSubstring("<https://x.com/1?a=1&amp;b=2|twitter> &lt;!channel&gt; outage &amp; &lt;https://evil.example|click&gt;"))
+		Expect(text).To(ContainSubstring("• twitter outage"))
+		Expect(text).NotTo(ContainSubstring("<!"))
+		Expect(text).NotTo(ContainSubstring("evil.example|x.com"))
+	})
+
+	It("should report failing sinks", func() {
+		webhook := newReceiver()
+		webhook.status = http.StatusInternalServerError
+		failing := watchlist.SinkFunc(func(ctx context.Context, alerts []watchlist.Alert) error { return errors.New("disk full") })
+		engine, err := watchlist.New(watchlist.Sinks(watchlist.Webhook(webhook.URL, nil), failing))
+		Expect(err).NotTo(HaveOccurred())
+
+		alerts, err := engine.Process(ctx, watchlist.Watch{Name: "all"}, docs)
+		Expect(alerts).To(HaveLen(3))
+		Expect(err).To(MatchError(ContainSubstring("returned status code 500")))
+		Expect(err).To(MatchError(ContainSubstring("disk full")))
+	})
+
+	It("should not include webhook URLs in errors", func() {
+		webhook := newReceiver()
+		webhook.status = http.StatusInternalServerError
+		engine, err := watchlist.New(watchlist.Sinks(
+			watchlist.Webhook(webhook.URL+"/hooks/secret-token", nil),
+			watchlist.Slack("http://127.0.0.1:1/services/secret-token", nil),
+		))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = engine.Process(ctx, watchlist.Watch{Name: "all"}, docs)
+		Expect(err).To(MatchError(ContainSubstring("webhook " + webhook.URL + " returned status code 500")))
+		Expect(err).To(MatchError(ContainSubstring("failed to do POST request to webhook http://127.0.0.1:1")))
+		Expect(err.Error()).NotTo(ContainSubstring("secret-token"))
+	})
+
+	It("should append alerts to files", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "alerts.jsonl")
+		engine, err := watchlist.New(watchlist.Sinks(watchlist.File(path)))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = engine.Process(ctx, watchlist.Watch{Name: "all"}, docs[:2])
+		Expect(err).NotTo(HaveOccurred())
+		_, err = engine.Process(ctx, watchlist.Watch{Name: "all"}, docs)
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Split(strings.TrimSpace(string(data)), "\n")).To(HaveLen(3))
+	})
+
+	It("should run scheduled watches", func() {
+		webhook := newReceiver()
+		engine, err := watchlist.New(watchlist.Sinks(watchlist.Webhook(webhook.URL, nil)))
+		Expect(err).NotTo(HaveOccurred())
+		s, err := scheduler.New(client.NewClient("http://localhost", "test-token"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(engine.Schedule(s, watchlist.Watch{
+			Name:  "outages",
+			Every: 10 * time.Millisecond,
+			Rule:  watchlist.Keywords("outage"),
+			Task: func(ctx context.Context, c *client.Client) ([]types.Document, error) {
+				return docs, nil
+			},
+		})).To(Succeed())
+		Expect(engine.Schedule(s, watchlist.Watch{Name: "no-task", Every: time.Minute})).To(MatchError(ContainSubstring("no task")))
+
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = s.Run(runCtx)
+		}()
+		Eventually(func() int {
+			status, _ := s.Status("outages")
+			return status.Runs
+		}).Should(BeNumerically(">=", 3))
+		cancel()
+		Eventually(done).Should(BeClosed())
+
+		Expect(webhook.received()).To(HaveLen(1))
+	})
+
+	It("should require sinks to schedule watches", func() {
+		engine, err := watchlist.New()
+		Expect(err).NotTo(HaveOccurred())
+		s, err := scheduler.New(client.NewClient("http://localhost", "test-token"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(engine.Schedule(s, watchlist.Watch{Name: "a", Every: time.Minute, Task: watchlist.TwitterQuery("gopher")})).To(MatchError(ContainSubstring("no alert sinks")))
+	})
+})